@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"system-design-game/internal/application/usecase"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/infrastructure/persistence"
+	"time"
+)
+
+// app 組裝 CLI 所需的依賴 (與 Server / Wasm 相同的 Clean Architecture 分層)
+type app struct {
+	designUC   *usecase.DesignUseCase
+	evalUC     *usecase.EvaluationUseCase
+	analysisUC *usecase.AnalysisUseCase
+}
+
+func newApp() *app {
+	// 基礎設施層
+	designRepo := persistence.NewInMemDesignRepository()
+	scenarioRepo := persistence.NewInMemScenarioRepository()
+	catalogRepo := persistence.NewInMemCatalogRepository()
+
+	// 領域層
+	clock := time.Now // 引擎與設計圖共用同一個時鐘
+	evalEngine := engine.NewSimpleEngine(designRepo, scenarioRepo, catalogRepo, clock)
+
+	// 應用層
+	return &app{
+		designUC:   usecase.NewDesignUseCase(designRepo, catalogRepo, clock),
+		evalUC:     usecase.NewEvaluationUseCase(evalEngine),
+		analysisUC: usecase.NewAnalysisUseCase(designRepo, evalEngine),
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	a := newApp()
+	var err error
+	switch os.Args[1] {
+	case "capacity":
+		err = a.capacity(os.Args[2:])
+	case "lint":
+		err = a.lint(os.Args[2:])
+	case "replay":
+		err = a.replay(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("執行失敗: %v", err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `用法: cli <指令> [參數]
+
+指令:
+  capacity   搜尋設計圖能持續承受的最大 QPS
+  lint       以靜態架構規則檢查設計圖
+  replay     依執行紀錄重播模擬，確認能否重現相同的結果`)
+}
+
+// capacity 搜尋設計圖的容量極限
+func (a *app) capacity(args []string) error {
+	fs := flag.NewFlagSet("capacity", flag.ExitOnError)
+	designPath := fs.String("design", "", "設計圖 JSON 檔案路徑")
+	minFulfillment := fs.Float64("min-fulfillment", 0.95, "判定為可持續的最低資料獲取率")
+	maxQPS := fs.Int64("max-qps", 10_000_000, "搜尋上限 QPS")
+	componentsPath := fs.String("components", "", "自訂組件定義的檔案或目錄 (JSON/YAML)")
+	fs.Parse(args)
+
+	if err := loadComponents(*componentsPath); err != nil {
+		return err
+	}
+	d, err := a.loadDesign(*designPath)
+	if err != nil {
+		return err
+	}
+
+	report, err := a.evalUC.CapacityLimit(d.ID, engine.CapacityOptions{
+		MinFulfillment: *minFulfillment,
+		MaxQPS:         *maxQPS,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
+
+// lint 以靜態架構規則檢查設計圖
+func (a *app) lint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	designPath := fs.String("design", "", "設計圖 JSON 檔案路徑")
+	componentsPath := fs.String("components", "", "自訂組件定義的檔案或目錄 (JSON/YAML)")
+	fs.Parse(args)
+
+	if err := loadComponents(*componentsPath); err != nil {
+		return err
+	}
+
+	d, err := a.loadDesign(*designPath)
+	if err != nil {
+		return err
+	}
+
+	issues, err := a.analysisUC.Lint(d.ID)
+	if err != nil {
+		return err
+	}
+	return printJSON(issues)
+}
+
+// replay 依執行紀錄 (GET /sessions/:id/log 下載的 JSON) 重播模擬
+func (a *app) replay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	logPath := fs.String("log", "", "執行紀錄 JSON 檔案路徑")
+	results := fs.Bool("results", false, "輸出每個 tick 的完整評估結果")
+	componentsPath := fs.String("components", "", "自訂組件定義的檔案或目錄 (JSON/YAML)")
+	fs.Parse(args)
+
+	if err := loadComponents(*componentsPath); err != nil {
+		return err
+	}
+	if *logPath == "" {
+		return fmt.Errorf("需要指定 -log")
+	}
+	data, err := os.ReadFile(*logPath)
+	if err != nil {
+		return err
+	}
+	var runLog engine.RunLog
+	if err := json.Unmarshal(data, &runLog); err != nil {
+		return fmt.Errorf("解析執行紀錄失敗: %w", err)
+	}
+
+	report, err := a.evalUC.Replay(&runLog)
+	if err != nil {
+		return err
+	}
+	if !*results {
+		report.Results = nil
+	}
+	return printJSON(report)
+}
+
+// loadComponents 讀取並註冊自訂組件定義，未指定路徑時略過
+func loadComponents(path string) error {
+	if path == "" {
+		return nil
+	}
+	defs, err := persistence.LoadComponentDefinitions(path)
+	if err != nil {
+		return err
+	}
+	return engine.RegisterDefinitions(defs...)
+}
+
+// loadDesign 從 JSON 檔案讀取設計圖並存入 Repository
+func (a *app) loadDesign(path string) (*design.Design, error) {
+	if path == "" {
+		return nil, fmt.Errorf("需要指定 -design")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var d design.Design
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("解析設計圖失敗: %w", err)
+	}
+	if d.ID == "" {
+		d.ID = "cli-design"
+	}
+	if err := a.designUC.SaveDesign(&d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
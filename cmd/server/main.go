@@ -1,32 +1,89 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"path/filepath"
+	"strings"
 	"system-design-game/internal/application/usecase"
+	"system-design-game/internal/domain/design"
 	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/leaderboard"
+	"system-design-game/internal/domain/scenario"
+	"system-design-game/internal/domain/world"
 	apphttp "system-design-game/internal/handler/http"
+	"system-design-game/internal/i18n"
 	"system-design-game/internal/infrastructure/persistence"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	componentsPath := flag.String("components", "", "自訂組件定義的檔案或目錄 (JSON/YAML)")
+	store := flag.String("store", "memory", "資料儲存方式：memory (重啟後清空) 或 file (JSON 檔)")
+	dataDir := flag.String("data", "./data", "store=file 時的資料目錄")
+	scenariosPath := flag.String("scenarios", "", "關卡定義的檔案或目錄 (JSON/YAML)，載入後新增或覆寫同 ID 的關卡")
+	origins := flag.String("origins", "", "除了同源之外允許連線 WebSocket 的來源，以逗號分隔 (如 https://game.example.com)")
+	flag.Parse()
+
+	// 自訂組件：講師可以不重新編譯就新增組件類型
+	if *componentsPath != "" {
+		defs, err := persistence.LoadComponentDefinitions(*componentsPath)
+		if err != nil {
+			log.Fatalf("無法載入自訂組件: %v", err)
+		}
+		if err := engine.RegisterDefinitions(defs...); err != nil {
+			log.Fatalf("無法註冊自訂組件: %v", err)
+		}
+		log.Printf("已載入 %d 個自訂組件類型", len(defs))
+	}
+
 	// 基礎設施層 (Infrastructure Layer)
-	designRepo := persistence.NewInMemDesignRepository()
-	scenarioRepo := persistence.NewInMemScenarioRepository()
+	repos, err := openRepositories(*store, *dataDir)
+	if err != nil {
+		log.Fatalf("無法開啟資料儲存 (%s): %v", *store, err)
+	}
+	designRepo, scenarioRepo := repos.design, repos.scenario
+	catalogRepo := persistence.NewInMemCatalogRepository()
 
 	// 領域層 (Domain Layer) - 領域服務
-	evalEngine := engine.NewSimpleEngine(designRepo, scenarioRepo)
+	// 引擎、設計圖與每日挑戰種子共用同一個時鐘，跨過 UTC 午夜時才不會對「今天」有不同的認定
+	clock := time.Now
+	evalEngine := engine.NewSimpleEngine(designRepo, scenarioRepo, catalogRepo, clock)
 
 	// 應用層 (Application Layer) - 用例 (Use Cases)
-	designUC := usecase.NewDesignUseCase(designRepo)
+	designUC := usecase.NewDesignUseCase(designRepo, catalogRepo, clock)
 	scenarioUC := usecase.NewScenarioUseCase(scenarioRepo)
+	catalogUC := usecase.NewCatalogUseCase(catalogRepo)
 	evalUC := usecase.NewEvaluationUseCase(evalEngine)
+	analysisUC := usecase.NewAnalysisUseCase(designRepo, evalEngine)
+	sessionUC := usecase.NewSessionUseCase(evalEngine, repos.world)
+	leaderboardUC := usecase.NewLeaderboardUseCase(repos.leaderboard, scenarioRepo, evalEngine)
+
+	// 關卡：講師可以用 YAML/JSON 檔撰寫新挑戰
+	if *scenariosPath != "" {
+		scenarios, err := persistence.LoadScenarios(*scenariosPath)
+		if err != nil {
+			log.Fatalf("無法載入關卡: %v", err)
+		}
+		for _, s := range scenarios {
+			if err := scenarioUC.SaveScenario(s); err != nil {
+				log.Fatalf("無法儲存關卡 %s: %v", s.ID, err)
+			}
+		}
+		log.Printf("已載入 %d 個關卡", len(scenarios))
+	}
 
 	// 介面層 (Interfaces / Presenters) - Handlers
 	designHandler := apphttp.NewDesignHandler(designUC, evalUC)
 	scenarioHandler := apphttp.NewScenarioHandler(scenarioUC)
+	catalogHandler := apphttp.NewCatalogHandler(catalogUC)
+	analysisHandler := apphttp.NewAnalysisHandler(analysisUC)
+	sessionHandler := apphttp.NewSessionHandler(sessionUC, evalUC, splitList(*origins)...)
+	leaderboardHandler := apphttp.NewLeaderboardHandler(leaderboardUC)
 
 	r := gin.Default()
 
@@ -34,17 +91,104 @@ func main() {
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "ok",
-			"message": "系統設計遊戲後端服務已啟動 (單人模式)",
+			"message": i18n.T(apphttp.Locale(c), "http.health", nil),
 		})
 	})
 
+	// 每日挑戰種子：同一天的所有玩家會遇到相同的隨機事件序列
+	r.GET("/daily-seed", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"seed": designUC.DailySeed()})
+	})
+
 	// 註冊處理程序 (Handlers)
 	r.POST("/evaluate/:design_id", designHandler.Evaluate)
+	r.POST("/evaluate/:design_id/montecarlo", designHandler.MonteCarlo)
+	r.POST("/compare", designHandler.Compare)
+	r.POST("/capacity/:design_id", designHandler.CapacityLimit)
+	r.POST("/verify", designHandler.Verify)
+	r.GET("/analyze/:design_id", analysisHandler.Analyze)
+	r.GET("/lint/rules", analysisHandler.LintRules)
+	r.GET("/lint/:design_id", analysisHandler.Lint)
+	r.POST("/lint", analysisHandler.LintDesign)
 	r.GET("/scenarios", scenarioHandler.List)
+	r.GET("/scenarios/:id", scenarioHandler.Get)
+	r.POST("/scenarios", scenarioHandler.Create)
+	r.PUT("/scenarios/:id", scenarioHandler.Update)
+	r.GET("/scenarios/:id/leaderboard", leaderboardHandler.List)
+	r.POST("/scenarios/:id/leaderboard", leaderboardHandler.Submit)
+	r.GET("/components", catalogHandler.List)
 	r.POST("/design", designHandler.Save)
+	r.GET("/design/:id", designHandler.Get)
+	r.PUT("/design/:id", designHandler.Update)
+	r.DELETE("/design/:id", designHandler.Delete)
+	r.GET("/players/:id/designs", designHandler.ListByPlayer)
+	r.GET("/players/:id/state", sessionHandler.GameState)
+	r.GET("/schemas", designHandler.Schemas)
+
+	// 即時模擬場次：伺服器依自己的時鐘推進，透過 WebSocket 推送每個 tick 並接收控制指令
+	r.POST("/sessions", sessionHandler.Start)
+	r.GET("/sessions/:id", sessionHandler.Get)
+	r.DELETE("/sessions/:id", sessionHandler.Stop)
+	r.POST("/sessions/:id/control", sessionHandler.Control)
+	r.GET("/sessions/:id/ws", sessionHandler.Stream)
+	r.GET("/sessions/:id/log", sessionHandler.RunLog)
+	r.GET("/sessions/:id/costs", sessionHandler.Costs)
+	r.POST("/replay", sessionHandler.Replay)
 
 	log.Println("伺服器運行在 :8080...")
 	if err := r.Run(":8080"); err != nil {
 		log.Fatalf("無法啟動伺服器: %v", err)
 	}
 }
+
+// repositories 是伺服器使用的持久化實作
+type repositories struct {
+	design      design.Repository
+	scenario    scenario.Repository
+	world       world.Repository
+	leaderboard leaderboard.Repository
+}
+
+// openRepositories 依啟動參數選擇持久化實作：memory 或 file (每筆資料一個 JSON 檔，放在 dataDir 下)
+func openRepositories(store, dataDir string) (*repositories, error) {
+	switch store {
+	case "memory":
+		return &repositories{
+			design:      persistence.NewInMemDesignRepository(),
+			scenario:    persistence.NewInMemScenarioRepository(),
+			world:       persistence.NewInMemWorldRepository(),
+			leaderboard: persistence.NewInMemLeaderboardRepository(),
+		}, nil
+	case "file":
+		designRepo, err := persistence.NewFileDesignRepository(filepath.Join(dataDir, "designs"))
+		if err != nil {
+			return nil, err
+		}
+		scenarioRepo, err := persistence.NewFileScenarioRepository(filepath.Join(dataDir, "scenarios"))
+		if err != nil {
+			return nil, err
+		}
+		worldRepo, err := persistence.NewFileWorldRepository(filepath.Join(dataDir, "worlds"))
+		if err != nil {
+			return nil, err
+		}
+		leaderboardRepo, err := persistence.NewFileLeaderboardRepository(filepath.Join(dataDir, "leaderboards"))
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("資料儲存於 %s", dataDir)
+		return &repositories{design: designRepo, scenario: scenarioRepo, world: worldRepo, leaderboard: leaderboardRepo}, nil
+	}
+	return nil, fmt.Errorf("不支援的 store: %s", store)
+}
+
+// splitList 將逗號分隔的參數拆成清單，略過空白項目
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
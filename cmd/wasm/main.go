@@ -3,38 +3,61 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"syscall/js"
 	"system-design-game/internal/application/usecase"
+	"system-design-game/internal/domain/catalog"
+	"system-design-game/internal/domain/component"
 	"system-design-game/internal/domain/design"
 	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/lint"
+	"system-design-game/internal/i18n"
 	"system-design-game/internal/infrastructure/persistence"
+	"time"
 )
 
 var (
 	designUC   *usecase.DesignUseCase
 	scenarioUC *usecase.ScenarioUseCase
+	catalogUC  *usecase.CatalogUseCase
 	evalUC     *usecase.EvaluationUseCase
+	analysisUC *usecase.AnalysisUseCase
 )
 
 func main() {
 	// 基礎設施層
 	designRepo := persistence.NewInMemDesignRepository()
 	scenarioRepo := persistence.NewInMemScenarioRepository()
+	catalogRepo := persistence.NewInMemCatalogRepository()
 
 	// 領域層
-	evalEngine := engine.NewSimpleEngine(designRepo, scenarioRepo)
+	// 引擎、設計圖與每日挑戰種子共用同一個時鐘
+	clock := time.Now
+	evalEngine := engine.NewSimpleEngine(designRepo, scenarioRepo, catalogRepo, clock)
 
 	// 應用層
-	designUC = usecase.NewDesignUseCase(designRepo)
+	designUC = usecase.NewDesignUseCase(designRepo, catalogRepo, clock)
 	scenarioUC = usecase.NewScenarioUseCase(scenarioRepo)
+	catalogUC = usecase.NewCatalogUseCase(catalogRepo)
 	evalUC = usecase.NewEvaluationUseCase(evalEngine)
+	analysisUC = usecase.NewAnalysisUseCase(designRepo, evalEngine)
 
 	// 暴露函數給 JavaScript
 	js.Global().Set("goEvaluate", js.FuncOf(evaluate))
 	js.Global().Set("goSaveDesign", js.FuncOf(saveDesign))
 	js.Global().Set("goListScenarios", js.FuncOf(listScenarios))
+	js.Global().Set("goListComponents", js.FuncOf(listComponents))
+	js.Global().Set("goDailySeed", js.FuncOf(dailySeed))
+	js.Global().Set("goLeaderboardSeed", js.FuncOf(leaderboardSeed))
+	js.Global().Set("goMonteCarlo", js.FuncOf(monteCarlo))
+	js.Global().Set("goCapacityLimit", js.FuncOf(capacityLimit))
+	js.Global().Set("goAnalyze", js.FuncOf(analyze))
+	js.Global().Set("goLintDesign", js.FuncOf(lintDesign))
+	js.Global().Set("goPropertySchemas", js.FuncOf(propertySchemas))
 
 	fmt.Println("Wasm 模組已載入 (Clean Architecture 模式)")
 
@@ -53,17 +76,104 @@ func evaluate(this js.Value, args []js.Value) interface{} {
 		elapsed = int64(args[1].Int())
 	}
 
-	// 透過 UseCase 進行評估
-	res, err := evalUC.Evaluate(designID, elapsed)
+	// 透過 UseCase 進行評估，第 4 個參數為 "strict" 時以組件目錄決定規格
+	evaluate := evalUC.Evaluate
+	if len(args) > 3 && args[3].Type() == js.TypeString && evaluation.Mode(args[3].String()) == evaluation.ModeStrict {
+		evaluate = evalUC.EvaluateStrict
+	}
+	res, err := evaluate(designID, elapsed)
 	if err != nil {
 		fmt.Printf("評估失敗: %v\n", err)
 		return err.Error()
 	}
+	res.Localize(localeArg(args, 2))
 
 	jsonRes, _ := json.Marshal(res)
 	return string(jsonRes)
 }
 
+func monteCarlo(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return "需要 Design ID"
+	}
+
+	var opts engine.MonteCarloOptions
+	if len(args) > 1 {
+		opts.Runs = args[1].Int()
+		// 與 HTTP 相同的上限：Wasm 在瀏覽器的主執行緒上同步執行，次數過多會讓畫面卡住
+		if opts.Runs < 0 || opts.Runs > engine.MaxMonteCarloRuns {
+			return "蒙地卡羅評估失敗: " + i18n.T(i18n.DefaultLocale, "error.invalid_runs", nil)
+		}
+	}
+	if len(args) > 2 {
+		opts.Seed = int64(args[2].Float())
+	}
+
+	report, err := evalUC.MonteCarlo(context.Background(), args[0].String(), opts)
+	if err != nil {
+		return "蒙地卡羅評估失敗: " + err.Error()
+	}
+
+	jsonRes, _ := json.Marshal(report)
+	return string(jsonRes)
+}
+
+func capacityLimit(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return "需要 Design ID"
+	}
+
+	var opts engine.CapacityOptions
+	if len(args) > 1 {
+		opts.MinFulfillment = args[1].Float()
+	}
+
+	report, err := evalUC.CapacityLimit(args[0].String(), opts)
+	if err != nil {
+		return "容量搜尋失敗: " + err.Error()
+	}
+
+	jsonRes, _ := json.Marshal(report)
+	return string(jsonRes)
+}
+
+func analyze(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return "需要 Design ID"
+	}
+
+	elapsed := int64(0)
+	if len(args) > 1 {
+		elapsed = int64(args[1].Int())
+	}
+
+	report, err := analysisUC.Analyze(args[0].String(), elapsed)
+	if err != nil {
+		return "分析失敗: " + err.Error()
+	}
+	report.Localize(localeArg(args, 2))
+
+	jsonRes, _ := json.Marshal(report)
+	return string(jsonRes)
+}
+
+func lintDesign(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return "需要 Design JSON"
+	}
+
+	var d design.Design
+	if err := json.Unmarshal([]byte(args[0].String()), &d); err != nil {
+		return "解析 JSON 失敗: " + err.Error()
+	}
+
+	issues := analysisUC.LintDesign(&d)
+	lint.Localize(issues, localeArg(args, 1))
+
+	jsonRes, _ := json.Marshal(issues)
+	return string(jsonRes)
+}
+
 func saveDesign(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return "需要 Design JSON"
@@ -76,8 +186,16 @@ func saveDesign(this js.Value, args []js.Value) interface{} {
 		return "解析 JSON 失敗: " + err.Error()
 	}
 
-	// 透過 UseCase 儲存設計
+	// 透過 UseCase 儲存設計，屬性驗證失敗時依語系 (第 2 個參數) 產生錯誤訊息
 	err = designUC.SaveDesign(&d)
+	var skuErr *catalog.SKUError
+	if errors.As(err, &skuErr) {
+		return "儲存失敗: " + i18n.T(localeArg(args, 1), skuErr.MessageID, skuErr.Params())
+	}
+	var ve *component.ValidationError
+	if errors.As(err, &ve) {
+		ve.Localize(localeArg(args, 1))
+	}
 	if err != nil {
 		return "儲存失敗: " + err.Error()
 	}
@@ -91,7 +209,59 @@ func listScenarios(this js.Value, args []js.Value) interface{} {
 	if err != nil {
 		return "取得關卡失敗: " + err.Error()
 	}
+	l := localeArg(args, 0)
+	for i, s := range scenarios {
+		scenarios[i] = s.Localized(l)
+	}
 
 	jsonRes, _ := json.Marshal(scenarios)
 	return string(jsonRes)
 }
+
+func listComponents(this js.Value, args []js.Value) interface{} {
+	// 透過 UseCase 取得組件目錄
+	skus, err := catalogUC.ListComponents()
+	if err != nil {
+		return "取得組件目錄失敗: " + err.Error()
+	}
+	l := localeArg(args, 0)
+	for i, s := range skus {
+		skus[i] = s.Localized(l)
+	}
+
+	jsonRes, _ := json.Marshal(skus)
+	return string(jsonRes)
+}
+
+func dailySeed(this js.Value, args []js.Value) interface{} {
+	// 回傳今日挑戰的種子，前端可將其寫入 design.properties.seed 以重現同一場挑戰
+	return float64(designUC.DailySeed())
+}
+
+func leaderboardSeed(this js.Value, args []js.Value) interface{} {
+	// 回傳關卡排行榜固定使用的種子，要提交到排行榜的遊戲必須以此種子、每個 tick 1 秒模擬
+	if len(args) < 1 {
+		return "需要 Scenario ID"
+	}
+	return float64(engine.LeaderboardSeed(args[0].String()))
+}
+
+// localeArg 讀取第 i 個參數作為語系 (如 "en")，未提供時使用預設語系
+func localeArg(args []js.Value, i int) i18n.Locale {
+	if len(args) > i && args[i].Type() == js.TypeString {
+		return i18n.Parse(args[i].String())
+	}
+	return i18n.DefaultLocale
+}
+
+func propertySchemas(this js.Value, args []js.Value) interface{} {
+	l := localeArg(args, 0)
+	schemas := designUC.PropertySchemas()
+	for i, s := range schemas.Components {
+		schemas.Components[i] = s.Localized(l)
+	}
+	schemas.Design = schemas.Design.Localized(l)
+
+	jsonRes, _ := json.Marshal(schemas)
+	return string(jsonRes)
+}
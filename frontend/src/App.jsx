@@ -483,6 +483,7 @@ function Game() {
   const [logs, setLogs] = useState([]);
   const crashedSet = useRef(new Set());
   const asgScaleRef = useRef({});
+  const experienceRef = useRef({}); // 最近幾個 tick 的延遲與錯誤率，每次評估時帶回 Wasm 判斷使用者體驗
   const terminalEndRef = useRef(null);
 
   const addLog = useCallback((msg, type = 'info') => {
@@ -539,6 +540,7 @@ function Game() {
     setIsAutoEvaluating(false);
     setEvaluationResult(null);
     setRetentionRate(1.0);
+    experienceRef.current = {};
     setNodes((nds) => nds.map(node => ({
       ...node,
       data: {
@@ -773,7 +775,7 @@ function Game() {
         to_id: e.target,
         protocol: "HTTP"
       })),
-      properties: { retention_rate: retentionRate }
+      properties: { ...experienceRef.current, retention_rate: retentionRate }
     };
 
     // 同步到 Wasm
@@ -785,18 +787,14 @@ function Game() {
       const res = JSON.parse(resultStr);
       setEvaluationResult(res);
 
-      // 使用者留存率衰減與恢復邏輯
-      setRetentionRate(prev => {
-        let next = prev;
-        if (res.total_score < 95) {
-          // 系統健康度低於 95%，使用者開始流失 (-0.5% / sec)
-          next -= 0.005;
-        } else {
-          // 系統健康度恢復，使用者慢慢回歸 (+0.2% / sec)
-          next += 0.002;
-        }
-        return Math.min(1.0, Math.max(0.1, next));
-      });
+      // 使用者留存率：依最近的 p95 延遲與錯誤率 (由 Wasm 依關卡目標評估) 流失或自然成長
+      if (res.experience) {
+        experienceRef.current = {
+          recent_latencies: res.experience.recent_latencies,
+          recent_error_rates: res.experience.recent_error_rates,
+        };
+        setRetentionRate(res.experience.next_retention_rate);
+      }
 
       // 動態更新連線動畫：只要系統健康度大於 0 且正在模擬就讓它流動
       const isActive = res.total_score > 0 && isAutoEvaluating;
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"system-design-game/internal/domain/analysis"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/lint"
+)
+
+// AnalysisUseCase 處理設計圖的瓶頸與根因分析
+type AnalysisUseCase struct {
+	designRepo design.Repository
+	engine     engine.Engine
+	linter     *lint.Linter
+}
+
+func NewAnalysisUseCase(repo design.Repository, e engine.Engine) *AnalysisUseCase {
+	return &AnalysisUseCase{designRepo: repo, engine: e, linter: lint.NewLinter()}
+}
+
+// Analyze 評估設計在指定秒數的狀態，並產生瓶頸與崩潰根因報告
+func (uc *AnalysisUseCase) Analyze(designID string, elapsedSeconds int64) (*analysis.Report, error) {
+	d, err := uc.designRepo.GetByID(designID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := uc.engine.Evaluate(designID, elapsedSeconds)
+	if err != nil {
+		return nil, err
+	}
+	return analysis.Analyze(d, res), nil
+}
+
+// Lint 以靜態架構規則檢查已儲存的設計圖 (不執行模擬)
+func (uc *AnalysisUseCase) Lint(designID string) ([]lint.Issue, error) {
+	d, err := uc.designRepo.GetByID(designID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.linter.Lint(d), nil
+}
+
+// LintDesign 以靜態架構規則檢查尚未儲存的設計圖
+func (uc *AnalysisUseCase) LintDesign(d *design.Design) []lint.Issue {
+	return uc.linter.Lint(d)
+}
+
+// LintRules 列出所有架構規則
+func (uc *AnalysisUseCase) LintRules() []lint.Rule {
+	return uc.linter.Rules()
+}
@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"system-design-game/internal/domain/catalog"
+)
+
+// CatalogUseCase 處理組件目錄相關的業務流程
+type CatalogUseCase struct {
+	repo catalog.Repository
+}
+
+func NewCatalogUseCase(repo catalog.Repository) *CatalogUseCase {
+	return &CatalogUseCase{repo: repo}
+}
+
+// ListComponents 列出所有可購買的組件規格
+func (uc *CatalogUseCase) ListComponents() ([]*catalog.SKU, error) {
+	return uc.repo.ListAll()
+}
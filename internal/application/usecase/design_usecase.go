@@ -1,24 +1,97 @@
 package usecase
 
 import (
+	"system-design-game/internal/domain/catalog"
+	"system-design-game/internal/domain/component"
 	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"time"
 )
 
 // DesignUseCase 處理與設計圖相關的業務流程
 type DesignUseCase struct {
-	repo design.Repository
+	repo    design.Repository
+	catalog catalog.Repository
+	clock   func() time.Time // 與引擎共用的時鐘，決定每日挑戰的日期
 }
 
-func NewDesignUseCase(repo design.Repository) *DesignUseCase {
-	return &DesignUseCase{repo: repo}
+func NewDesignUseCase(repo design.Repository, catalogRepo catalog.Repository, clock func() time.Time) *DesignUseCase {
+	return &DesignUseCase{repo: repo, catalog: catalogRepo, clock: clock}
+}
+
+// DailySeed 依時鐘回傳今天的每日挑戰種子
+func (uc *DesignUseCase) DailySeed() int64 {
+	return engine.DailySeed(uc.clock())
+}
+
+// PropertySchemas 是所有屬性定義，供前端產生屬性編輯器
+type PropertySchemas struct {
+	Components []component.Schema `json:"components"`
+	Design     component.Schema   `json:"design"`
 }
 
 // SaveDesign 儲存玩家的設計
+// 引用 SKU 的組件以目錄的成本與容量覆寫 (SKU 不存在或類型不符時回傳 *catalog.SKUError)，
+// 屬性不符合定義時回傳 *component.ValidationError
 func (uc *DesignUseCase) SaveDesign(d *design.Design) error {
-	// TODO: 可以在這裡加入驗證邏輯，例如檢查組件連線是否合法
+	// TODO: 可以在這裡加入連線驗證，例如檢查組件連線是否合法
+	if err := catalog.Resolve(d, uc.catalog); err != nil {
+		return err
+	}
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	now := uc.clock()
+	pinDailySeed(d, now)
+	if d.CreatedAt == 0 {
+		d.CreatedAt = now.Unix()
+	}
+	d.UpdatedAt = now.Unix()
 	return uc.repo.Save(d)
 }
 
+// pinDailySeed 在儲存每日挑戰的設計圖時寫入當天的種子，之後逐 tick 的評估都沿用同一個種子，
+// 不會因為模擬跨過 UTC 午夜而換成另一天的事件序列 (設計圖已指定 seed 時不變)
+func pinDailySeed(d *design.Design, now time.Time) {
+	if !d.Properties.Enabled("daily_challenge") {
+		return
+	}
+	if _, ok := d.Properties.Int("seed"); ok {
+		return
+	}
+	d.Properties["seed"] = float64(engine.DailySeed(now))
+}
+
+// UpdateDesign 以新的內容覆寫既有的設計圖，設計圖不存在時回傳 design.ErrNotFound
+// 建立時間沿用原本的值，未指定玩家時沿用原本的玩家
+func (uc *DesignUseCase) UpdateDesign(id string, d *design.Design) error {
+	existing, err := uc.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	d.ID = id
+	d.CreatedAt = existing.CreatedAt
+	if d.PlayerID == "" {
+		d.PlayerID = existing.PlayerID
+	}
+	return uc.SaveDesign(d)
+}
+
+// DeleteDesign 刪除設計圖，設計圖不存在時回傳 design.ErrNotFound
+func (uc *DesignUseCase) DeleteDesign(id string) error {
+	return uc.repo.Delete(id)
+}
+
+// ListDesigns 列出玩家的所有設計圖
+func (uc *DesignUseCase) ListDesigns(playerID string) ([]*design.Design, error) {
+	return uc.repo.ListByPlayerID(playerID)
+}
+
+// PropertySchemas 回傳組件類型與設計圖全域屬性的定義
+func (uc *DesignUseCase) PropertySchemas() PropertySchemas {
+	return PropertySchemas{Components: component.Schemas(), Design: design.PropertySchema}
+}
+
 // GetDesign 獲取指定的設計圖
 func (uc *DesignUseCase) GetDesign(id string) (*design.Design, error) {
 	return uc.repo.GetByID(id)
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/infrastructure/persistence"
+	"testing"
+	"time"
+)
+
+// 儲存每日挑戰的設計圖時，依注入的時鐘寫入當天的種子，與 DailySeed 的結果一致
+func TestSaveDesignPinsDailySeedFromClock(t *testing.T) {
+	now := time.Date(2026, 3, 1, 23, 59, 59, 0, time.UTC)
+	uc := NewDesignUseCase(persistence.NewInMemDesignRepository(), persistence.NewInMemCatalogRepository(), func() time.Time { return now })
+
+	d := &design.Design{ID: "daily", ScenarioID: "tinyurl", Properties: component.Metadata{"daily_challenge": true}}
+	if err := uc.SaveDesign(d); err != nil {
+		t.Fatal(err)
+	}
+	seed, _ := d.Properties.Int("seed")
+	if want := engine.DailySeed(now); seed != want || uc.DailySeed() != want {
+		t.Errorf("pinned seed %d, DailySeed %d; want %d", seed, uc.DailySeed(), want)
+	}
+
+	// 已指定的種子不會在午夜之後被換掉
+	now = now.Add(time.Second)
+	if err := uc.SaveDesign(d); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := d.Properties.Int("seed"); again != seed {
+		t.Errorf("seed changed to %d after midnight, want %d", again, seed)
+	}
+	if uc.DailySeed() == seed {
+		t.Error("DailySeed should follow the clock to the next day")
+	}
+}
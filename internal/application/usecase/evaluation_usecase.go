@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"system-design-game/internal/domain/engine"
 	"system-design-game/internal/domain/evaluation"
 )
@@ -19,3 +20,43 @@ func (uc *EvaluationUseCase) Evaluate(designID string, elapsedSeconds int64) (*e
 	// 這裡可以加入一些應用層的邏輯，例如紀錄評估次數或暫存結果
 	return uc.engine.Evaluate(designID, elapsedSeconds)
 }
+
+// EvaluateStrict 以嚴格模式評估 (排行榜與評分使用)，組件規格由伺服器端目錄決定
+func (uc *EvaluationUseCase) EvaluateStrict(designID string, elapsedSeconds int64) (*evaluation.Result, error) {
+	return uc.engine.EvaluateStrict(designID, elapsedSeconds)
+}
+
+// MonteCarlo 以多次隨機模擬評估設計的穩健度，ctx 被取消時停止模擬
+func (uc *EvaluationUseCase) MonteCarlo(ctx context.Context, designID string, opts engine.MonteCarloOptions) (*evaluation.MonteCarloReport, error) {
+	return uc.engine.MonteCarlo(ctx, designID, opts)
+}
+
+// Compare 以相同的種子與參數分別評估兩個設計，並比較其統計差異
+func (uc *EvaluationUseCase) Compare(ctx context.Context, designA, designB string, opts engine.MonteCarloOptions) (*evaluation.Comparison, error) {
+	a, err := uc.engine.MonteCarlo(ctx, designA, opts)
+	if err != nil {
+		return nil, err
+	}
+	// 使用 A 實際採用的種子，讓兩個設計面對完全相同的事件序列
+	opts.Seed = a.Seed
+	b, err := uc.engine.MonteCarlo(ctx, designB, opts)
+	if err != nil {
+		return nil, err
+	}
+	return engine.CompareMonteCarlo(a, b), nil
+}
+
+// CapacityLimit 搜尋設計在穩定流量下能持續承受的最大 QPS
+func (uc *EvaluationUseCase) CapacityLimit(designID string, opts engine.CapacityOptions) (*evaluation.CapacityReport, error) {
+	return uc.engine.CapacityLimit(designID, opts)
+}
+
+// Replay 依執行紀錄重播一場模擬，確認能否重現相同的評估結果
+func (uc *EvaluationUseCase) Replay(log *engine.RunLog) (*evaluation.ReplayReport, error) {
+	return uc.engine.Replay(log)
+}
+
+// Verify 以伺服器的引擎與目錄重新模擬客戶端提交的遊戲，回傳權威成績 (競賽與排行榜以此為準)
+func (uc *EvaluationUseCase) Verify(req engine.VerificationRequest) (*evaluation.VerificationReport, error) {
+	return uc.engine.Verify(req)
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/leaderboard"
+	"system-design-game/internal/domain/scenario"
+	"time"
+)
+
+// ErrMissingPlayer 表示提交的設計圖沒有 player_id，無法記錄到排行榜
+var ErrMissingPlayer = errors.New("missing player id")
+
+// Submission 是提交成績到排行榜的結果
+type Submission struct {
+	Verification *evaluation.VerificationReport `json:"verification"`
+	Entry        *leaderboard.Entry             `json:"entry,omitempty"` // 這次的成績 (驗證通過才有)
+	PersonalBest bool                           `json:"personal_best"`   // 是否刷新了玩家在該關卡的最佳成績
+	Best         *leaderboard.Entry             `json:"best,omitempty"`  // 玩家目前在該關卡的最佳成績
+}
+
+// LeaderboardUseCase 處理排行榜的提交與查詢，所有成績都必須先由伺服器重新模擬驗證
+type LeaderboardUseCase struct {
+	repo         leaderboard.Repository
+	scenarioRepo scenario.Repository
+	engine       engine.Engine
+	mu           sync.Mutex // 讓「比較最佳成績 + 儲存」不會與其他提交交錯
+}
+
+func NewLeaderboardUseCase(lr leaderboard.Repository, sr scenario.Repository, e engine.Engine) *LeaderboardUseCase {
+	return &LeaderboardUseCase{repo: lr, scenarioRepo: sr, engine: e}
+}
+
+// Submit 驗證玩家在關卡中的一場遊戲，客戶端回報的成績與伺服器一致時才記錄，
+// 每位玩家在每個關卡只保留最佳成績 (leaderboard.Entry.Better)。
+// 為了讓成績可以互相比較，排行榜一律以關卡固定的種子 (engine.LeaderboardSeed)、每個 tick 1 秒、
+// 標準難度設定 (leaderboard.StandardSettings) 模擬完整個關卡；要求其他種子、tick 長度、模擬秒數
+// 或難度設定 (含調整 tick 長度或難度設定的操作) 時回傳 engine.ErrInvalidVerification
+func (uc *LeaderboardUseCase) Submit(scenarioID string, req engine.VerificationRequest) (*Submission, error) {
+	if req.Design != nil {
+		d := *req.Design
+		d.ScenarioID = scenarioID
+		req.Design = &d
+		if d.PlayerID == "" {
+			return nil, ErrMissingPlayer
+		}
+	}
+	s, err := uc.scenarioRepo.GetByID(scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := leaderboardRules(&req, s); err != nil {
+		return nil, err
+	}
+	report, err := uc.engine.Verify(req)
+	if err != nil {
+		return nil, err
+	}
+	sub := &Submission{Verification: report}
+	if !report.Verified {
+		return sub, nil
+	}
+
+	settings := leaderboard.StandardSettings()
+	sub.Entry = &leaderboard.Entry{
+		ScenarioID:     scenarioID,
+		PlayerID:       req.Design.PlayerID,
+		DesignID:       req.Design.ID,
+		TotalScore:     report.Score.TotalScore,
+		Passed:         report.Score.Passed,
+		CostPerSec:     report.Score.CostPerSec,
+		P95LatencyMS:   report.Score.P95LatencyMS,
+		ComponentCount: report.ComponentCount,
+		Seed:           report.Seed,
+		Settings:       &settings,
+		SubmittedAt:    time.Now().Unix(),
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	best, err := uc.repo.Get(scenarioID, sub.Entry.PlayerID)
+	if err != nil && !errors.Is(err, leaderboard.ErrNotFound) {
+		return nil, err
+	}
+	if best == nil || sub.Entry.Better(best) {
+		if err := uc.repo.Save(sub.Entry); err != nil {
+			return nil, err
+		}
+		best = sub.Entry
+		sub.PersonalBest = true
+	}
+	sub.Best = best
+	return sub, nil
+}
+
+// Leaderboard 依指標列出關卡的排行榜，limit <= 0 代表不限制筆數
+func (uc *LeaderboardUseCase) Leaderboard(scenarioID string, key leaderboard.SortKey, limit int) ([]leaderboard.Standing, error) {
+	if _, err := uc.scenarioRepo.GetByID(scenarioID); err != nil {
+		return nil, err
+	}
+	entries, err := uc.repo.ListByScenario(scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	standings, err := leaderboard.Rank(entries, key)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(standings) > limit {
+		standings = standings[:limit]
+	}
+	return standings, nil
+}
+
+// leaderboardRules 將驗證請求固定為排行榜的規則 (關卡固定的種子、每個 tick 1 秒、完整的關卡長度與標準難度設定)，
+// 客戶端要求其他值時回傳錯誤而不是默默覆寫，避免回報的成績是以不同的條件計算
+func leaderboardRules(req *engine.VerificationRequest, s *scenario.Scenario) error {
+	seed := engine.LeaderboardSeed(s.ID)
+	duration := float64(engine.ScenarioDuration(s))
+	switch {
+	case req.Seed != 0 && req.Seed != seed:
+		return &engine.ReasonError{Err: engine.ErrInvalidVerification, MessageID: "reason.leaderboard_seed", Params: map[string]interface{}{"seed": seed}}
+	case req.TickSeconds != 0 && req.TickSeconds != 1:
+		return &engine.ReasonError{Err: engine.ErrInvalidVerification, MessageID: "reason.leaderboard_dt"}
+	case req.DurationSeconds != 0 && req.DurationSeconds != duration:
+		return &engine.ReasonError{Err: engine.ErrInvalidVerification, MessageID: "reason.leaderboard_duration", Params: map[string]interface{}{"duration": duration}}
+	}
+	for _, a := range req.Actions {
+		if a.Type == engine.ActionTickSize {
+			return &engine.ReasonError{Err: engine.ErrInvalidVerification, MessageID: "reason.leaderboard_tick_action"}
+		}
+	}
+	if err := standardSettings(req); err != nil {
+		return err
+	}
+	req.Seed, req.TickSeconds, req.DurationSeconds = seed, 1, duration
+	return nil
+}
+
+// standardSettings 檢查設計圖與玩家操作沒有偏離標準難度設定：設計圖全域屬性、組件屬性
+// (難度設定的屬性名稱只對流量來源有作用，因此不區分組件類型)、修改屬性與新增組件的操作
+func standardSettings(req *engine.VerificationRequest) error {
+	if req.Design == nil {
+		return nil
+	}
+	standard := leaderboard.StandardSettings()
+	check := func(props, want component.Metadata) error {
+		if name := leaderboard.Deviation(props, want); name != "" {
+			return &engine.ReasonError{Err: engine.ErrInvalidVerification, MessageID: "reason.leaderboard_setting", Params: map[string]interface{}{"property": name, "value": want[name]}}
+		}
+		return nil
+	}
+
+	if err := check(req.Design.Properties, standard.Design); err != nil {
+		return err
+	}
+	for _, c := range req.Design.Components {
+		if err := check(c.Properties, standard.Source); err != nil {
+			return err
+		}
+	}
+	for _, a := range req.Actions {
+		switch {
+		case a.Type == engine.ActionSetProperty:
+			props := component.Metadata{a.Property: a.Value}
+			if err := check(props, standard.Design); err != nil {
+				return err
+			}
+			if err := check(props, standard.Source); err != nil {
+				return err
+			}
+		case a.Type == engine.ActionAddComponent && a.Component != nil:
+			if err := check(a.Component.Properties, standard.Source); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
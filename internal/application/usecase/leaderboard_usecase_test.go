@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/leaderboard"
+	"system-design-game/internal/infrastructure/persistence"
+	"testing"
+	"time"
+)
+
+func newTestLeaderboard() *LeaderboardUseCase {
+	scenarios := persistence.NewInMemScenarioRepository()
+	eng := engine.NewSimpleEngine(persistence.NewInMemDesignRepository(), scenarios, persistence.NewInMemCatalogRepository(), time.Now)
+	return NewLeaderboardUseCase(persistence.NewInMemLeaderboardRepository(), scenarios, eng)
+}
+
+func leaderboardDesign() *design.Design {
+	d := simpleDesign()
+	d.PlayerID = "alice"
+	return d
+}
+
+// 排行榜不接受偏離標準難度設定的設計圖或操作 (關閉隨機事件、加上額外流量、調整留存率或經濟模式)
+func TestSubmitRejectsNonStandardSettings(t *testing.T) {
+	uc := newTestLeaderboard()
+	score := 100.0
+	cases := []struct {
+		name     string
+		change   func(req *engine.VerificationRequest)
+		property string
+	}{
+		{"steady traffic", func(req *engine.VerificationRequest) {
+			req.Design.Properties = component.Metadata{"steady_traffic": true}
+		}, "steady_traffic"},
+		{"economy", func(req *engine.VerificationRequest) { req.Design.Properties = component.Metadata{"economy": true} }, "economy"},
+		{"extra traffic", func(req *engine.VerificationRequest) {
+			req.Design.Components[0].Properties["start_qps"] = float64(5000)
+		}, "start_qps"},
+		{"attacks", func(req *engine.VerificationRequest) { req.Design.Components[0].Properties["enable_attacks"] = true }, "enable_attacks"},
+		{"set_property action", func(req *engine.VerificationRequest) {
+			req.Actions = []engine.RunAction{{Tick: 10, Type: engine.ActionSetProperty, Property: "churn_rate", Value: float64(0)}}
+		}, "churn_rate"},
+		{"add_component action", func(req *engine.VerificationRequest) {
+			src := component.Component{ID: "src-2", Type: component.TrafficSource, Properties: component.Metadata{"burst_traffic": true}}
+			req.Actions = []engine.RunAction{{Tick: 10, Type: engine.ActionAddComponent, Component: &src}}
+		}, "burst_traffic"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := engine.VerificationRequest{Design: leaderboardDesign(), Reported: engine.ReportedScore{TotalScore: &score}}
+			tc.change(&req)
+			_, err := uc.Submit("tinyurl", req)
+			var re *engine.ReasonError
+			if !errors.Is(err, engine.ErrInvalidVerification) || !errors.As(err, &re) || re.MessageID != "reason.leaderboard_setting" || re.Params["property"] != tc.property {
+				t.Errorf("Submit = %v, want reason.leaderboard_setting for %s", err, tc.property)
+			}
+		})
+	}
+}
+
+// 通過驗證的成績記錄模擬時的難度設定
+func TestSubmitRecordsSettings(t *testing.T) {
+	uc := newTestLeaderboard()
+	placeholder := 0.0
+	report, err := uc.engine.Verify(engine.VerificationRequest{Design: leaderboardDesign(), Seed: engine.LeaderboardSeed("tinyurl"), Reported: engine.ReportedScore{TotalScore: &placeholder}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	score := report.Score.TotalScore
+	d := leaderboardDesign()
+	d.Components[0].Properties["enable_attacks"] = false // 明確寫出標準值仍可提交
+	sub, err := uc.Submit("tinyurl", engine.VerificationRequest{Design: d, Reported: engine.ReportedScore{TotalScore: &score}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Entry == nil {
+		t.Fatalf("submission was not verified: %+v", sub.Verification)
+	}
+	if want := leaderboard.StandardSettings(); sub.Entry.Settings == nil || !reflect.DeepEqual(*sub.Entry.Settings, want) {
+		t.Errorf("Settings = %+v, want %+v", sub.Entry.Settings, want)
+	}
+}
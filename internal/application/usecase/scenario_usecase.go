@@ -1,12 +1,15 @@
 package usecase
 
 import (
+	"errors"
+	"sync"
 	"system-design-game/internal/domain/scenario"
 )
 
 // ScenarioUseCase 處理與關卡情境相關的業務流程
 type ScenarioUseCase struct {
 	repo scenario.Repository
+	mu   sync.Mutex // 讓「檢查是否存在 + 儲存」不會與其他寫入交錯
 }
 
 func NewScenarioUseCase(repo scenario.Repository) *ScenarioUseCase {
@@ -22,3 +25,30 @@ func (uc *ScenarioUseCase) GetScenario(id string) (*scenario.Scenario, error) {
 func (uc *ScenarioUseCase) ListScenarios() ([]*scenario.Scenario, error) {
 	return uc.repo.ListAll()
 }
+
+// CreateScenario 驗證並新增關卡，ID 已存在時回傳 scenario.ErrAlreadyExists
+func (uc *ScenarioUseCase) CreateScenario(s *scenario.Scenario) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	_, err := uc.repo.GetByID(s.ID)
+	if err == nil {
+		return scenario.ErrAlreadyExists
+	}
+	if !errors.Is(err, scenario.ErrNotFound) {
+		return err
+	}
+	return uc.repo.Save(s)
+}
+
+// SaveScenario 驗證並新增或覆寫關卡 (講師修改既有關卡，或啟動時從檔案載入)
+func (uc *ScenarioUseCase) SaveScenario(s *scenario.Scenario) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.repo.Save(s)
+}
@@ -0,0 +1,603 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/world"
+	"system-design-game/internal/i18n"
+	"time"
+)
+
+const (
+	minSessionSpeed    = 0.01            // 最慢 0.01 倍速 (更小的倍速會讓推進間隔超出 time.Duration 的範圍)
+	maxSessionSpeed    = 100.0           // 最快 100 倍速
+	sessionIdleTimeout = 5 * time.Minute // 沒有任何觀看者超過此時間的場次會自動結束
+	subscriberBuffer   = 16              // 觀看者的事件緩衝，處理不及時丟棄較舊的 tick
+	maxFinishedRuns    = 32              // 保留最近結束的場次執行紀錄數量
+	worldSaveSeconds   = 10.0            // 每經過多少模擬秒數保存一次玩家的遊戲狀態
+)
+
+var (
+	// ErrSessionNotFound 表示模擬場次不存在或已結束
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrInvalidControl 表示無法辨識的控制指令或參數錯誤
+	ErrInvalidControl = errors.New("invalid session control")
+	// ErrPlayerInSession 表示玩家已有進行中的場次，同一位玩家同時只能進行一場 (避免互相覆寫遊戲狀態)
+	ErrPlayerInSession = errors.New("player already in session")
+)
+
+// 控制指令類型
+const (
+	ControlPause        = "pause"
+	ControlResume       = "resume"
+	ControlSpeed        = "speed"
+	ControlStep         = "step"          // 立即推進一個 tick (暫停時用來逐步觀察)
+	ControlTickSize     = "dt"            // 修改每個 tick 代表的秒數
+	ControlRestart      = "restart"       // 重啟已崩潰的組件
+	ControlSetProperty  = "set_property"  // 修改組件 (或設計圖全域) 屬性
+	ControlConnect      = "connect"       // 新增連線
+	ControlAddComponent = "add_component" // 新增 (購買) 組件，經濟模式下以剩餘資金支付 setup_cost
+)
+
+// SessionControl 是觀看者送回伺服器的控制指令
+type SessionControl struct {
+	Type        string               `json:"type"`
+	Speed       float64              `json:"speed,omitempty"`        // speed：倍速 (0.01 <= speed <= 100)
+	DT          float64              `json:"dt,omitempty"`           // dt：每個 tick 的秒數 (0.1 ~ 60)
+	ComponentID string               `json:"component_id,omitempty"` // restart / set_property 的目標組件
+	Property    string               `json:"property,omitempty"`     // set_property 的屬性名稱
+	Value       interface{}          `json:"value,omitempty"`        // set_property 的新值，null 代表移除設定
+	Connection  *design.Connection   `json:"connection,omitempty"`   // connect 的新連線
+	Component   *component.Component `json:"component,omitempty"`    // add_component 的新組件
+}
+
+// SessionState 是模擬場次目前的時鐘狀態
+type SessionState struct {
+	ID         string  `json:"id"`
+	DesignID   string  `json:"design_id"`
+	ScenarioID string  `json:"scenario_id"`
+	Elapsed    float64 `json:"elapsed"` // 下一個 tick 的模擬時間 (秒)
+	DT         float64 `json:"dt"`      // 每個 tick 的秒數
+	Paused     bool    `json:"paused"`
+	Speed      float64 `json:"speed"` // 模擬時間與真實時間的比例，實際每 dt / speed 秒推進一個 tick
+	Spectators int     `json:"spectators"`
+
+	PlayerID string              `json:"player_id,omitempty"`
+	Economy  *evaluation.Economy `json:"economy,omitempty"` // 無盡模式的玩家經濟狀態
+}
+
+// SessionEvent 是推送給觀看者的事件
+//   - tick：每個 tick 的評估結果
+//   - state：暫停、倍速等控制指令生效後的最新狀態
+//   - closed：場次已結束，Reason 為 bankrupt 代表玩家破產、over_budget 代表經濟模式下持續超出預算
+type SessionEvent struct {
+	Type   string             `json:"type"`
+	State  SessionState       `json:"state"`
+	Result *evaluation.Result `json:"result,omitempty"`
+	Reason string             `json:"reason,omitempty"`
+}
+
+// 場次結束的原因
+const (
+	CloseBankrupt   = "bankrupt"    // 玩家破產
+	CloseOverBudget = "over_budget" // 經濟模式下持續超出每秒預算
+	CloseFailed     = "failed"      // 模擬無法推進
+)
+
+// Subscription 是一位觀看者的事件串流，C 中的事件已依觀看者的語系轉為 JSON
+// 場次結束或取消訂閱後 C 會被關閉
+type Subscription struct {
+	C       <-chan []byte
+	session *Session
+	ch      chan []byte
+	locale  i18n.Locale
+}
+
+// Cancel 取消訂閱
+func (sub *Subscription) Cancel() {
+	sub.session.unsubscribe(sub)
+}
+
+// Session 是一場在伺服器端依自己的時鐘推進的模擬，多位觀看者可以同時觀看並控制同一場模擬
+type Session struct {
+	id       string
+	designID string
+	sim      *engine.Simulation
+	log      *engine.RunLog
+	onClose  func()
+
+	// 無盡模式的經濟：每個 tick 依成功的 QPS 賺取收益並扣除運作成本，破產時場次結束
+	game      *world.GameState
+	worldRepo world.Repository // nil 代表不保存 (匿名玩家)
+	savedAt   float64          // 上次保存遊戲狀態時的模擬秒數
+
+	mu          sync.Mutex
+	paused      bool
+	speed       float64
+	subscribers map[*Subscription]bool
+	idleSince   time.Time
+	latest      *evaluation.Result
+	closed      bool
+	stopped     string // 模擬無法再推進的原因 (破產或錯誤)，時鐘會在下一次檢查時結束場次
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+// ID 回傳場次 ID
+func (s *Session) ID() string {
+	return s.id
+}
+
+// State 回傳場次目前的狀態
+func (s *Session) State() SessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state()
+}
+
+func (s *Session) state() SessionState {
+	return SessionState{
+		ID:         s.id,
+		DesignID:   s.designID,
+		ScenarioID: s.sim.Scenario().ID,
+		Elapsed:    s.sim.Elapsed(),
+		DT:         s.sim.TickSeconds(),
+		Paused:     s.paused,
+		Speed:      s.speed,
+		Spectators: len(s.subscribers),
+		PlayerID:   s.game.PlayerID,
+		Economy:    s.economy(),
+	}
+}
+
+// economy 回傳玩家目前的經濟狀態
+func (s *Session) economy() *evaluation.Economy {
+	return &evaluation.Economy{
+		PlayerID:     s.game.PlayerID,
+		Balance:      s.game.Balance,
+		SystemHealth: s.game.SystemHealth,
+		TotalUsers:   s.game.TotalUsers,
+		Uptime:       s.game.Uptime,
+		Bankrupt:     s.game.Bankrupt(),
+	}
+}
+
+// RunLog 回傳場次到目前為止的執行紀錄
+func (s *Session) RunLog() *engine.RunLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.log.Snapshot()
+}
+
+// Subscribe 加入觀看，會先收到目前的狀態與最近一個 tick 的結果
+func (s *Session) Subscribe(l i18n.Locale) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrSessionNotFound
+	}
+	ch := make(chan []byte, subscriberBuffer)
+	sub := &Subscription{C: ch, session: s, ch: ch, locale: l}
+	s.subscribers[sub] = true
+
+	sub.send(s.frame(SessionEvent{Type: "state", State: s.state()}, l))
+	if s.latest != nil {
+		sub.send(s.frame(SessionEvent{Type: "tick", State: s.state(), Result: s.latest}, l))
+	}
+	return sub, nil
+}
+
+func (s *Session) unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.subscribers[sub] {
+		return
+	}
+	delete(s.subscribers, sub)
+	close(sub.ch)
+	if len(s.subscribers) == 0 {
+		s.idleSince = time.Now()
+	}
+}
+
+// Control 套用控制指令，生效後推送最新狀態給所有觀看者
+// 組件不存在時回傳 engine.ErrComponentNotFound，重啟未崩潰的組件時回傳 engine.ErrComponentNotCrashed，屬性不符合定義時回傳 *component.ValidationError，
+// 連線不合法時回傳 engine.ErrInvalidConnection，新增組件不合法或資金不足時回傳 engine.ErrInvalidComponent 或 engine.ErrInsufficientFunds；
+// 改變模擬結果的指令會記錄在執行紀錄中
+func (s *Session) Control(msg SessionControl) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSessionNotFound
+	}
+
+	switch msg.Type {
+	case ControlPause:
+		s.paused = true
+	case ControlResume:
+		s.paused = false
+	case ControlSpeed:
+		if err := checkSpeed(msg.Speed); err != nil {
+			return err
+		}
+		s.speed = msg.Speed
+	case ControlTickSize:
+		if err := s.sim.SetTickSeconds(msg.DT); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidControl, err)
+		}
+	case ControlStep:
+		if s.stopped != "" {
+			return fmt.Errorf("%w: 模擬無法推進", ErrInvalidControl)
+		}
+		s.advance()
+		s.poke()
+		return nil
+	case ControlRestart:
+		if err := s.sim.RestartComponent(msg.ComponentID); err != nil {
+			return err
+		}
+	case ControlSetProperty:
+		if msg.Property == "" {
+			return fmt.Errorf("%w: 缺少 property", ErrInvalidControl)
+		}
+		if err := s.sim.SetProperty(msg.ComponentID, msg.Property, msg.Value); err != nil {
+			return err
+		}
+	case ControlConnect:
+		if msg.Connection == nil {
+			return fmt.Errorf("%w: 缺少 connection", ErrInvalidControl)
+		}
+		if err := s.sim.AddConnection(*msg.Connection); err != nil {
+			return err
+		}
+	case ControlAddComponent:
+		if msg.Component == nil {
+			return fmt.Errorf("%w: 缺少 component", ErrInvalidControl)
+		}
+		if err := s.sim.AddComponent(*msg.Component); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidControl, msg.Type)
+	}
+
+	s.broadcast(SessionEvent{Type: "state", State: s.state()})
+	s.poke()
+	return nil
+}
+
+// poke 通知時鐘重新計算下一個 tick 的時間 (如倍速改變)
+func (s *Session) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// interval 回傳目前倍速下一個 tick 的實際間隔
+func (s *Session) interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Duration(float64(time.Second) * s.sim.TickSeconds() / s.speed)
+}
+
+// run 是場次的時鐘，每個間隔推進一個 tick，直到場次結束
+func (s *Session) run() {
+	timer := time.NewTimer(s.interval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			if reason := s.stopReason(); reason != "" {
+				s.closeWith(reason)
+				return
+			}
+		case <-timer.C:
+			if !s.tick() {
+				s.closeWith(s.stopReason())
+				return
+			}
+		}
+		timer.Reset(s.interval())
+	}
+}
+
+func (s *Session) stopReason() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+// tick 由時鐘觸發，未暫停時推進一個 tick；沒有觀看者太久或模擬無法再推進時回傳 false
+func (s *Session) tick() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.subscribers) == 0 && time.Since(s.idleSince) > sessionIdleTimeout {
+		return false
+	}
+	if !s.paused && s.stopped == "" {
+		s.advance()
+	}
+	return s.stopped == ""
+}
+
+// advance 推進一個 tick (模擬依玩家的遊戲狀態結算收益與成本) 並推送結果 (呼叫端需持有鎖)
+// 模擬出錯、玩家破產或持續超出預算時記錄在 stopped，由時鐘結束場次
+func (s *Session) advance() {
+	res, err := s.sim.Step()
+	if err != nil {
+		s.stopped = CloseFailed
+		return
+	}
+
+	res.Economy = s.economy()
+	switch {
+	case s.game.Bankrupt():
+		s.stopped = CloseBankrupt
+	case s.sim.BudgetFailed():
+		s.stopped = CloseOverBudget
+	}
+	if s.stopped != "" || s.sim.Elapsed()-s.savedAt >= worldSaveSeconds {
+		s.saveGame()
+	}
+
+	s.latest = res
+	s.broadcast(SessionEvent{Type: "tick", State: s.state(), Result: res})
+}
+
+// saveGame 保存玩家的遊戲狀態 (呼叫端需持有鎖)，保存失敗不影響模擬的進行
+func (s *Session) saveGame() {
+	s.savedAt = s.sim.Elapsed()
+	if s.worldRepo == nil {
+		return
+	}
+	s.game.LastTick = time.Now().Unix()
+	state := *s.game
+	if err := s.worldRepo.Save(&state); err != nil {
+		log.Printf("無法保存玩家 %s 的遊戲狀態: %v", s.game.PlayerID, err)
+	}
+}
+
+// Close 結束場次，所有觀看者的串流會收到 closed 事件後關閉
+func (s *Session) Close() {
+	s.closeWith("")
+}
+
+func (s *Session) closeWith(reason string) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.saveGame()
+	s.broadcast(SessionEvent{Type: "closed", State: s.state(), Reason: reason})
+	for sub := range s.subscribers {
+		close(sub.ch)
+	}
+	s.subscribers = nil
+	close(s.done)
+	s.mu.Unlock()
+
+	if s.onClose != nil {
+		s.onClose()
+	}
+}
+
+// broadcast 將事件依語系轉為 JSON 推送給所有觀看者 (呼叫端需持有鎖)
+func (s *Session) broadcast(ev SessionEvent) {
+	frames := make(map[i18n.Locale][]byte)
+	for sub := range s.subscribers {
+		frame, ok := frames[sub.locale]
+		if !ok {
+			frame = s.frame(ev, sub.locale)
+			frames[sub.locale] = frame
+		}
+		sub.send(frame)
+	}
+}
+
+func (s *Session) frame(ev SessionEvent, l i18n.Locale) []byte {
+	if ev.Result != nil {
+		ev.Result.Localize(l)
+	}
+	data, _ := json.Marshal(ev)
+	return data
+}
+
+// send 推送事件，觀看者處理不及時丟棄這個事件，避免拖慢整場模擬
+func (sub *Subscription) send(frame []byte) {
+	select {
+	case sub.ch <- frame:
+	default:
+	}
+}
+
+// SessionUseCase 管理伺服器端的即時模擬場次
+type SessionUseCase struct {
+	engine    engine.Engine
+	worldRepo world.Repository
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+	finished map[string]*engine.RunLog // 最近結束的場次執行紀錄
+	order    []string
+}
+
+func NewSessionUseCase(e engine.Engine, wr world.Repository) *SessionUseCase {
+	return &SessionUseCase{engine: e, worldRepo: wr, sessions: make(map[string]*Session), finished: make(map[string]*engine.RunLog)}
+}
+
+// checkSpeed 檢查倍速介於 minSessionSpeed 到 maxSessionSpeed (NaN 也會被拒絕)
+func checkSpeed(speed float64) error {
+	if !(speed >= minSessionSpeed && speed <= maxSessionSpeed) {
+		return fmt.Errorf("%w: speed 必須介於 %g 到 %g", ErrInvalidControl, minSessionSpeed, maxSessionSpeed)
+	}
+	return nil
+}
+
+// SessionOptions 是開始場次的參數，未設定時為 1 倍速、每個 tick 1 秒
+type SessionOptions struct {
+	Speed       float64
+	TickSeconds float64
+}
+
+// StartSession 以已儲存的設計圖開始一場模擬，設計圖有 player_id 時延續玩家保存的遊戲狀態 (破產後重新開始)
+func (uc *SessionUseCase) StartSession(designID string, opts SessionOptions) (*Session, error) {
+	speed := opts.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	if err := checkSpeed(speed); err != nil {
+		return nil, err
+	}
+	sim, err := uc.engine.Simulate(designID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.TickSeconds != 0 {
+		if err := sim.SetTickSeconds(opts.TickSeconds); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidControl, err)
+		}
+	}
+
+	s := &Session{
+		id:          newSessionID(),
+		designID:    designID,
+		sim:         sim,
+		log:         sim.Record(),
+		speed:       speed,
+		subscribers: make(map[*Subscription]bool),
+		idleSince:   time.Now(),
+		wake:        make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	if err := uc.loadGame(s, sim.Design().PlayerID); err != nil {
+		return nil, err
+	}
+	sim.UseGameState(s.game)
+	s.onClose = func() {
+		runLog := s.RunLog()
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+		delete(uc.sessions, s.id)
+		uc.finished[s.id] = runLog
+		uc.order = append(uc.order, s.id)
+		if len(uc.order) > maxFinishedRuns {
+			delete(uc.finished, uc.order[0])
+			uc.order = uc.order[1:]
+		}
+	}
+
+	uc.mu.Lock()
+	if playerID := s.game.PlayerID; playerID != "" {
+		for _, other := range uc.sessions {
+			if other.game.PlayerID == playerID {
+				uc.mu.Unlock()
+				return nil, fmt.Errorf("%w: %s (%s)", ErrPlayerInSession, playerID, other.id)
+			}
+		}
+	}
+	uc.sessions[s.id] = s
+	uc.mu.Unlock()
+	go s.run()
+	return s, nil
+}
+
+// loadGame 讀取玩家的遊戲狀態，沒有保存過或已破產時建立新的狀態；匿名玩家的狀態不保存
+func (uc *SessionUseCase) loadGame(s *Session, playerID string) error {
+	s.game = world.NewGameState(playerID)
+	if playerID == "" || uc.worldRepo == nil {
+		return nil
+	}
+	s.worldRepo = uc.worldRepo
+	state, err := uc.worldRepo.GetByPlayerID(playerID)
+	if errors.Is(err, world.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !state.Bankrupt() {
+		game := *state
+		s.game = &game
+	}
+	return nil
+}
+
+// GameState 取得玩家保存的遊戲狀態，進行中的場次以最近一次保存的狀態為準
+func (uc *SessionUseCase) GameState(playerID string) (*world.GameState, error) {
+	if uc.worldRepo == nil {
+		return nil, fmt.Errorf("%w: %s", world.ErrNotFound, playerID)
+	}
+	return uc.worldRepo.GetByPlayerID(playerID)
+}
+
+// GetSession 取得進行中的場次
+func (uc *SessionUseCase) GetSession(id string) (*Session, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	s, ok := uc.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	return s, nil
+}
+
+// StopSession 結束場次
+func (uc *SessionUseCase) StopSession(id string) error {
+	s, err := uc.GetSession(id)
+	if err != nil {
+		return err
+	}
+	s.Close()
+	return nil
+}
+
+// RunLog 取得場次的執行紀錄，進行中或最近結束的場次皆可取得
+func (uc *SessionUseCase) RunLog(id string) (*engine.RunLog, error) {
+	uc.mu.Lock()
+	s, ok := uc.sessions[id]
+	runLog, done := uc.finished[id]
+	uc.mu.Unlock()
+	switch {
+	case ok:
+		return s.RunLog(), nil
+	case done:
+		return runLog, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+}
+
+// CostProjection 依場次 (進行中或最近結束) 的執行紀錄推算長期成本與每百萬個成功請求的成本
+func (uc *SessionUseCase) CostProjection(id string) (*evaluation.CostProjection, error) {
+	runLog, err := uc.RunLog(id)
+	if err != nil {
+		return nil, err
+	}
+	return engine.ProjectCosts(runLog), nil
+}
+
+func newSessionID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/world"
+	"system-design-game/internal/i18n"
+	"system-design-game/internal/infrastructure/persistence"
+	"testing"
+	"time"
+)
+
+// newTestSessions 建立使用記憶體儲存的場次用例，並存入設計圖 d
+func newTestSessions(t *testing.T, d *design.Design, worldRepo world.Repository) *SessionUseCase {
+	t.Helper()
+	designRepo := persistence.NewInMemDesignRepository()
+	eng := engine.NewSimpleEngine(designRepo, persistence.NewInMemScenarioRepository(), persistence.NewInMemCatalogRepository(), time.Now)
+	if err := designRepo.Save(d); err != nil {
+		t.Fatal(err)
+	}
+	return NewSessionUseCase(eng, worldRepo)
+}
+
+func simpleDesign() *design.Design {
+	return &design.Design{
+		ID: "d1", ScenarioID: "tinyurl",
+		Components: []component.Component{
+			{ID: "src", Type: component.TrafficSource, Properties: component.Metadata{"read_ratio": float64(80)}},
+			{ID: "web", Type: component.WebServer, Properties: component.Metadata{"max_qps": float64(1000)}},
+		},
+		Connections: []design.Connection{{FromID: "src", ToID: "web"}},
+	}
+}
+
+// 倍速必須介於 0.01 到 100：極小的倍速會讓推進間隔溢位
+func TestSessionSpeedBounds(t *testing.T) {
+	uc := newTestSessions(t, simpleDesign(), persistence.NewInMemWorldRepository())
+	for _, speed := range []float64{1e-12, 0.009, -1, 101, math.NaN()} {
+		if _, err := uc.StartSession("d1", SessionOptions{Speed: speed}); !errors.Is(err, ErrInvalidControl) {
+			t.Errorf("StartSession(speed %g) = %v, want ErrInvalidControl", speed, err)
+		}
+	}
+
+	s, err := uc.StartSession("d1", SessionOptions{Speed: minSessionSpeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uc.StopSession(s.ID())
+	if got := s.interval(); got != 100*time.Second {
+		t.Errorf("interval at speed %g = %v, want 100s", minSessionSpeed, got)
+	}
+	for _, speed := range []float64{1e-12, 0, math.Inf(1)} {
+		if err := s.Control(SessionControl{Type: ControlSpeed, Speed: speed}); !errors.Is(err, ErrInvalidControl) {
+			t.Errorf("Control(speed %g) = %v, want ErrInvalidControl", speed, err)
+		}
+	}
+	if err := s.Control(SessionControl{Type: ControlSpeed, Speed: maxSessionSpeed}); err != nil {
+		t.Errorf("Control(speed %g) = %v", maxSessionSpeed, err)
+	}
+}
+
+// 破產時場次以 bankrupt 結束並保存狀態 (收益、成本與運行秒數依 tick 長度縮放)，下一個場次從初始金幣重新開始
+func TestSessionClosesOnBankruptcy(t *testing.T) {
+	worlds := persistence.NewInMemWorldRepository()
+	if err := worlds.Save(&world.GameState{PlayerID: "alice", Balance: 1, SystemHealth: 100, Uptime: 30}); err != nil {
+		t.Fatal(err)
+	}
+	d := simpleDesign()
+	d.PlayerID = "alice"
+	d.Components[1].OperationalCost = 1000
+	uc := newTestSessions(t, d, worlds)
+
+	s, err := uc.StartSession("d1", SessionOptions{Speed: minSessionSpeed, TickSeconds: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := s.Subscribe(i18n.En)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Control(SessionControl{Type: ControlStep}); err != nil {
+		t.Fatal(err)
+	}
+
+	var tick, closed *SessionEvent
+	timeout := time.After(5 * time.Second)
+	for closed == nil {
+		select {
+		case frame, ok := <-sub.C:
+			if !ok {
+				t.Fatal("stream closed without a closed event")
+			}
+			var ev SessionEvent
+			if err := json.Unmarshal(frame, &ev); err != nil {
+				t.Fatal(err)
+			}
+			switch ev.Type {
+			case "tick":
+				tick = &ev
+			case "closed":
+				closed = &ev
+			}
+		case <-timeout:
+			t.Fatal("session did not close after going bankrupt")
+		}
+	}
+	if tick == nil {
+		t.Fatal("no tick event before closing")
+	}
+	if closed.Reason != CloseBankrupt {
+		t.Errorf("closed reason = %q, want %q", closed.Reason, CloseBankrupt)
+	}
+
+	saved, err := worlds.GetByPlayerID("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := tick.Result
+	want := 1 + (res.RevenuePerSec-res.CostPerSec)*0.5
+	if math.Abs(saved.Balance-want) > 1e-6 || !saved.Bankrupt() {
+		t.Errorf("saved balance = %v, want %v (bankrupt)", saved.Balance, want)
+	}
+	if saved.Uptime != 30.5 {
+		t.Errorf("saved uptime = %v, want 30.5", saved.Uptime)
+	}
+	if saved.TotalUsers != int64(float64(res.FulfilledQPS)*0.5) {
+		t.Errorf("saved users = %d, want %d", saved.TotalUsers, int64(float64(res.FulfilledQPS)*0.5))
+	}
+	if _, err := uc.GetSession(s.ID()); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("GetSession after bankruptcy = %v, want ErrSessionNotFound", err)
+	}
+
+	next, err := uc.StartSession("d1", SessionOptions{Speed: minSessionSpeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uc.StopSession(next.ID())
+	if b := next.State().Economy; b == nil || b.Balance != world.StartingBalance {
+		t.Errorf("a bankrupt player should restart with %v, got %+v", world.StartingBalance, b)
+	}
+}
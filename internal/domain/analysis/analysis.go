@@ -0,0 +1,367 @@
+package analysis
+
+import (
+	"sort"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/i18n"
+)
+
+// Cause 定義崩潰或瓶頸的根本原因
+type Cause string
+
+const (
+	CauseBurst          Cause = "BURST"              // 突發流量
+	CauseAttack         Cause = "ATTACK"             // 惡意流量攻擊
+	CauseCacheColdStart Cause = "CACHE_COLD_START"   // 上游快取失效或剛重啟，讀取全數穿透
+	CauseQueueDrain     Cause = "QUEUE_DRAIN"        // 上游 MQ 以 PUSH 模式傾倒積壓訊息
+	CauseOOM            Cause = "OOM"                // 記憶體耗盡
+	CauseRandomFailure  Cause = "RANDOM_FAILURE"     // 隨機硬體故障
+	CausePreviousCrash  Cause = "PREVIOUS_CRASH"     // 先前已崩潰且尚未重啟
+	CauseOverload       Cause = "SUSTAINED_OVERLOAD" // 持續性過載 (容量不足)
+)
+
+// Severity 定義發現的嚴重程度
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+)
+
+// Kind 定義發現的類型
+type Kind string
+
+const (
+	KindCrash      Kind = "crash"      // 崩潰歸因
+	KindBottleneck Kind = "bottleneck" // 路徑上的限流瓶頸
+)
+
+// Finding 是一筆結構化的分析發現
+type Finding struct {
+	Kind          Kind     `json:"kind"`
+	Severity      Severity `json:"severity"`
+	ComponentID   string   `json:"component_id"`
+	ComponentName string   `json:"component_name"`
+	Cause         Cause    `json:"cause,omitempty"`
+	UpstreamID    string   `json:"upstream_id,omitempty"` // 造成問題的上游組件
+	Path          []string `json:"path,omitempty"`        // 瓶頸所在的流量路徑
+	Utilization   float64  `json:"utilization"`           // 負載 / 有效最大 QPS
+	Message       string   `json:"message"`
+	Remediation   string   `json:"remediation"`
+
+	MessageID     string                 `json:"message_id"`     // 訊息 ID，文字由 Localize 依語系產生
+	RemediationID string                 `json:"remediation_id"` // 建議的訊息 ID
+	Params        map[string]interface{} `json:"params,omitempty"`
+}
+
+// Report 是針對某一個 tick 的瓶頸與根因分析報告
+type Report struct {
+	DesignID string    `json:"design_id"`
+	Tick     int64     `json:"tick"`
+	Findings []Finding `json:"findings"` // 依嚴重程度與影響排序
+}
+
+// maxPaths 限制列舉的路徑數量，避免大型拓撲造成組合爆炸
+const maxPaths = 256
+
+// graceSeconds 與引擎的重啟保護期一致，快取在此期間內視為冷啟動
+const graceSeconds = 5.0
+
+// analyzer 保存單次分析需要的拓撲與評估結果
+type analyzer struct {
+	design  *design.Design
+	result  *evaluation.Result
+	comps   map[string]component.Component
+	adj     map[string][]string
+	reverse map[string][]string
+}
+
+// Analyze 根據評估結果找出每條路徑的限流組件，並將每個崩潰歸因到上游的根本原因
+func Analyze(d *design.Design, res *evaluation.Result) *Report {
+	a := &analyzer{
+		design:  d,
+		result:  res,
+		comps:   make(map[string]component.Component),
+		adj:     make(map[string][]string),
+		reverse: make(map[string][]string),
+	}
+	for _, c := range d.Components {
+		a.comps[c.ID] = c
+	}
+	for _, conn := range d.Connections {
+		a.adj[conn.FromID] = append(a.adj[conn.FromID], conn.ToID)
+		a.reverse[conn.ToID] = append(a.reverse[conn.ToID], conn.FromID)
+	}
+
+	findings := append(a.crashFindings(), a.bottleneckFindings()...)
+	sort.SliceStable(findings, func(i, j int) bool {
+		ri, rj := severityRank(findings[i].Severity), severityRank(findings[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		if findings[i].Kind != findings[j].Kind {
+			return findings[i].Kind == KindCrash
+		}
+		return findings[i].Utilization > findings[j].Utilization
+	})
+
+	report := &Report{DesignID: d.ID, Tick: res.CreatedAt, Findings: findings}
+	report.Localize(i18n.DefaultLocale)
+	return report
+}
+
+// Localize 依語系產生每筆發現的訊息與建議文字
+func (r *Report) Localize(l i18n.Locale) {
+	for i := range r.Findings {
+		f := &r.Findings[i]
+		f.Message = i18n.T(l, f.MessageID, f.Params)
+		f.Remediation = i18n.T(l, f.RemediationID, f.Params)
+	}
+}
+
+// crashFindings 將每個崩潰的組件歸因到最可能的根本原因
+func (a *analyzer) crashFindings() []Finding {
+	failed := make(map[string]bool)
+	for _, id := range a.result.FailedComponentIDs {
+		failed[id] = true
+	}
+
+	ids := append([]string(nil), a.result.CrashedComponentIDs...)
+	sort.Strings(ids)
+
+	var findings []Finding
+	for _, id := range ids {
+		comp := a.comps[id]
+		f := Finding{
+			Kind:          KindCrash,
+			Severity:      SeverityCritical,
+			ComponentID:   id,
+			ComponentName: comp.Name,
+			Utilization:   a.utilization(id),
+			Params:        map[string]interface{}{"component": comp.Name},
+		}
+
+		load := a.result.ComponentLoads[id]
+		mal := a.result.ComponentMaliciousLoads[id]
+		prevCrashed := comp.Properties.Enabled("crashed")
+
+		switch {
+		case prevCrashed:
+			f.Cause = CausePreviousCrash
+		case failed[id]:
+			f.Cause = CauseRandomFailure
+		case a.result.ComponentRAMUsage[id] >= 100:
+			f.Cause = CauseOOM
+		case a.result.IsAttackActive && mal > 0 && float64(mal) >= float64(load)*0.2:
+			f.Cause = CauseAttack
+			f.UpstreamID = a.nearestAncestor(id, component.TrafficSource)
+			f.Params["malicious_qps"] = mal
+		default:
+			if cache := a.coldCache(id); cache != "" {
+				f.Cause = CauseCacheColdStart
+				f.UpstreamID = cache
+				f.Params["upstream"] = a.comps[cache].Name
+			} else if mq := a.drainingQueue(id); mq != "" {
+				f.Cause = CauseQueueDrain
+				f.UpstreamID = mq
+				f.Params["upstream"] = a.comps[mq].Name
+				f.Params["backlog"] = a.result.ComponentBacklogs[mq]
+			} else if a.result.IsBurstActive {
+				f.Cause = CauseBurst
+				f.UpstreamID = a.nearestAncestor(id, component.TrafficSource)
+			} else {
+				f.Cause = CauseOverload
+				f.Params["load"] = load
+				f.Params["max_qps"] = a.result.ComponentEffectiveMaxQPS[id]
+			}
+		}
+		f.MessageID = "analysis.crash." + string(f.Cause)
+		f.RemediationID = "analysis.remediation." + string(f.Cause)
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// bottleneckFindings 列舉所有從流量來源出發的路徑，找出每條路徑上使用率最高的組件
+func (a *analyzer) bottleneckFindings() []Finding {
+	crashed := make(map[string]bool)
+	for _, id := range a.result.CrashedComponentIDs {
+		crashed[id] = true
+	}
+
+	byComponent := make(map[string]*Finding)
+	var order []string
+	for _, path := range a.paths() {
+		limiting, best := "", -1.0
+		for _, id := range path {
+			if a.comps[id].Type == component.TrafficSource || crashed[id] || a.result.ComponentEffectiveMaxQPS[id] <= 0 {
+				continue
+			}
+			if u := a.utilization(id); u > best {
+				limiting, best = id, u
+			}
+		}
+		if limiting == "" {
+			continue
+		}
+		if _, ok := byComponent[limiting]; ok {
+			continue
+		}
+
+		comp := a.comps[limiting]
+		f := &Finding{
+			Kind:          KindBottleneck,
+			Severity:      SeverityInfo,
+			ComponentID:   limiting,
+			ComponentName: comp.Name,
+			Path:          path,
+			Utilization:   best,
+			MessageID:     "analysis.bottleneck",
+			RemediationID: remediationFor(comp.Type),
+			Params:        map[string]interface{}{"component": comp.Name, "utilization": best * 100},
+		}
+		if best >= 1.0 {
+			f.Severity = SeverityCritical
+		} else if best >= 0.8 {
+			f.Severity = SeverityWarning
+		}
+		byComponent[limiting] = f
+		order = append(order, limiting)
+	}
+
+	findings := make([]Finding, 0, len(order))
+	for _, id := range order {
+		findings = append(findings, *byComponent[id])
+	}
+	return findings
+}
+
+// paths 以 DFS 列舉從每個流量來源到末端組件的路徑 (略過環)
+func (a *analyzer) paths() [][]string {
+	var result [][]string
+	var walk func(id string, path []string, onPath map[string]bool)
+	walk = func(id string, path []string, onPath map[string]bool) {
+		if len(result) >= maxPaths {
+			return
+		}
+		path = append(path, id)
+		onPath[id] = true
+		defer delete(onPath, id)
+
+		extended := false
+		for _, next := range a.adj[id] {
+			if _, ok := a.comps[next]; !ok || onPath[next] {
+				continue
+			}
+			extended = true
+			walk(next, path, onPath)
+		}
+		if !extended {
+			result = append(result, append([]string(nil), path...))
+		}
+	}
+
+	for _, c := range a.design.Components {
+		if c.Type == component.TrafficSource {
+			walk(c.ID, nil, make(map[string]bool))
+		}
+	}
+	return result
+}
+
+// ancestors 以 BFS 回傳所有上游組件 (由近到遠)
+func (a *analyzer) ancestors(id string) []string {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	var out []string
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, prev := range a.reverse[cur] {
+			if seen[prev] {
+				continue
+			}
+			seen[prev] = true
+			out = append(out, prev)
+			queue = append(queue, prev)
+		}
+	}
+	return out
+}
+
+// nearestAncestor 回傳最近的指定類型上游組件
+func (a *analyzer) nearestAncestor(id string, t component.Type) string {
+	for _, prev := range a.ancestors(id) {
+		if a.comps[prev].Type == t {
+			return prev
+		}
+	}
+	return ""
+}
+
+// coldCache 回傳已崩潰或仍在重啟保護期內的上游快取
+func (a *analyzer) coldCache(id string) string {
+	crashed := make(map[string]bool)
+	for _, cid := range a.result.CrashedComponentIDs {
+		crashed[cid] = true
+	}
+	for _, prev := range a.ancestors(id) {
+		c := a.comps[prev]
+		if c.Type != component.Cache && c.Type != component.CDN {
+			continue
+		}
+		if crashed[prev] {
+			return prev
+		}
+		if restartedAt, ok := c.Properties.Float("restartedAt"); ok && float64(a.result.CreatedAt)-restartedAt < graceSeconds {
+			return prev
+		}
+	}
+	return ""
+}
+
+// drainingQueue 回傳有積壓且以 PUSH 模式投遞的上游 MQ
+func (a *analyzer) drainingQueue(id string) string {
+	for _, prev := range a.ancestors(id) {
+		c := a.comps[prev]
+		if c.Type != component.MessageQueue || a.result.ComponentBacklogs[prev] <= 0 {
+			continue
+		}
+		if c.Properties.TextOr("delivery_mode", "PUSH") == "PULL" {
+			continue
+		}
+		return prev
+	}
+	return ""
+}
+
+func (a *analyzer) utilization(id string) float64 {
+	maxQPS := a.result.ComponentEffectiveMaxQPS[id]
+	if maxQPS <= 0 {
+		return 0
+	}
+	return float64(a.result.ComponentLoads[id]) / float64(maxQPS)
+}
+
+// remediationFor 回傳針對瓶頸組件類型的建議訊息 ID
+func remediationFor(t component.Type) string {
+	id := "analysis.remediation.type." + string(t)
+	if _, ok := i18n.Lookup(i18n.DefaultLocale, id); ok {
+		return id
+	}
+	return "analysis.remediation.type.default"
+}
+
+func severityRank(s Severity) int {
+	switch s {
+	case SeverityCritical:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
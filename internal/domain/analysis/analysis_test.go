@@ -0,0 +1,257 @@
+package analysis
+
+import (
+	"strconv"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/scenario"
+	"testing"
+	"time"
+)
+
+func steadyScenario(qps int64) *scenario.Scenario {
+	return &scenario.Scenario{
+		ID:     "test",
+		Phases: []scenario.TrafficPhase{{Name: "steady", StartQPS: qps, EndQPS: qps, DurationSeconds: 600}},
+	}
+}
+
+func newComponent(id string, t component.Type, props component.Metadata) component.Component {
+	if props == nil {
+		props = component.Metadata{}
+	}
+	return component.Component{ID: id, Name: id, Type: t, Properties: props}
+}
+
+// chainDesign 建立 src → comps[0] → comps[1] → ... 的設計圖，流量穩定且種子固定
+func chainDesign(src component.Metadata, comps ...component.Component) *design.Design {
+	d := &design.Design{
+		ID:         "test-design",
+		ScenarioID: "test",
+		Properties: component.Metadata{"steady_traffic": true, "seed": float64(42)},
+		Components: []component.Component{newComponent("src", component.TrafficSource, src)},
+	}
+	prev := "src"
+	for _, c := range comps {
+		d.Components = append(d.Components, c)
+		d.Connections = append(d.Connections, design.Connection{FromID: prev, ToID: c.ID})
+		prev = c.ID
+	}
+	return d
+}
+
+// firstResult 以引擎逐秒評估，回傳第一個符合條件的結果
+func firstResult(t *testing.T, d *design.Design, s *scenario.Scenario, match func(*evaluation.Result) bool) *evaluation.Result {
+	t.Helper()
+	e := engine.NewSimpleEngine(nil, nil, nil, func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) })
+	for elapsed := int64(0); elapsed < 180; elapsed++ {
+		res, err := e.EvaluateDesign(d, s, elapsed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match(res) {
+			return res
+		}
+	}
+	t.Fatal("no tick matched")
+	return nil
+}
+
+func crashed(id string) func(*evaluation.Result) bool {
+	return func(res *evaluation.Result) bool {
+		for _, c := range res.CrashedComponentIDs {
+			if c == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestCrashCauses(t *testing.T) {
+	db := func(props component.Metadata) component.Component {
+		if props == nil {
+			props = component.Metadata{}
+		}
+		props["max_qps"] = 1000
+		return newComponent("db", component.Database, props)
+	}
+
+	tests := []struct {
+		name     string
+		design   *design.Design
+		qps      int64
+		match    func(*evaluation.Result) bool
+		target   string
+		cause    Cause
+		upstream string
+	}{
+		{
+			name:   "sustained overload",
+			design: chainDesign(nil, db(nil)),
+			qps:    3000,
+			match:  crashed("db"),
+			target: "db",
+			cause:  CauseOverload,
+		},
+		{
+			name:   "previous crash",
+			design: chainDesign(nil, db(component.Metadata{"crashed": true})),
+			qps:    100,
+			match:  crashed("db"),
+			target: "db",
+			cause:  CausePreviousCrash,
+		},
+		{
+			name:   "random failure",
+			design: chainDesign(component.Metadata{"enable_failures": true, "failure_probability": 1.0}, db(nil)),
+			qps:    100,
+			match:  crashed("db"),
+			target: "db",
+			cause:  CauseRandomFailure,
+		},
+		{
+			name: "burst",
+			design: chainDesign(component.Metadata{"start_qps": 500, "burst_traffic": true, "burst_probability": 1.0},
+				db(nil)),
+			qps: 100,
+			match: func(res *evaluation.Result) bool {
+				return res.IsBurstActive && crashed("db")(res)
+			},
+			target:   "db",
+			cause:    CauseBurst,
+			upstream: "src",
+		},
+		{
+			name: "attack",
+			design: chainDesign(component.Metadata{"enable_attacks": true, "attack_probability": 1.0},
+				db(nil)),
+			qps: 100,
+			match: func(res *evaluation.Result) bool {
+				return res.IsAttackActive && crashed("db")(res)
+			},
+			target:   "db",
+			cause:    CauseAttack,
+			upstream: "src",
+		},
+		{
+			name: "cold cache",
+			design: chainDesign(component.Metadata{"read_ratio": 100},
+				newComponent("cache", component.Cache, component.Metadata{"max_qps": 100000, "restartedAt": 0.0}),
+				db(nil)),
+			qps:      10000,
+			match:    crashed("db"),
+			target:   "db",
+			cause:    CauseCacheColdStart,
+			upstream: "cache",
+		},
+		{
+			name: "queue drain",
+			design: chainDesign(component.Metadata{"read_ratio": 0},
+				newComponent("mq", component.MessageQueue, component.Metadata{"max_qps": 100000, "backlog": 5000}),
+				db(nil)),
+			qps:      3000,
+			match:    crashed("db"),
+			target:   "db",
+			cause:    CauseQueueDrain,
+			upstream: "mq",
+		},
+		{
+			name: "out of memory",
+			design: chainDesign(nil,
+				newComponent("mq", component.MessageQueue, component.Metadata{"max_qps": 100000, "backlog": 200000}),
+				newComponent("worker", component.WebServer, component.Metadata{"max_qps": 100000})),
+			qps:    100,
+			match:  crashed("mq"),
+			target: "mq",
+			cause:  CauseOOM,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := firstResult(t, tt.design, steadyScenario(tt.qps), tt.match)
+			report := Analyze(tt.design, res)
+
+			var found *Finding
+			for i, f := range report.Findings {
+				if f.Kind == KindCrash && f.ComponentID == tt.target {
+					found = &report.Findings[i]
+				}
+			}
+			if found == nil {
+				t.Fatalf("no crash finding for %s in %+v", tt.target, report.Findings)
+			}
+			if found.Cause != tt.cause || found.UpstreamID != tt.upstream {
+				t.Errorf("cause = %s (upstream %q), want %s (upstream %q)", found.Cause, found.UpstreamID, tt.cause, tt.upstream)
+			}
+			if found.Severity != SeverityCritical || found.MessageID != "analysis.crash."+string(tt.cause) || found.Message == "" {
+				t.Errorf("finding = %+v, want a localized critical finding", *found)
+			}
+			if report.Findings[0].Kind != KindCrash {
+				t.Errorf("crash findings should sort first, got %+v", report.Findings[0])
+			}
+		})
+	}
+}
+
+// 每條路徑回報使用率最高的組件，嚴重程度依使用率分級
+func TestBottleneckFindings(t *testing.T) {
+	d := chainDesign(nil,
+		newComponent("web", component.WebServer, component.Metadata{"max_qps": 10000}),
+		newComponent("db", component.Database, component.Metadata{"max_qps": 1200}),
+	)
+	res := firstResult(t, d, steadyScenario(1000), func(*evaluation.Result) bool { return true })
+	report := Analyze(d, res)
+
+	if len(report.Findings) != 1 {
+		t.Fatalf("findings = %+v, want one bottleneck", report.Findings)
+	}
+	f := report.Findings[0]
+	if f.Kind != KindBottleneck || f.ComponentID != "db" || f.Severity != SeverityWarning {
+		t.Errorf("finding = %+v, want a warning bottleneck on db", f)
+	}
+	if want := []string{"src", "web", "db"}; len(f.Path) != len(want) || f.Path[0] != want[0] || f.Path[2] != want[2] {
+		t.Errorf("path = %v, want %v", f.Path, want)
+	}
+}
+
+// 每層兩個組件、九層的拓撲有 512 條路徑，列舉在 maxPaths 條時停止
+func TestPathsAreCapped(t *testing.T) {
+	d := &design.Design{
+		ID:         "wide",
+		Components: []component.Component{newComponent("src", component.TrafficSource, nil)},
+	}
+	prev := []string{"src"}
+	for layer := 0; layer < 9; layer++ {
+		var cur []string
+		for i := 0; i < 2; i++ {
+			id := "n" + strconv.Itoa(layer) + "-" + strconv.Itoa(i)
+			d.Components = append(d.Components, newComponent(id, component.WebServer, component.Metadata{"max_qps": 1000}))
+			for _, from := range prev {
+				d.Connections = append(d.Connections, design.Connection{FromID: from, ToID: id})
+			}
+			cur = append(cur, id)
+		}
+		prev = cur
+	}
+
+	a := &analyzer{design: d, comps: make(map[string]component.Component), adj: make(map[string][]string)}
+	for _, c := range d.Components {
+		a.comps[c.ID] = c
+	}
+	for _, conn := range d.Connections {
+		a.adj[conn.FromID] = append(a.adj[conn.FromID], conn.ToID)
+	}
+	if paths := a.paths(); len(paths) != maxPaths {
+		t.Errorf("paths = %d, want %d", len(paths), maxPaths)
+	}
+
+	res := &evaluation.Result{ComponentLoads: map[string]int64{"n8-0": 900}, ComponentEffectiveMaxQPS: map[string]int64{"n8-0": 1000}}
+	report := Analyze(d, res)
+	if len(report.Findings) == 0 || report.Findings[0].ComponentID != "n8-0" {
+		t.Errorf("findings = %+v, want the bottleneck on n8-0", report.Findings)
+	}
+}
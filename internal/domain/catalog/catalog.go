@@ -0,0 +1,96 @@
+package catalog
+
+import (
+	"fmt"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/i18n"
+)
+
+// SKU 是目錄中可購買的組件規格，成本與容量由目錄決定，不信任客戶端傳入的值
+type SKU struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Type            component.Type     `json:"type"`
+	SetupCost       float64            `json:"setup_cost"`           // 購買/建立成本
+	OperationalCost float64            `json:"operational_cost"`     // 每秒運作成本，0 代表使用該類型的預設成本
+	Properties      component.Metadata `json:"properties,omitempty"` // 由規格決定的屬性 (如 max_qps、base_latency)，會覆寫設計圖中的同名屬性
+	Options         []Option           `json:"options,omitempty"`    // 允許玩家調整的升級選項 (嚴格模式下其餘屬性會被忽略)
+}
+
+// Repository 定義組件目錄的存取介面
+type Repository interface {
+	GetByID(id string) (*SKU, error)
+	ListAll() ([]*SKU, error)
+}
+
+// Localized 回傳依語系翻譯名稱後的副本
+func (s *SKU) Localized(l i18n.Locale) *SKU {
+	out := *s
+	out.Name = i18n.TOr(l, "catalog."+s.ID+".name", s.Name, nil)
+	return &out
+}
+
+// Apply 以規格覆寫組件的類型、成本與規格屬性，組件其餘的屬性 (如 auto_scaling) 保留玩家的設定
+func (s *SKU) Apply(c *component.Component) error {
+	if c.Type != "" && c.Type != s.Type {
+		return &SKUError{ComponentID: c.ID, SKU: s.ID, MessageID: "error.sku_type_mismatch", Type: c.Type, Expected: s.Type}
+	}
+	c.Type = s.Type
+	if c.Name == "" {
+		c.Name = s.Name
+	}
+	c.SetupCost = s.SetupCost
+	c.OperationalCost = s.OperationalCost
+	if c.Properties == nil {
+		c.Properties = component.Metadata{}
+	}
+	for k, v := range s.Properties {
+		c.Properties[k] = v
+	}
+	return nil
+}
+
+// Resolve 將設計圖中引用 SKU 的組件替換為目錄中的規格，沒有引用 SKU 的組件不受影響
+func Resolve(d *design.Design, repo Repository) error {
+	for i := range d.Components {
+		c := &d.Components[i]
+		if c.SKU == "" {
+			continue
+		}
+		sku, err := repo.GetByID(c.SKU)
+		if err != nil {
+			return &SKUError{ComponentID: c.ID, SKU: c.SKU, MessageID: "error.unknown_sku"}
+		}
+		if err := sku.Apply(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SKUError 表示組件引用了不存在的 SKU，或 SKU 與組件類型不符
+type SKUError struct {
+	ComponentID string
+	SKU         string
+	MessageID   string
+	Type        component.Type // 組件宣告的類型 (類型不符時)
+	Expected    component.Type // SKU 的類型 (類型不符時)
+}
+
+func (e *SKUError) Error() string {
+	if e.Expected != "" {
+		return fmt.Sprintf("組件 %s 的類型 %s 與 SKU %s (%s) 不符", e.ComponentID, e.Type, e.SKU, e.Expected)
+	}
+	return fmt.Sprintf("組件 %s 引用了不存在的 SKU: %s", e.ComponentID, e.SKU)
+}
+
+// Params 回傳訊息模板的參數
+func (e *SKUError) Params() map[string]interface{} {
+	return map[string]interface{}{
+		"component": e.ComponentID,
+		"sku":       e.SKU,
+		"type":      string(e.Type),
+		"expected":  string(e.Expected),
+	}
+}
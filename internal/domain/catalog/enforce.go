@@ -0,0 +1,239 @@
+package catalog
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+)
+
+// Option 是 SKU 允許玩家調整的升級選項，範圍與列舉未設定時沿用屬性定義
+type Option struct {
+	Property string   `json:"property"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+}
+
+// controlledProperties 是嚴格模式下一律由目錄 (或類型預設值) 決定的屬性
+var controlledProperties = map[string]bool{
+	"max_qps":          true,
+	"base_latency":     true,
+	"setup_cost":       true,
+	"operational_cost": true,
+
+	"price_per_million_requests": true,
+	"egress_per_gb":              true,
+}
+
+// Enforce 以目錄規格重建設計圖 (嚴格模式)，回傳複製後的設計圖與被忽略的客戶端設定
+//   - 成本、容量與延遲一律採用 SKU 的值；沒有指定 SKU 的組件使用規格屬性完全相符的 SKU (如前端元件庫建立的組件)，
+//     找不到時使用該類型的第一個 SKU，沒有任何 SKU 的類型 (如自訂組件) 使用類型預設值
+//   - 其餘屬性只保留 SKU 的升級選項 (且必須在允許範圍內)
+//   - 模擬狀態 (如 crashed、restartedAt、backlog、replica_start_times 與設計圖的 retention_rate) 由引擎產生，
+//     客戶端送來的值一律清除，評估從全新的狀態開始
+func Enforce(d *design.Design, repo Repository) (*design.Design, []evaluation.DiscardedProperty, error) {
+	skus, err := repo.ListAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := d.Clone()
+	for _, spec := range design.PropertySchema.Properties {
+		if spec.State {
+			delete(out.Properties, spec.Name)
+		}
+	}
+	var discarded []evaluation.DiscardedProperty
+	for i := range out.Components {
+		c := &out.Components[i]
+		var sku *SKU
+		if c.SKU != "" {
+			if sku, err = repo.GetByID(c.SKU); err != nil {
+				return nil, nil, &SKUError{ComponentID: c.ID, SKU: c.SKU, MessageID: "error.unknown_sku"}
+			}
+			if c.Type != "" && c.Type != sku.Type {
+				return nil, nil, &SKUError{ComponentID: c.ID, SKU: c.SKU, MessageID: "error.sku_type_mismatch", Type: c.Type, Expected: sku.Type}
+			}
+		} else if sku = matchingSKU(skus, *c); sku == nil {
+			if sku = defaultSKU(skus, c.Type); sku != nil {
+				discarded = append(discarded, discard(c, "sku", nil, sku.ID, "discarded.default_sku"))
+			}
+		}
+		discarded = append(discarded, enforceComponent(c, sku)...)
+	}
+	return out, discarded, nil
+}
+
+// matchingSKU 回傳規格屬性與組件完全相符的 SKU (沒有規格屬性的 SKU 視為相符)
+func matchingSKU(skus []*SKU, c component.Component) *SKU {
+	for _, s := range skus {
+		if s.Type != c.Type {
+			continue
+		}
+		matched := true
+		for k, v := range s.Properties {
+			if !sameValue(c.Properties[k], v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return s
+		}
+	}
+	return nil
+}
+
+// defaultSKU 回傳目錄中該類型的第一個 SKU
+func defaultSKU(skus []*SKU, t component.Type) *SKU {
+	for _, s := range skus {
+		if s.Type == t {
+			return s
+		}
+	}
+	return nil
+}
+
+// enforceComponent 以 SKU (可能為 nil) 覆寫單一組件，回傳被忽略的設定
+func enforceComponent(c *component.Component, sku *SKU) []evaluation.DiscardedProperty {
+	var discarded []evaluation.DiscardedProperty
+	var setupCost, operationalCost float64
+	var specProps component.Metadata
+	if sku != nil {
+		c.Type, c.SKU = sku.Type, sku.ID
+		setupCost, operationalCost = sku.SetupCost, sku.OperationalCost
+		specProps = sku.Properties
+	}
+
+	// expected 回傳受控屬性實際採用的值，nil 代表使用類型預設值
+	expected := func(k string) interface{} {
+		switch k {
+		case "setup_cost":
+			return setupCost
+		case "operational_cost":
+			if operationalCost == 0 {
+				return nil
+			}
+			return operationalCost
+		}
+		return specProps[k]
+	}
+
+	// 成本欄位
+	if c.SetupCost != 0 && !sameValue(c.SetupCost, expected("setup_cost")) {
+		discarded = append(discarded, controlledDiscard(c, "setup_cost", c.SetupCost, expected("setup_cost")))
+	}
+	if c.OperationalCost != 0 && !sameValue(c.OperationalCost, expected("operational_cost")) {
+		discarded = append(discarded, controlledDiscard(c, "operational_cost", c.OperationalCost, expected("operational_cost")))
+	}
+	c.SetupCost, c.OperationalCost = setupCost, operationalCost
+
+	schema := component.SchemaFor(c.Type)
+	options := sku.optionSchema(schema)
+
+	keys := make([]string, 0, len(c.Properties))
+	for k := range c.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	props := component.Metadata{}
+	for _, k := range keys {
+		v := c.Properties[k]
+		if v == nil {
+			continue
+		}
+		if spec, ok := schema.Lookup(k); ok && spec.State {
+			continue // 模擬狀態不接受客戶端的值
+		}
+		if _, ok := specProps[k]; ok || controlledProperties[k] {
+			if applied := expected(k); !sameValue(v, applied) {
+				discarded = append(discarded, controlledDiscard(c, k, v, applied))
+			}
+			continue
+		}
+		if _, ok := options.Lookup(k); !ok {
+			discarded = append(discarded, discard(c, k, v, nil, "discarded.not_allowed"))
+			continue
+		}
+		if errs := options.Validate(component.Metadata{k: v}); len(errs) > 0 {
+			discarded = append(discarded, discard(c, k, v, nil, "discarded.out_of_range"))
+			continue
+		}
+		props[k] = v
+	}
+	for k, v := range specProps {
+		props[k] = v
+	}
+	c.Properties = props
+	return discarded
+}
+
+// optionSchema 依升級選項收窄屬性定義的範圍
+func (s *SKU) optionSchema(schema component.Schema) component.Schema {
+	out := component.Schema{Type: schema.Type}
+	if s == nil {
+		return out
+	}
+	for _, opt := range s.Options {
+		spec, ok := schema.Lookup(opt.Property)
+		if !ok {
+			continue
+		}
+		if opt.Min != nil {
+			spec.Min = opt.Min
+		}
+		if opt.Max != nil {
+			spec.Max = opt.Max
+		}
+		if len(opt.Enum) > 0 {
+			spec.Enum = opt.Enum
+		}
+		out.Properties = append(out.Properties, spec)
+	}
+	return out
+}
+
+// controlledDiscard 記錄被目錄值 (applied 為 nil 時為類型預設值) 取代的客戶端設定
+func controlledDiscard(c *component.Component, property string, value, applied interface{}) evaluation.DiscardedProperty {
+	if applied == nil {
+		return discard(c, property, value, nil, "discarded.default")
+	}
+	return discard(c, property, value, applied, "discarded.catalog")
+}
+
+func discard(c *component.Component, property string, value, applied interface{}, messageID string) evaluation.DiscardedProperty {
+	name := c.Name
+	if name == "" {
+		name = c.ID
+	}
+	return evaluation.DiscardedProperty{
+		ComponentID: c.ID,
+		Property:    property,
+		Value:       value,
+		Applied:     applied,
+		MessageID:   messageID,
+		Params:      map[string]interface{}{"component": name, "property": property, "value": display(value), "applied": display(applied)},
+	}
+}
+
+// display 將數值格式化為一般寫法 (避免 1e+06 這類科學記號)
+func display(v interface{}) interface{} {
+	if n, ok := (component.Metadata{"v": v}).Float("v"); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return v
+}
+
+// sameValue 比較兩個屬性值，數值不分 int / float64
+func sameValue(a, b interface{}) bool {
+	x, okA := component.Metadata{"v": a}.Float("v")
+	y, okB := component.Metadata{"v": b}.Float("v")
+	if okA && okB {
+		return x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,193 @@
+package catalog
+
+import (
+	"errors"
+	"reflect"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"testing"
+)
+
+// stubRepository 是測試用的組件目錄，ListAll 依宣告順序回傳
+type stubRepository []*SKU
+
+func (r stubRepository) GetByID(id string) (*SKU, error) {
+	for _, s := range r {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, errors.New("sku not found")
+}
+
+func (r stubRepository) ListAll() ([]*SKU, error) {
+	return r, nil
+}
+
+var testCatalog = stubRepository{
+	{
+		ID: "web-small", Type: component.WebServer, SetupCost: 100, OperationalCost: 0.5,
+		Properties: component.Metadata{"max_qps": 1000, "base_latency": 20},
+		Options:    []Option{{Property: "auto_scaling"}, {Property: "max_replicas", Max: component.Bound(4)}},
+	},
+	{
+		ID: "web-large", Type: component.WebServer, SetupCost: 400, OperationalCost: 2,
+		Properties: component.Metadata{"max_qps": 5000, "base_latency": 20},
+	},
+	{
+		ID: "db-standard", Type: component.Database, SetupCost: 300,
+		Properties: component.Metadata{"max_qps": 2000},
+		Options:    []Option{{Property: "replication_mode", Enum: []string{"SINGLE", "MASTER_SLAVE"}}, {Property: "slave_count"}},
+	},
+}
+
+// discardedKeys 執行 Enforce，被忽略的設定以 "屬性:訊息 ID" 表示
+func discardedKeys(t *testing.T, d *design.Design, repo Repository) (*design.Design, []string) {
+	t.Helper()
+	out, discarded, err := Enforce(d, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, dp := range discarded {
+		keys = append(keys, dp.Property+":"+dp.MessageID)
+	}
+	return out, keys
+}
+
+func TestEnforceComponent(t *testing.T) {
+	tests := []struct {
+		name      string
+		comp      component.Component
+		sku       string
+		setupCost float64
+		props     component.Metadata
+		discarded []string
+	}{
+		{
+			name:      "catalog values replace client specs",
+			comp:      component.Component{ID: "web", Type: component.WebServer, SKU: "web-small", SetupCost: 1, Properties: component.Metadata{"max_qps": 99999, "auto_scaling": true}},
+			sku:       "web-small",
+			setupCost: 100,
+			props:     component.Metadata{"max_qps": 1000, "base_latency": 20, "auto_scaling": true},
+			discarded: []string{"setup_cost:discarded.catalog", "max_qps:discarded.catalog"},
+		},
+		{
+			name:      "option within range",
+			comp:      component.Component{ID: "web", Type: component.WebServer, SKU: "web-small", Properties: component.Metadata{"max_replicas": 4}},
+			sku:       "web-small",
+			setupCost: 100,
+			props:     component.Metadata{"max_qps": 1000, "base_latency": 20, "max_replicas": 4},
+		},
+		{
+			name:      "option out of range",
+			comp:      component.Component{ID: "web", Type: component.WebServer, SKU: "web-small", Properties: component.Metadata{"max_replicas": 10}},
+			sku:       "web-small",
+			setupCost: 100,
+			props:     component.Metadata{"max_qps": 1000, "base_latency": 20},
+			discarded: []string{"max_replicas:discarded.out_of_range"},
+		},
+		{
+			name:      "option outside the SKU's enum",
+			comp:      component.Component{ID: "db", Type: component.Database, SKU: "db-standard", Properties: component.Metadata{"replication_mode": "SLAVE"}},
+			sku:       "db-standard",
+			setupCost: 300,
+			props:     component.Metadata{"max_qps": 2000},
+			discarded: []string{"replication_mode:discarded.out_of_range"},
+		},
+		{
+			name:      "property that is not an option",
+			comp:      component.Component{ID: "web", Type: component.WebServer, SKU: "web-large", Properties: component.Metadata{"auto_scaling": true}},
+			sku:       "web-large",
+			setupCost: 400,
+			props:     component.Metadata{"max_qps": 5000, "base_latency": 20},
+			discarded: []string{"auto_scaling:discarded.not_allowed"},
+		},
+		{
+			name:      "matching SKU without an ID",
+			comp:      component.Component{ID: "web", Type: component.WebServer, Properties: component.Metadata{"max_qps": 5000, "base_latency": 20}},
+			sku:       "web-large",
+			setupCost: 400,
+			props:     component.Metadata{"max_qps": 5000, "base_latency": 20},
+		},
+		{
+			name:      "default SKU when nothing matches",
+			comp:      component.Component{ID: "web", Type: component.WebServer, Properties: component.Metadata{"max_qps": 3000}},
+			sku:       "web-small",
+			setupCost: 100,
+			props:     component.Metadata{"max_qps": 1000, "base_latency": 20},
+			discarded: []string{"sku:discarded.default_sku", "max_qps:discarded.catalog"},
+		},
+		{
+			name:      "type without SKUs uses type defaults",
+			comp:      component.Component{ID: "cache", Type: component.Cache, OperationalCost: 0.01, Properties: component.Metadata{"max_qps": 100000, "pricing": "spot"}},
+			props:     component.Metadata{},
+			discarded: []string{"operational_cost:discarded.default", "max_qps:discarded.default", "pricing:discarded.not_allowed"},
+		},
+		{
+			name:      "simulation state is stripped silently",
+			comp:      component.Component{ID: "web", Type: component.WebServer, SKU: "web-small", Properties: component.Metadata{"crashed": true, "restartedAt": 3.0, "replica_start_times": []interface{}{1.0}}},
+			sku:       "web-small",
+			setupCost: 100,
+			props:     component.Metadata{"max_qps": 1000, "base_latency": 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &design.Design{ID: "d", Components: []component.Component{tt.comp}}
+			out, discarded := discardedKeys(t, d, testCatalog)
+
+			c := out.Components[0]
+			if c.SKU != tt.sku || c.SetupCost != tt.setupCost {
+				t.Errorf("sku = %q setup = %v, want %q %v", c.SKU, c.SetupCost, tt.sku, tt.setupCost)
+			}
+			if !reflect.DeepEqual(c.Properties, tt.props) {
+				t.Errorf("properties = %v, want %v", c.Properties, tt.props)
+			}
+			if !reflect.DeepEqual(discarded, tt.discarded) {
+				t.Errorf("discarded = %v, want %v", discarded, tt.discarded)
+			}
+		})
+	}
+}
+
+// 設計圖全域的模擬狀態被清除，其餘設定保留；傳入的設計圖不被修改
+func TestEnforceStripsDesignState(t *testing.T) {
+	d := &design.Design{
+		ID:         "d",
+		Properties: component.Metadata{"retention_rate": 5.0, "recent_latencies": []interface{}{1.0}, "seed": float64(7)},
+		Components: []component.Component{{ID: "web", Type: component.WebServer, SKU: "web-small", Properties: component.Metadata{"crashed": true}}},
+	}
+	out, _ := discardedKeys(t, d, testCatalog)
+
+	if want := (component.Metadata{"seed": float64(7)}); !reflect.DeepEqual(out.Properties, want) {
+		t.Errorf("design properties = %v, want %v", out.Properties, want)
+	}
+	if d.Properties["retention_rate"] != 5.0 {
+		t.Error("Enforce modified the caller's design properties")
+	}
+	if !d.Components[0].Properties.Enabled("crashed") {
+		t.Error("Enforce modified the caller's components")
+	}
+}
+
+func TestEnforceSKUErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		comp      component.Component
+		messageID string
+	}{
+		{"unknown SKU", component.Component{ID: "web", Type: component.WebServer, SKU: "web-huge"}, "error.unknown_sku"},
+		{"type mismatch", component.Component{ID: "web", Type: component.Cache, SKU: "web-small"}, "error.sku_type_mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Enforce(&design.Design{Components: []component.Component{tt.comp}}, testCatalog)
+			var skuErr *SKUError
+			if !errors.As(err, &skuErr) || skuErr.MessageID != tt.messageID {
+				t.Errorf("err = %v, want SKUError %s", err, tt.messageID)
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ type Component struct {
 	ID              string   `json:"id"`
 	Name            string   `json:"name"`
 	Type            Type     `json:"type"`
+	SKU             string   `json:"sku,omitempty"`    // 引用組件目錄中的規格，儲存時以目錄的成本與容量覆寫
 	SetupCost       float64  `json:"setup_cost"`       // 購買/建立成本
 	OperationalCost float64  `json:"operational_cost"` // 每秒運作成本
 	Properties      Metadata `json:"properties"`       // 儲存組件的具體參數（如：記憶體大小、連線數限制）
@@ -37,3 +38,18 @@ type Component struct {
 // "base_latency": 50
 // "max_connections": 500
 type Metadata map[string]interface{}
+
+// Clone 複製一份屬性，讓模擬過程中的狀態變更 (如崩潰、積壓) 不會影響原始設計
+func (m Metadata) Clone() Metadata {
+	if m == nil {
+		return nil
+	}
+	out := make(Metadata, len(m))
+	for k, v := range m {
+		if list, ok := v.([]interface{}); ok {
+			v = append([]interface{}(nil), list...)
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package component
+
+import "fmt"
+
+// Definition 以資料描述一種自訂組件類型，讓講師不需重新編譯即可新增組件 (如 GraphQL Federation Gateway)
+type Definition struct {
+	Type               Type    `json:"type"`
+	Name               string  `json:"name"`
+	Description        string  `json:"description,omitempty"`
+	MaxQPS             int64   `json:"max_qps"`                       // 預設最大 QPS (組件未設定 max_qps 時使用)
+	LatencyMS          float64 `json:"latency_ms"`                    // 預設延遲 (組件未設定 base_latency 時使用)
+	OperationalCost    float64 `json:"operational_cost"`              // 預設每秒維運成本
+	CrashThreshold     float64 `json:"crash_threshold,omitempty"`     // 負載超過容量幾倍時崩潰 (預設 1.5)
+	ReadAbsorption     float64 `json:"read_absorption,omitempty"`     // 類似快取：此比例的讀取在此取得資料，不再往下游傳遞 (0 ~ 1)
+	DataSink           bool    `json:"data_sink,omitempty"`           // 是否為資料的最終落點 (抵達的讀寫視為成功取得資料)
+	ConsistencyPenalty float64 `json:"consistency_penalty,omitempty"` // 使用此組件對資料一致性分數的扣分
+	Sensitive          bool    `json:"sensitive,omitempty"`           // 是否為核心資料節點 (抵達的惡意流量會降低安全分數)
+	Gateway            bool    `json:"gateway,omitempty"`             // 是否能保護下游的核心資料節點
+}
+
+// builtinTypes 是內建的組件類型，自訂組件不得覆寫
+var builtinTypes = map[Type]bool{
+	TrafficSource: true, LoadBalancer: true, WebServer: true, Database: true,
+	Cache: true, MessageQueue: true, CDN: true, WAF: true,
+	ObjectStorage: true, SearchEngine: true, AutoScalingGroup: true, APIGateway: true,
+	NoSQL: true, Worker: true, VideoTranscoding: true, ExternalAPI: true,
+}
+
+// IsBuiltin 判斷是否為內建的組件類型
+func (t Type) IsBuiltin() bool {
+	return builtinTypes[t]
+}
+
+// Validate 檢查定義的欄位是否合理
+func (d Definition) Validate() error {
+	switch {
+	case d.Type == "":
+		return fmt.Errorf("自訂組件缺少 type")
+	case d.Type.IsBuiltin():
+		return fmt.Errorf("自訂組件 %s 不能覆寫內建類型", d.Type)
+	case d.MaxQPS < 0:
+		return fmt.Errorf("自訂組件 %s 的 max_qps 不能為負數", d.Type)
+	case d.LatencyMS < 0 || d.OperationalCost < 0 || d.CrashThreshold < 0 || d.ConsistencyPenalty < 0:
+		return fmt.Errorf("自訂組件 %s 的延遲、成本、崩潰閾值與一致性扣分不能為負數", d.Type)
+	case d.ReadAbsorption < 0 || d.ReadAbsorption > 1:
+		return fmt.Errorf("自訂組件 %s 的 read_absorption 必須介於 0 ~ 1", d.Type)
+	}
+	return nil
+}
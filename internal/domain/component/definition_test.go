@@ -0,0 +1,31 @@
+package component
+
+import "testing"
+
+func TestDefinitionValidate(t *testing.T) {
+	valid := Definition{Type: "GraphQLGateway", Name: "GraphQL Gateway", MaxQPS: 2000, LatencyMS: 8, OperationalCost: 0.2, ReadAbsorption: 0.3}
+
+	tests := []struct {
+		name    string
+		modify  func(d *Definition)
+		wantErr bool
+	}{
+		{"valid", func(d *Definition) {}, false},
+		{"missing type", func(d *Definition) { d.Type = "" }, true},
+		{"built-in type", func(d *Definition) { d.Type = Database }, true},
+		{"negative max_qps", func(d *Definition) { d.MaxQPS = -1 }, true},
+		{"negative latency", func(d *Definition) { d.LatencyMS = -1 }, true},
+		{"negative crash threshold", func(d *Definition) { d.CrashThreshold = -1 }, true},
+		{"read absorption above 1", func(d *Definition) { d.ReadAbsorption = 1.5 }, true},
+		{"full read absorption", func(d *Definition) { d.ReadAbsorption = 1 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid
+			tt.modify(&d)
+			if err := d.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
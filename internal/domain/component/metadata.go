@@ -0,0 +1,76 @@
+package component
+
+// 型別化的屬性存取：JSON 解碼後的數字為 float64，程式內建立的設計可能為 int 或 int64，
+// 統一在這裡轉換，呼叫端不需要再逐一嘗試型別斷言。
+
+// Float 讀取數值屬性
+func (m Metadata) Float(key string) (float64, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// FloatOr 讀取數值屬性，未設定時回傳預設值
+func (m Metadata) FloatOr(key string, def float64) float64 {
+	if v, ok := m.Float(key); ok {
+		return v
+	}
+	return def
+}
+
+// Int 讀取整數屬性 (小數部分會被捨去)
+func (m Metadata) Int(key string) (int64, bool) {
+	if v, ok := m[key].(int64); ok {
+		return v, true
+	}
+	v, ok := m.Float(key)
+	return int64(v), ok
+}
+
+// IntOr 讀取整數屬性，未設定時回傳預設值
+func (m Metadata) IntOr(key string, def int64) int64 {
+	if v, ok := m.Int(key); ok {
+		return v
+	}
+	return def
+}
+
+// Bool 讀取布林屬性
+func (m Metadata) Bool(key string) (bool, bool) {
+	v, ok := m[key].(bool)
+	return v, ok
+}
+
+// Enabled 判斷布林屬性是否為 true (未設定視為 false)
+func (m Metadata) Enabled(key string) bool {
+	v, _ := m.Bool(key)
+	return v
+}
+
+// Text 讀取字串屬性
+func (m Metadata) Text(key string) (string, bool) {
+	v, ok := m[key].(string)
+	return v, ok
+}
+
+// TextOr 讀取字串屬性，未設定時回傳預設值
+func (m Metadata) TextOr(key string, def string) string {
+	if v, ok := m.Text(key); ok {
+		return v
+	}
+	return def
+}
+
+// List 讀取陣列屬性
+func (m Metadata) List(key string) ([]interface{}, bool) {
+	v, ok := m[key].([]interface{})
+	return v, ok
+}
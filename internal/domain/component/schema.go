@@ -0,0 +1,313 @@
+package component
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"system-design-game/internal/i18n"
+)
+
+// PropertyKind 定義屬性的資料型別
+type PropertyKind string
+
+const (
+	KindNumber  PropertyKind = "number"
+	KindInteger PropertyKind = "integer"
+	KindBoolean PropertyKind = "boolean"
+	KindString  PropertyKind = "string"
+	KindList    PropertyKind = "list"
+)
+
+// PropertySpec 描述一個屬性的名稱、型別、單位、預設值與允許範圍
+type PropertySpec struct {
+	Name        string       `json:"name"`
+	Kind        PropertyKind `json:"kind"`
+	Unit        string       `json:"unit,omitempty"` // 如 qps、ms、%、s、$/s
+	Description string       `json:"description"`
+	Default     interface{}  `json:"default,omitempty"`
+	Min         *float64     `json:"min,omitempty"`
+	Max         *float64     `json:"max,omitempty"`
+	Enum        []string     `json:"enum,omitempty"`
+	State       bool         `json:"state,omitempty"` // 模擬狀態 (由引擎或前端每個 tick 寫回)，屬性編輯器不需顯示
+}
+
+// Schema 是一組屬性定義 (某一種組件類型，或設計圖的全域屬性)
+type Schema struct {
+	Type       Type           `json:"type,omitempty"`
+	Properties []PropertySpec `json:"properties"`
+}
+
+// PropertyError 是一筆屬性驗證錯誤
+type PropertyError struct {
+	ComponentID string                 `json:"component_id,omitempty"` // 空白代表設計圖的全域屬性
+	Property    string                 `json:"property"`
+	MessageID   string                 `json:"message_id"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Message     string                 `json:"message"`
+}
+
+// ValidationError 彙整所有屬性驗證錯誤
+type ValidationError struct {
+	Errors []PropertyError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, pe := range e.Errors {
+		msgs[i] = pe.Message
+	}
+	return "屬性驗證失敗: " + strings.Join(msgs, "; ")
+}
+
+// Bound 回傳數值的指標，用於設定 Min / Max
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// commonProperties 是所有組件共用的屬性
+var commonProperties = []PropertySpec{
+	{Name: "max_qps", Kind: KindInteger, Unit: "qps", Description: "單一節點每秒最多能處理的請求數", Min: Bound(0)},
+	{Name: "base_latency", Kind: KindNumber, Unit: "ms", Description: "基礎延遲，未設定時使用該類型的預設延遲", Min: Bound(0)},
+	{Name: "setup_cost", Kind: KindNumber, Unit: "$", Description: "購買/建立成本", Min: Bound(0)},
+	{Name: "operational_cost", Kind: KindNumber, Unit: "$/s", Description: "每秒運作成本", Min: Bound(0)},
+	{Name: "pricing", Kind: KindString, Description: "計價方式：on_demand 依使用付費、reserved 便宜 40% 但沒有運作也要付費、spot 便宜 70% 但可能被回收", Default: "on_demand", Enum: []string{"on_demand", "reserved", "spot"}},
+	{Name: "spot_interruption_probability", Kind: KindNumber, Description: "spot 節點每 60 秒被回收 (中斷 10 秒) 的機率", Default: 0.05, Min: Bound(0), Max: Bound(1)},
+	{Name: "price_per_million_requests", Kind: KindNumber, Unit: "$", Description: "按量計費：每百萬個請求的費用 (如 Serverless)，未設定時使用該類型的預設", Min: Bound(0)},
+	{Name: "egress_per_gb", Kind: KindNumber, Unit: "$/GB", Description: "處理的流量每 GB 的傳輸費用", Min: Bound(0)},
+	{Name: "crashed", Kind: KindBoolean, Description: "是否已崩潰 (需手動重啟)", State: true},
+	{Name: "restartedAt", Kind: KindNumber, Unit: "s", Description: "最近一次重啟的時間，重啟後 5 秒內不會再崩潰", State: true},
+}
+
+var autoScalingProperties = []PropertySpec{
+	{Name: "auto_scaling", Kind: KindBoolean, Description: "是否啟用 Auto Scaling", Default: false},
+	{Name: "max_replicas", Kind: KindInteger, Description: "最多擴展到幾台", Default: 5, Min: Bound(1), Max: Bound(100)},
+	{Name: "scale_up_threshold", Kind: KindNumber, Unit: "%", Description: "資源使用率超過此值時擴展", Default: 70, Min: Bound(1), Max: Bound(100)},
+	{Name: "warmup_seconds", Kind: KindInteger, Unit: "s", Description: "新副本的暖機時間", Default: 10, Min: Bound(0)},
+	{Name: "scale_metric", Kind: KindString, Description: "擴展依據的指標", Default: "cpu", Enum: []string{"cpu", "ram"}},
+	{Name: "replica_start_times", Kind: KindList, Unit: "s", Description: "額外副本的啟動時間", State: true},
+}
+
+var replicationModeProperty = PropertySpec{
+	Name: "replication_mode", Kind: KindString, Description: "複寫模式 (SLAVE 只能處理讀取)", Default: "SINGLE", Enum: []string{"SINGLE", "MASTER_SLAVE", "SLAVE"},
+}
+
+// schemas 是各組件類型特有的屬性 (不含共用屬性)
+var schemas = map[Type][]PropertySpec{
+	TrafficSource: {
+		{Name: "start_qps", Kind: KindInteger, Unit: "qps", Description: "在關卡流量之外額外加上的初始流量", Default: 0, Min: Bound(0)},
+		{Name: "read_ratio", Kind: KindNumber, Unit: "%", Description: "讀取請求所佔的比例", Default: 80, Min: Bound(0), Max: Bound(100)},
+		{Name: "burst_traffic", Kind: KindBoolean, Description: "是否啟用突發流量", Default: false},
+		{Name: "burst_probability", Kind: KindNumber, Description: "每 10 秒發生突發流量的機率", Default: 0.3, Min: Bound(0), Max: Bound(1)},
+		{Name: "enable_attacks", Kind: KindBoolean, Description: "是否啟用 DDoS 攻擊", Default: false},
+		{Name: "attack_probability", Kind: KindNumber, Description: "每 40 秒發動攻擊的機率", Default: 0.5, Min: Bound(0), Max: Bound(1)},
+		{Name: "enable_failures", Kind: KindBoolean, Description: "是否啟用隨機硬體故障", Default: false},
+		{Name: "failure_probability", Kind: KindNumber, Description: "每個組件每 60 秒故障的機率", Default: 0.02, Min: Bound(0), Max: Bound(1)},
+		{Name: "read_size_kb", Kind: KindNumber, Unit: "KB", Description: "每個讀取請求 (含回應) 的大小，用於計算 egress 成本", Default: 20, Min: Bound(0)},
+		{Name: "write_size_kb", Kind: KindNumber, Unit: "KB", Description: "每個寫入請求的大小，用於計算 egress 成本", Default: 5, Min: Bound(0)},
+		{Name: "attack_size_kb", Kind: KindNumber, Unit: "KB", Description: "每個惡意請求的大小，用於計算 egress 成本", Default: 1, Min: Bound(0)},
+	},
+	WebServer:        autoScalingProperties,
+	AutoScalingGroup: autoScalingProperties,
+	Database: {
+		replicationModeProperty,
+		{Name: "slave_count", Kind: KindInteger, Description: "主從架構下的 Slave 數量，每台增加一倍讀取能力", Default: 0, Min: Bound(0), Max: Bound(10)},
+	},
+	NoSQL:         {replicationModeProperty},
+	ObjectStorage: {replicationModeProperty},
+	SearchEngine:  {replicationModeProperty},
+	MessageQueue: {
+		{Name: "delivery_mode", Kind: KindString, Description: "PUSH 會將積壓訊息全數推給消費者，PULL 由消費者依能力拉取", Default: "PUSH", Enum: []string{"PUSH", "PULL"}},
+		{Name: "backlog_alert", Kind: KindInteger, Description: "積壓超過此數量時發出警告事件", Default: 10000, Min: Bound(0)},
+		{Name: "backlog", Kind: KindInteger, Description: "目前積壓的訊息數", State: true},
+	},
+	ExternalAPI: {
+		{Name: "sla", Kind: KindNumber, Unit: "%", Description: "第三方服務的成功率", Default: 99, Min: Bound(0), Max: Bound(100)},
+	},
+}
+
+// SchemaFor 回傳組件類型的屬性定義 (共用屬性 + 類型特有屬性)，未知類型 (如自訂組件) 只有共用屬性
+func SchemaFor(t Type) Schema {
+	props := append([]PropertySpec(nil), commonProperties...)
+	props = append(props, schemas[t]...)
+	return Schema{Type: t, Properties: props}
+}
+
+// Schemas 回傳所有內建組件類型的屬性定義 (依類型排序)
+func Schemas() []Schema {
+	out := make([]Schema, 0, len(builtinTypes))
+	for t := range builtinTypes {
+		out = append(out, SchemaFor(t))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
+
+// Lookup 依名稱尋找屬性定義
+func (s Schema) Lookup(name string) (PropertySpec, bool) {
+	for _, p := range s.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return PropertySpec{}, false
+}
+
+// Validate 檢查屬性是否符合定義：未知的屬性、型別錯誤、超出範圍與不在列舉中的值都會回報
+// 值為 null 視為未設定
+func (s Schema) Validate(m Metadata) []PropertyError {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []PropertyError
+	for _, key := range keys {
+		v := m[key]
+		if v == nil {
+			continue
+		}
+		spec, ok := s.Lookup(key)
+		if !ok {
+			pe := PropertyError{Property: key, MessageID: "schema.unknown", Params: map[string]interface{}{"property": key}}
+			if suggestion := s.suggest(key); suggestion != "" {
+				pe.MessageID = "schema.unknown_suggest"
+				pe.Params["suggestion"] = suggestion
+			}
+			errs = append(errs, pe)
+			continue
+		}
+		if pe, ok := spec.check(v); !ok {
+			errs = append(errs, pe)
+		}
+	}
+	return errs
+}
+
+// check 檢查單一屬性值
+func (p PropertySpec) check(v interface{}) (PropertyError, bool) {
+	fail := func(id string, params map[string]interface{}) (PropertyError, bool) {
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		params["property"] = p.Name
+		return PropertyError{Property: p.Name, MessageID: id, Params: params}, false
+	}
+	typeError := func() (PropertyError, bool) {
+		return fail("schema.type", map[string]interface{}{"kind": string(p.Kind)})
+	}
+
+	m := Metadata{p.Name: v}
+	switch p.Kind {
+	case KindNumber, KindInteger:
+		n, ok := m.Float(p.Name)
+		if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+			return typeError()
+		}
+		if p.Kind == KindInteger && n != math.Trunc(n) {
+			return typeError()
+		}
+		if p.Min != nil && n < *p.Min {
+			return fail("schema.min", map[string]interface{}{"min": *p.Min})
+		}
+		if p.Max != nil && n > *p.Max {
+			return fail("schema.max", map[string]interface{}{"max": *p.Max})
+		}
+	case KindBoolean:
+		if _, ok := m.Bool(p.Name); !ok {
+			return typeError()
+		}
+	case KindString:
+		str, ok := m.Text(p.Name)
+		if !ok {
+			return typeError()
+		}
+		if len(p.Enum) > 0 && !contains(p.Enum, str) {
+			return fail("schema.enum", map[string]interface{}{"enum": strings.Join(p.Enum, ", ")})
+		}
+	case KindList:
+		if _, ok := m.List(p.Name); !ok {
+			return typeError()
+		}
+	}
+	return PropertyError{}, true
+}
+
+// suggest 找出與未知屬性名稱最接近的已知屬性 (編輯距離 2 以內)，用於提示拼字錯誤
+func (s Schema) suggest(name string) string {
+	best, bestDist := "", 3
+	for _, p := range s.Properties {
+		if d := editDistance(name, p.Name); d < bestDist {
+			best, bestDist = p.Name, d
+		}
+	}
+	return best
+}
+
+// editDistance 計算兩個字串的編輯距離 (相鄰字元對調如 qps -> qsp 只算一次)
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateProperties 依組件類型的屬性定義檢查組件屬性
+func (c Component) ValidateProperties() []PropertyError {
+	errs := SchemaFor(c.Type).Validate(c.Properties)
+	for i := range errs {
+		errs[i].ComponentID = c.ID
+		errs[i].Params["component"] = c.Name
+		if c.Name == "" {
+			errs[i].Params["component"] = c.ID
+		}
+	}
+	return errs
+}
+
+// Localize 以指定語系產生每筆錯誤的訊息
+func (e *ValidationError) Localize(l i18n.Locale) {
+	for i, pe := range e.Errors {
+		msg := i18n.T(l, pe.MessageID, pe.Params)
+		if pe.ComponentID != "" {
+			msg = i18n.T(l, "schema.component", map[string]interface{}{"component": pe.Params["component"], "message": msg})
+		}
+		e.Errors[i].Message = msg
+	}
+}
+
+// Localized 回傳依語系翻譯屬性說明後的定義
+func (s Schema) Localized(l i18n.Locale) Schema {
+	props := make([]PropertySpec, len(s.Properties))
+	for i, p := range s.Properties {
+		p.Description = i18n.TOr(l, "schema.property."+p.Name, p.Description, nil)
+		props[i] = p
+	}
+	return Schema{Type: s.Type, Properties: props}
+}
@@ -0,0 +1,88 @@
+package component
+
+import (
+	"math"
+	"strings"
+	"system-design-game/internal/i18n"
+	"testing"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		t         Type
+		props     Metadata
+		property  string // 空白代表沒有錯誤
+		messageID string
+		params    map[string]interface{}
+	}{
+		{"valid", Database, Metadata{"max_qps": 5000, "replication_mode": "MASTER_SLAVE", "slave_count": 2}, "", "", nil},
+		{"null is unset", WebServer, Metadata{"max_replicas": nil}, "", "", nil},
+		{"below min", WebServer, Metadata{"max_replicas": 0}, "max_replicas", "schema.min", map[string]interface{}{"min": 1.0}},
+		{"above max", Database, Metadata{"slave_count": 11}, "slave_count", "schema.max", map[string]interface{}{"max": 10.0}},
+		{"negative cost", Cache, Metadata{"operational_cost": -0.1}, "operational_cost", "schema.min", map[string]interface{}{"min": 0.0}},
+		{"not in enum", MessageQueue, Metadata{"delivery_mode": "POLL"}, "delivery_mode", "schema.enum", map[string]interface{}{"enum": "PUSH, PULL"}},
+		{"string for a number", WebServer, Metadata{"max_replicas": "8"}, "max_replicas", "schema.type", map[string]interface{}{"kind": "integer"}},
+		{"fraction for an integer", WebServer, Metadata{"max_replicas": 2.5}, "max_replicas", "schema.type", map[string]interface{}{"kind": "integer"}},
+		{"infinite number", WebServer, Metadata{"base_latency": math.Inf(1)}, "base_latency", "schema.type", map[string]interface{}{"kind": "number"}},
+		{"number for a boolean", WebServer, Metadata{"auto_scaling": 1}, "auto_scaling", "schema.type", map[string]interface{}{"kind": "boolean"}},
+		{"typo suggestion", WebServer, Metadata{"max_qsp": 100}, "max_qsp", "schema.unknown_suggest", map[string]interface{}{"suggestion": "max_qps"}},
+		{"unknown without suggestion", WebServer, Metadata{"colour": "red"}, "colour", "schema.unknown", nil},
+		{"other type's property", Cache, Metadata{"slave_count": 1}, "slave_count", "schema.unknown", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := SchemaFor(tt.t).Validate(tt.props)
+			if tt.property == "" {
+				if len(errs) != 0 {
+					t.Fatalf("unexpected errors: %+v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("errors = %+v, want one on %s", errs, tt.property)
+			}
+			pe := errs[0]
+			if pe.Property != tt.property || pe.MessageID != tt.messageID {
+				t.Errorf("error = %s %s, want %s %s", pe.Property, pe.MessageID, tt.property, tt.messageID)
+			}
+			for k, want := range tt.params {
+				if pe.Params[k] != want {
+					t.Errorf("params[%s] = %v, want %v", k, pe.Params[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"max_qps", "max_qps", 0},
+		{"max_qsp", "max_qps", 1}, // 相鄰字元對調
+		{"maxqps", "max_qps", 1},
+		{"max_qs", "max_qps", 1},
+		{"colour", "max_qps", 7},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// 組件的錯誤帶有組件 ID，並依語系產生含組件名稱的訊息
+func TestComponentValidateProperties(t *testing.T) {
+	c := Component{ID: "web-1", Type: WebServer, Properties: Metadata{"max_qsp": 100}}
+	errs := c.ValidateProperties()
+	if len(errs) != 1 || errs[0].ComponentID != "web-1" || errs[0].Params["component"] != "web-1" {
+		t.Fatalf("errors = %+v", errs)
+	}
+	ve := &ValidationError{Errors: errs}
+	ve.Localize(i18n.En)
+	if msg := ve.Errors[0].Message; !strings.Contains(msg, "web-1") || !strings.Contains(msg, "max_qps") {
+		t.Errorf("message = %q, want the component and the suggested property", msg)
+	}
+}
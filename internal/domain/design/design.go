@@ -1,6 +1,10 @@
 package design
 
-import "system-design-game/internal/domain/component"
+import (
+	"errors"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/i18n"
+)
 
 // Connection 定義組件之間的連通性
 type Connection struct {
@@ -22,9 +26,54 @@ type Design struct {
 	UpdatedAt   int64                 `json:"updated_at"`
 }
 
+// Clone 深度複製設計圖，供伺服器端模擬在不影響原始資料的情況下推進狀態
+func (d *Design) Clone() *Design {
+	out := *d
+	out.Components = make([]component.Component, len(d.Components))
+	for i, c := range d.Components {
+		c.Properties = c.Properties.Clone()
+		out.Components[i] = c
+	}
+	out.Connections = append([]Connection(nil), d.Connections...)
+	out.Properties = d.Properties.Clone()
+	return &out
+}
+
+// PropertySchema 是設計圖全域屬性的定義
+var PropertySchema = component.Schema{Properties: []component.PropertySpec{
+	{Name: "retention_rate", Kind: component.KindNumber, Description: "使用者留存率，實際流量 = 關卡流量 × 留存率", Default: 1.0, Min: component.Bound(0), Max: component.Bound(10), State: true},
+	{Name: "recent_latencies", Kind: component.KindList, Unit: "ms", Description: "最近幾個 tick 的延遲，用來計算 p95 延遲", State: true},
+	{Name: "recent_error_rates", Kind: component.KindList, Description: "最近幾個 tick 的錯誤率", State: true},
+	{Name: "churn_rate", Kind: component.KindNumber, Description: "體驗最差時每秒流失的留存率，延遲或錯誤率超出關卡目標越多流失越快", Default: 0.005, Min: component.Bound(0), Max: component.Bound(1)},
+	{Name: "growth_rate", Kind: component.KindNumber, Description: "體驗良好時每秒自然成長的留存率", Default: 0.002, Min: component.Bound(0), Max: component.Bound(1)},
+	{Name: "max_retention", Kind: component.KindNumber, Description: "自然成長的上限 (關卡流量的倍數)", Default: 1.5, Min: component.Bound(0.1), Max: component.Bound(10)},
+	{Name: "seed", Kind: component.KindInteger, Description: "隨機事件的種子，相同種子會重現相同的突發、攻擊與故障"},
+	{Name: "daily_challenge", Kind: component.KindBoolean, Description: "使用當日的每日挑戰種子", Default: false},
+	{Name: "steady_traffic", Kind: component.KindBoolean, Description: "關閉流量的隨機波動", Default: false},
+	{Name: "economy", Kind: component.KindBoolean, Description: "經濟模式：組件需以關卡的起始資金購買，連續超出每秒預算會讓模擬失敗 (開始模擬時決定)", Default: false},
+	{Name: "random_drop_probability", Kind: component.KindNumber, Description: "每 15 秒發生流量驟降 (40%) 的機率", Default: 0.1, Min: component.Bound(0), Max: component.Bound(1)},
+}}
+
+// Validate 依屬性定義檢查設計圖的全域屬性與每個組件的屬性，錯誤訊息使用預設語系
+func (d *Design) Validate() error {
+	ve := &component.ValidationError{Errors: PropertySchema.Validate(d.Properties)}
+	for _, c := range d.Components {
+		ve.Errors = append(ve.Errors, c.ValidateProperties()...)
+	}
+	if len(ve.Errors) == 0 {
+		return nil
+	}
+	ve.Localize(i18n.DefaultLocale)
+	return ve
+}
+
+// ErrNotFound 表示設計圖不存在，Repository 回傳的錯誤可用 errors.Is 判斷
+var ErrNotFound = errors.New("design not found")
+
 // Repository 定義 Design 的持久化介面
 type Repository interface {
 	Save(design *Design) error
 	GetByID(id string) (*Design, error)
 	ListByPlayerID(playerID string) ([]*Design, error)
+	Delete(id string) error
 }
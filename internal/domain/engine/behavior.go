@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"sync"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+)
+
+// ComponentBehavior 定義某一類組件在模擬中的行為
+// 引擎只負責流量的傳遞與評分彙總，容量、延遲、資源、崩潰與流量轉換都交由各類型的行為決定。
+// 新增組件類型時只需實作一個行為並以 RegisterBehavior 註冊，不需修改引擎本身。
+type ComponentBehavior interface {
+	// Traits 回傳此類組件的固定特性 (預設成本、延遲、崩潰閾值等)
+	Traits() Traits
+
+	// MaxQPS 回傳單一節點的最大處理能力
+	MaxQPS(comp component.Component) int64
+	// MaxPotentialQPS 回傳擴展到極限時的最大處理能力 (供 PULL 模式的 MQ 決定投遞量)
+	MaxPotentialQPS(comp component.Component) int64
+	// Scale 依當前流量決定有效處理能力與副本數 (Auto Scaling)
+	Scale(ctx *TickContext, in Flow) (maxQPS int64, replicas int)
+
+	// Resources 回傳 CPU 與 RAM 使用率 (%)，RAM 超過 100 時組件會 OOM 崩潰
+	Resources(ctx *TickContext) (cpu, ram float64)
+
+	// Filter 回傳組件過濾後實際接收的流量 (如 WAF 攔截惡意流量、外部 API 依 SLA 丟包)
+	Filter(ctx *TickContext, in Flow) Flow
+	// Process 回傳組件實際處理的流量 (容量截斷或排隊)
+	Process(ctx *TickContext, in Flow) Flow
+	// Fulfill 回傳在此組件「成功取得資料」的讀取與寫入量
+	Fulfill(ctx *TickContext, in Flow) (read, write int64)
+	// Output 回傳往下游傳遞的總流量 (如快取命中的讀取不再往下傳)
+	Output(in Flow) Flow
+	// Deliver 回傳實際投遞給某一個下游組件的流量 (如 MQ PULL 模式依消費者能力投遞)
+	Deliver(ctx *TickContext, to component.Component, f Flow) Flow
+
+	// ReliabilityBonus 回傳此組件的備援設計對可靠性分數的加分
+	ReliabilityBonus(comp component.Component) float64
+}
+
+// Traits 是組件類型的固定特性
+type Traits struct {
+	OperationalCost         float64 // 未設定 OperationalCost 時的預設每秒維運成本
+	PricePerMillionRequests float64 // 未設定 price_per_million_requests 時的預設按量計費 (每百萬請求)
+	LatencyMS               float64 // 未設定 base_latency 時的預設延遲
+	ConsistencyPenalty      float64 // 使用預設延遲時對資料一致性分數的扣分 (如快取、最終一致性)
+	CrashThreshold          float64 // 負載超過有效容量幾倍時崩潰
+	Sensitive               bool    // 是否為核心資料節點 (抵達的惡意流量會降低安全分數)
+	Gateway                 bool    // 流量經過此組件後，抵達核心資料節點的威脅減半
+}
+
+// Flow 是流經組件的讀取、寫入與惡意流量 (QPS)
+type Flow struct {
+	Read      int64
+	Write     int64
+	Malicious int64
+}
+
+// Total 回傳正常請求 (讀取 + 寫入) 的總量
+func (f Flow) Total() int64 {
+	return f.Read + f.Write
+}
+
+// scale 依比例縮減讀取與寫入，惡意流量不變
+func (f Flow) scale(ratio float64) Flow {
+	return Flow{Read: int64(float64(f.Read) * ratio), Write: int64(float64(f.Write) * ratio), Malicious: f.Malicious}
+}
+
+// TickContext 提供組件行為在單一 tick 中所需的資訊，並收集行為對評估結果的影響
+type TickContext struct {
+	Elapsed            float64             // 模擬經過的秒數
+	DT                 float64             // 這個 tick 代表的秒數，累積量 (如 MQ 積壓) 需依此縮放
+	Component          component.Component // 正在處理的組件
+	Load               int64               // Pass 1 計算出的潛在總負載 (嘗試打進來的量，含惡意流量)
+	BaseMaxQPS         int64               // 單一節點的處理能力
+	MaxQPS             int64               // 當前的有效處理能力 (含 Auto Scaling)
+	DownstreamCapacity int64               // 尚未崩潰的下游組件處理能力總和
+
+	recorder        *eventRecorder
+	costs           *costAccount
+	latency         *float64
+	consistency     *float64
+	backlogs        map[string]int64
+	effectiveMaxQPS map[string]int64
+}
+
+// Emit 為目前的組件記錄一個事件
+func (c *TickContext) Emit(code evaluation.EventCode, severity evaluation.EventSeverity, params map[string]interface{}) {
+	c.recorder.emit(code, severity, c.Component.ID, params)
+}
+
+// AddCost 增加與流量無關的每秒維運成本
+func (c *TickContext) AddCost(v float64) {
+	c.costs.charge(c.Component.ID, evaluation.CostBreakdown{Capacity: v})
+}
+
+// AddNodeCost 以組件的計價方式 (reserved、spot 折扣) 增加 nodes 台節點的成本 (如 Auto Scaling 的額外副本)
+func (c *TickContext) AddNodeCost(unitPrice float64, nodes int) {
+	c.costs.chargeNodes(c.Component, unitPrice, nodes)
+}
+
+// AddLatency 增加請求的延遲 (如排隊延遲)
+func (c *TickContext) AddLatency(ms float64) {
+	*c.latency += ms
+}
+
+// PenalizeConsistency 降低資料一致性分數
+func (c *TickContext) PenalizeConsistency(v float64) {
+	*c.consistency -= v
+}
+
+// SetBacklog 記錄組件的積壓量，下一個 tick 由前端或 Simulation 帶回 backlog 屬性
+func (c *TickContext) SetBacklog(n int64) {
+	c.backlogs[c.Component.ID] = n
+}
+
+// SetEffectiveMaxQPS 覆寫顯示用的有效處理能力 (如 MQ 受限於消費者能力)
+func (c *TickContext) SetEffectiveMaxQPS(n int64) {
+	c.effectiveMaxQPS[c.Component.ID] = n
+}
+
+var (
+	behaviorsMu sync.RWMutex
+	behaviors   = map[component.Type]ComponentBehavior{}
+)
+
+// RegisterBehavior 註冊 (或覆寫) 某一類組件的行為
+func RegisterBehavior(t component.Type, b ComponentBehavior) {
+	behaviorsMu.Lock()
+	defer behaviorsMu.Unlock()
+	behaviors[t] = b
+}
+
+// BehaviorFor 回傳組件類型的行為，未註冊的類型使用預設行為
+func BehaviorFor(t component.Type) ComponentBehavior {
+	behaviorsMu.RLock()
+	defer behaviorsMu.RUnlock()
+	if b, ok := behaviors[t]; ok {
+		return b
+	}
+	return baseBehavior{}
+}
+
+// baseBehavior 是一般組件的預設行為，各類型的行為嵌入它並只覆寫有差異的部分
+type baseBehavior struct {
+	traits Traits
+}
+
+func (b baseBehavior) Traits() Traits {
+	t := b.traits
+	if t.CrashThreshold == 0 {
+		t.CrashThreshold = 1.5
+	}
+	return t
+}
+
+func (baseBehavior) MaxQPS(comp component.Component) int64 {
+	return comp.Properties.IntOr("max_qps", 0)
+}
+
+func (b baseBehavior) MaxPotentialQPS(comp component.Component) int64 {
+	return b.MaxQPS(comp)
+}
+
+func (baseBehavior) Scale(ctx *TickContext, in Flow) (int64, int) {
+	return ctx.BaseMaxQPS, 1
+}
+
+// Resources 預設 CPU 與負載成正比 (100% 負荷代表達到處理上限)，RAM 隨流量緩慢增加
+func (baseBehavior) Resources(ctx *TickContext) (float64, float64) {
+	return baseCPU(ctx), 15.0 + (float64(ctx.Load)/20000.0)*10.0
+}
+
+func (baseBehavior) Filter(ctx *TickContext, in Flow) Flow {
+	return in
+}
+
+// Process 預設超過有效容量的請求會被截斷
+func (baseBehavior) Process(ctx *TickContext, in Flow) Flow {
+	if ctx.MaxQPS > 0 && ctx.Load > ctx.MaxQPS {
+		return in.scale(float64(ctx.MaxQPS) / float64(ctx.Load))
+	}
+	return in
+}
+
+func (baseBehavior) Fulfill(ctx *TickContext, in Flow) (int64, int64) {
+	return 0, 0
+}
+
+func (baseBehavior) Output(in Flow) Flow {
+	return in
+}
+
+func (baseBehavior) Deliver(ctx *TickContext, to component.Component, f Flow) Flow {
+	return f
+}
+
+func (baseBehavior) ReliabilityBonus(comp component.Component) float64 {
+	return 0
+}
+
+// baseCPU 回傳依負載計算的 CPU 使用率 (基礎 Idle 10%)
+func baseCPU(ctx *TickContext) float64 {
+	cpu := 10.0
+	if ctx.MaxQPS > 0 {
+		cpu += (float64(ctx.Load) / float64(ctx.MaxQPS)) * 90.0
+	}
+	return cpu
+}
@@ -0,0 +1,356 @@
+package engine
+
+import (
+	"math"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+)
+
+// 內建組件類型的行為
+func init() {
+	RegisterBehavior(component.TrafficSource, baseBehavior{})
+	RegisterBehavior(component.LoadBalancer, baseBehavior{traits: Traits{OperationalCost: 0.1, LatencyMS: 5.0, CrashThreshold: 5.0}})
+	RegisterBehavior(component.APIGateway, baseBehavior{traits: Traits{OperationalCost: 0.15, LatencyMS: 2.0, Gateway: true}})
+	RegisterBehavior(component.WAF, wafBehavior{baseBehavior{traits: Traits{OperationalCost: 0.15, CrashThreshold: 5.0}}})
+	RegisterBehavior(component.CDN, cacheBehavior{baseBehavior{traits: Traits{LatencyMS: 2.0, ConsistencyPenalty: 10.0, CrashThreshold: 5.0}}})
+	RegisterBehavior(component.Cache, memoryCacheBehavior{cacheBehavior{baseBehavior{traits: Traits{OperationalCost: 0.3, LatencyMS: 2.0, ConsistencyPenalty: 10.0}}}})
+	RegisterBehavior(component.WebServer, autoScalingBehavior{baseBehavior{traits: Traits{OperationalCost: 0.2, LatencyMS: 20.0}}})
+	RegisterBehavior(component.AutoScalingGroup, asgBehavior{autoScalingBehavior{baseBehavior{traits: Traits{CrashThreshold: 3.0}}}}) // ASG 具有彈性緩衝，允許短暫過載以等待機器啟動
+	RegisterBehavior(component.Database, databaseBehavior{storageBehavior{baseBehavior{traits: Traits{OperationalCost: 0.5, LatencyMS: 50.0, Sensitive: true}}}})
+	RegisterBehavior(component.NoSQL, nosqlBehavior{storageBehavior{baseBehavior{traits: Traits{OperationalCost: 0.4, LatencyMS: 10.0, ConsistencyPenalty: 15.0, Sensitive: true}}}}) // NoSQL 通常為最終一致性
+	RegisterBehavior(component.ObjectStorage, storageBehavior{baseBehavior{traits: Traits{CrashThreshold: 50.0}}})                                                                    // ObjectStorage 非常難以崩潰
+	RegisterBehavior(component.SearchEngine, storageBehavior{baseBehavior{}})
+	RegisterBehavior(component.MessageQueue, queueBehavior{baseBehavior{traits: Traits{LatencyMS: 200.0, ConsistencyPenalty: 5.0, CrashThreshold: 50.0}}}) // MQ 的非同步延遲代價與最終一致性風險
+	RegisterBehavior(component.Worker, workerBehavior{baseBehavior{}})
+	RegisterBehavior(component.VideoTranscoding, transcodingBehavior{baseBehavior{}})
+	RegisterBehavior(component.ExternalAPI, externalAPIBehavior{baseBehavior{traits: Traits{LatencyMS: 200.0, PricePerMillionRequests: 100}}}) // 第三方服務通常很慢，按量收費 (每 1000 請求 $0.1)
+}
+
+// wafBehavior 過濾 90% 的惡意流量，並誤擋 2% 的正常請求
+type wafBehavior struct {
+	baseBehavior
+}
+
+func (wafBehavior) Filter(ctx *TickContext, in Flow) Flow {
+	return Flow{
+		Read:      int64(float64(in.Read) * 0.98),
+		Write:     int64(float64(in.Write) * 0.98),
+		Malicious: int64(float64(in.Malicious) * 0.1),
+	}
+}
+
+func (wafBehavior) Output(in Flow) Flow {
+	in.Malicious = int64(float64(in.Malicious) * 0.1)
+	return in
+}
+
+// cacheBehavior 攔截讀取 (假設 80% 命中)，寫入 100% 穿透
+type cacheBehavior struct {
+	baseBehavior
+}
+
+func (cacheBehavior) Fulfill(ctx *TickContext, in Flow) (int64, int64) {
+	return int64(float64(in.Read) * 0.8), 0
+}
+
+func (cacheBehavior) Output(in Flow) Flow {
+	in.Read = int64(float64(in.Read) * 0.2)
+	return in
+}
+
+// memoryCacheBehavior 是記憶體快取：RAM 隨流量增長而填滿 (模擬快取物件增加)
+type memoryCacheBehavior struct {
+	cacheBehavior
+}
+
+func (memoryCacheBehavior) Resources(ctx *TickContext) (float64, float64) {
+	ram := 15.0
+	if ctx.MaxQPS > 0 {
+		ram += (float64(ctx.Load) / float64(ctx.MaxQPS)) * 70.0
+	}
+	return baseCPU(ctx), ram
+}
+
+// autoScalingBehavior 支援 Auto Scaling (WebServer 與 AutoScalingGroup 共用)
+type autoScalingBehavior struct {
+	baseBehavior
+}
+
+func (b autoScalingBehavior) MaxPotentialQPS(comp component.Component) int64 {
+	base := b.MaxQPS(comp)
+	if comp.Properties.Enabled("auto_scaling") {
+		return base * comp.Properties.IntOr("max_replicas", 5)
+	}
+	return base
+}
+
+func (b autoScalingBehavior) Scale(ctx *TickContext, in Flow) (int64, int) {
+	comp := ctx.Component
+	baseMaxQPS := ctx.BaseMaxQPS
+	if !comp.Properties.Enabled("auto_scaling") {
+		return baseMaxQPS, 1
+	}
+
+	maxReplicas := int(comp.Properties.IntOr("max_replicas", 5))
+	threshold := comp.Properties.FloatOr("scale_up_threshold", 70.0) // 預設 70% 資源使用率就擴展
+	warmup := float64(comp.Properties.IntOr("warmup_seconds", 10))   // 預設 10 秒暖機
+
+	// 擴展指標：預設使用 CPU，也可以設定為 RAM
+	scaleMetric := comp.Properties.TextOr("scale_metric", "cpu")
+
+	// 考慮暖機時間 (由前端傳來的啟動時間清單)
+	activeCount := 1
+	bootingCount := 0
+	for _, tObj := range replicaStartTimes(comp) {
+		startTime, _ := tObj.(float64)
+		if ctx.Elapsed-startTime >= warmup {
+			activeCount++
+		} else {
+			bootingCount++
+		}
+	}
+
+	// 計算當前單機的資源使用率
+	effectiveResourceLoad := float64(in.Read) + float64(in.Write)*4.0 // 寫入加權
+	currentCPUUsage := 0.0
+	currentRAMUsage := 0.0
+
+	if baseMaxQPS > 0 {
+		currentCPUUsage = math.Min(100.0, 10.0+(effectiveResourceLoad/float64(baseMaxQPS)/float64(activeCount))*90.0)
+		currentRAMUsage = math.Min(100.0, 20.0+(float64(ctx.Load)/float64(baseMaxQPS)/float64(activeCount))*60.0)
+	}
+
+	// 根據選擇的指標決定是否擴展
+	currentMetricValue := currentCPUUsage
+	if scaleMetric == "ram" {
+		currentMetricValue = currentRAMUsage
+	}
+
+	// 計算目標副本數
+	targetReplicas := activeCount
+	if currentMetricValue > threshold && activeCount < maxReplicas {
+		// 需要擴展：例如當前 CPU 90%、閾值 70%，則需要 90/70 = 1.28 倍的機器
+		targetReplicas = int(math.Ceil(float64(activeCount) * currentMetricValue / threshold))
+		if targetReplicas > maxReplicas {
+			targetReplicas = maxReplicas
+		}
+	} else if currentMetricValue < threshold*0.5 && activeCount > 1 {
+		// 縮容：使用率低於閾值的 50% 時縮減
+		targetReplicas = activeCount - 1
+	}
+
+	currentReplicas := activeCount + bootingCount
+	scaleParams := map[string]interface{}{"from": currentReplicas, "to": targetReplicas, "metric": scaleMetric, "usage": currentMetricValue}
+	if targetReplicas > currentReplicas {
+		ctx.Emit(evaluation.EventScaleUp, evaluation.SeverityInfo, scaleParams)
+	} else if targetReplicas < activeCount {
+		ctx.Emit(evaluation.EventScaleDown, evaluation.SeverityInfo, scaleParams)
+	}
+
+	// 額外成本：每台機器都以與基礎機器相同的單價計費
+	ctx.AddNodeCost(nodePrice(comp, b.Traits()), currentReplicas-1)
+	return baseMaxQPS * int64(activeCount), currentReplicas
+}
+
+// replicaStartTimes 回傳額外副本的啟動時間，超過 max_replicas 的部分不計 (第 1 台基礎機器不在清單中)
+func replicaStartTimes(comp component.Component) []interface{} {
+	startTimes, _ := comp.Properties.List("replica_start_times")
+	maxExtra := int(comp.Properties.IntOr("max_replicas", 5)) - 1
+	if maxExtra < 0 {
+		maxExtra = 0
+	}
+	return startTimes[:min(len(startTimes), maxExtra)]
+}
+
+// asgBehavior 是 Auto Scaling Group：多台機器共同分擔流量
+type asgBehavior struct {
+	autoScalingBehavior
+}
+
+// Resources 計算「平均單機」的使用率，因為流量會被 LB 均分 (暖機中的副本也佔資源)
+func (b asgBehavior) Resources(ctx *TickContext) (float64, float64) {
+	if ctx.BaseMaxQPS <= 0 {
+		return b.baseBehavior.Resources(ctx)
+	}
+	nodes := 1 + len(replicaStartTimes(ctx.Component))
+	avgLoadPerNode := float64(ctx.Load) / float64(nodes)
+	return 10.0 + (avgLoadPerNode/float64(ctx.BaseMaxQPS))*90.0, 15.0 + (avgLoadPerNode/20000.0)*10.0
+}
+
+// storageBehavior 是資料的最終落點：抵達的讀寫即視為成功取得資料
+type storageBehavior struct {
+	baseBehavior
+}
+
+// Fulfill 處理讀寫；Slave 只能處理讀取，寫到 Slave 的請求會失敗並降低一致性
+func (storageBehavior) Fulfill(ctx *TickContext, in Flow) (int64, int64) {
+	if ctx.Component.Properties.TextOr("replication_mode", "SINGLE") != "SLAVE" {
+		return in.Read, in.Write
+	}
+	if in.Write > 0 {
+		ctx.PenalizeConsistency(1.0)
+		ctx.Emit(evaluation.EventSlaveWrite, evaluation.SeverityWarning, map[string]interface{}{"write_qps": in.Write})
+	}
+	return in.Read, 0
+}
+
+// databaseBehavior 支援主從架構 (MASTER_SLAVE)
+type databaseBehavior struct {
+	storageBehavior
+}
+
+// MaxQPS 主從架構下每個 Slave 增加一倍的讀取能力
+func (b databaseBehavior) MaxQPS(comp component.Component) int64 {
+	base := b.baseBehavior.MaxQPS(comp)
+	if comp.Properties.TextOr("replication_mode", "SINGLE") == "MASTER_SLAVE" {
+		return base * (1 + comp.Properties.IntOr("slave_count", 0))
+	}
+	return base
+}
+
+func (b databaseBehavior) MaxPotentialQPS(comp component.Component) int64 {
+	return b.MaxQPS(comp)
+}
+
+func (databaseBehavior) Resources(ctx *TickContext) (float64, float64) {
+	return databaseResources(ctx)
+}
+
+// ReliabilityBonus 有主從備援的資料庫加 10 分
+func (databaseBehavior) ReliabilityBonus(comp component.Component) float64 {
+	if comp.Properties.TextOr("replication_mode", "SINGLE") == "MASTER_SLAVE" && comp.Properties.IntOr("slave_count", 0) > 0 {
+		return 10.0
+	}
+	return 0
+}
+
+type nosqlBehavior struct {
+	storageBehavior
+}
+
+func (nosqlBehavior) Resources(ctx *TickContext) (float64, float64) {
+	return databaseResources(ctx)
+}
+
+// databaseResources 資料庫的 RAM 有較高的基礎占用 (Buffer Pool)，並隨流量增加
+func databaseResources(ctx *TickContext) (float64, float64) {
+	return baseCPU(ctx), 15.0 + (30.0 + (float64(ctx.Load)/10000.0)*20.0)
+}
+
+// queueBehavior 是訊息隊列：處理速度受限於自身吞吐量與下游消費者能力，處理不完的訊息會積壓
+type queueBehavior struct {
+	baseBehavior
+}
+
+// Resources MQ 的 RAM 隨積壓量增加 (假設 5 萬筆積壓會爆 RAM)
+func (queueBehavior) Resources(ctx *TickContext) (float64, float64) {
+	prevBacklog := ctx.Component.Properties.IntOr("backlog", 0)
+	return baseCPU(ctx), 15.0 + (float64(prevBacklog)/50000.0)*80.0
+}
+
+func (queueBehavior) Process(ctx *TickContext, in Flow) Flow {
+	// 實際處理速度受限於「MQ 吞吐量」與「消費者能力」的最小值
+	// 如果下游完全沒有可用的消費者，則處理能力為 0
+	mqIoLimit := ctx.MaxQPS
+	effectiveProcessingRate := mqIoLimit
+	if ctx.DownstreamCapacity > 0 && ctx.DownstreamCapacity < mqIoLimit {
+		effectiveProcessingRate = ctx.DownstreamCapacity
+	} else if ctx.DownstreamCapacity == 0 {
+		effectiveProcessingRate = 0
+	}
+
+	// 從 Properties 獲取上一次的積壓量
+	prevBacklog := ctx.Component.Properties.IntOr("backlog", 0)
+
+	// 嘗試處理：這個 tick 內進入的訊息量與可處理量都依 tick 長度縮放
+	var actualProcessed, backlog int64
+	attemptLoad := int64(float64(in.Read+in.Write)*ctx.DT) + prevBacklog
+	capacity := int64(float64(effectiveProcessingRate) * ctx.DT)
+	if effectiveProcessingRate > 0 && attemptLoad > capacity {
+		actualProcessed = capacity
+		backlog = attemptLoad - capacity
+	} else {
+		actualProcessed = attemptLoad
+	}
+	ctx.SetBacklog(backlog)
+
+	// 積壓量跨越警戒值 (預設 10000 筆) 時發出事件
+	backlogAlert := ctx.Component.Properties.IntOr("backlog_alert", 10000)
+	if backlog >= backlogAlert && prevBacklog < backlogAlert {
+		ctx.Emit(evaluation.EventBacklogThreshold, evaluation.SeverityWarning, map[string]interface{}{
+			"backlog":   backlog,
+			"threshold": backlogAlert,
+		})
+	}
+
+	// MQ 延遲代價，並以實際處理速度作為顯示用的有效 MaxQPS
+	if effectiveProcessingRate > 0 {
+		ctx.AddLatency((float64(backlog) / float64(effectiveProcessingRate)) * 1000.0)
+	}
+	ctx.SetEffectiveMaxQPS(effectiveProcessingRate)
+
+	// 依處理比例縮減讀寫
+	ratio := 1.0
+	if attemptLoad > 0 {
+		ratio = float64(actualProcessed) / float64(attemptLoad)
+	}
+	return in.scale(ratio)
+}
+
+// Deliver PULL 模式下，消費者只拉取自己處理得了的量
+func (queueBehavior) Deliver(ctx *TickContext, to component.Component, f Flow) Flow {
+	if ctx.Component.Properties.TextOr("delivery_mode", "PUSH") != "PULL" {
+		return f
+	}
+	dsMaxCap := BehaviorFor(to.Type).MaxPotentialQPS(to)
+	if total := f.Total(); dsMaxCap > 0 && total > dsMaxCap {
+		return f.scale(float64(dsMaxCap) / float64(total))
+	}
+	return f
+}
+
+// workerBehavior 是背景工作者：記憶體消耗較高，與負載相關
+type workerBehavior struct {
+	baseBehavior
+}
+
+func (workerBehavior) Resources(ctx *TickContext) (float64, float64) {
+	ram := 15.0
+	if ctx.MaxQPS > 0 {
+		ram += 20.0 + (float64(ctx.Load)/float64(ctx.MaxQPS))*40.0
+	}
+	return baseCPU(ctx), ram
+}
+
+// transcodingBehavior 是影片轉碼：極高 CPU (CPU 密集型任務) 與中等 RAM (需要載入影片)
+type transcodingBehavior struct {
+	baseBehavior
+}
+
+func (transcodingBehavior) Resources(ctx *TickContext) (float64, float64) {
+	cpu := baseCPU(ctx) + 40.0
+	ram := 15.0
+	if ctx.MaxQPS > 0 {
+		cpu += (float64(ctx.Load) / float64(ctx.MaxQPS)) * 50.0
+		ram += 30.0 + (float64(ctx.Load)/float64(ctx.MaxQPS))*40.0
+	}
+	return cpu, ram
+}
+
+// externalAPIBehavior 是第三方服務：依 SLA 丟包 (按量計費由 CostModel 依 PricePerMillionRequests 計算)，不佔用我們自己的資源
+type externalAPIBehavior struct {
+	baseBehavior
+}
+
+func (externalAPIBehavior) Resources(ctx *TickContext) (float64, float64) {
+	return 2.0, 5.0
+}
+
+func (externalAPIBehavior) Filter(ctx *TickContext, in Flow) Flow {
+	sla := ctx.Component.Properties.FloatOr("sla", 99.0) / 100.0
+	return Flow{
+		Read:      int64(float64(in.Read) * sla),
+		Write:     int64(float64(in.Write) * sla),
+		Malicious: in.Malicious,
+	}
+}
@@ -0,0 +1,230 @@
+package engine
+
+import (
+	"slices"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+	"testing"
+)
+
+// newTickContext 建立單一組件在 elapsed 秒、負載為 load 時的 TickContext
+func newTickContext(comp component.Component, load int64, elapsed float64) *TickContext {
+	latency, consistency := 0.0, 100.0
+	return &TickContext{
+		Elapsed:         elapsed,
+		DT:              1,
+		Component:       comp,
+		Load:            load,
+		BaseMaxQPS:      BehaviorFor(comp.Type).MaxQPS(comp),
+		recorder:        newEventRecorder(int64(elapsed), map[string]component.Component{comp.ID: comp}),
+		costs:           newCostAccount(StandardCostModel{}, []component.Component{comp}),
+		latency:         &latency,
+		consistency:     &consistency,
+		backlogs:        make(map[string]int64),
+		effectiveMaxQPS: make(map[string]int64),
+	}
+}
+
+// eventCodes 回傳 TickContext 記錄的事件代碼
+func eventCodes(ctx *TickContext) []evaluation.EventCode {
+	var codes []evaluation.EventCode
+	for _, ev := range ctx.recorder.events {
+		codes = append(codes, ev.Code)
+	}
+	return codes
+}
+
+// 崩潰閾值與改為行為註冊表之前引擎中的類型分支相同
+func TestCrashThresholds(t *testing.T) {
+	tests := []struct {
+		typ       component.Type
+		threshold float64
+	}{
+		{component.WebServer, 1.5},
+		{component.Database, 1.5},
+		{component.Cache, 1.5},
+		{component.Worker, 1.5},
+		{component.AutoScalingGroup, 3.0},
+		{component.LoadBalancer, 5.0},
+		{component.CDN, 5.0},
+		{component.WAF, 5.0},
+		{component.MessageQueue, 50.0},
+		{component.ObjectStorage, 50.0},
+		{component.Type("UNKNOWN"), 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			if got := BehaviorFor(tt.typ).Traits().CrashThreshold; got != tt.threshold {
+				t.Errorf("crash threshold = %v, want %v", got, tt.threshold)
+			}
+		})
+	}
+}
+
+// 負載超過 max_qps × 崩潰閾值時組件崩潰
+func TestCrashOnOverload(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     component.Type
+		qps     int64
+		crashed bool
+	}{
+		{"web below threshold", component.WebServer, 1400, false},
+		{"web above threshold", component.WebServer, 1600, true},
+		{"load balancer below threshold", component.LoadBalancer, 4800, false},
+		{"load balancer above threshold", component.LoadBalancer, 5200, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := chainDesign(80,
+				newComponent("x", tt.typ, component.Metadata{"max_qps": 1000}),
+				newComponent("db", component.Database, component.Metadata{"max_qps": 100000}),
+			)
+			res, err := newTestEngine().EvaluateDesign(d, steadyScenario(tt.qps, 60), 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := slices.Contains(res.CrashedComponentIDs, "x"); got != tt.crashed {
+				t.Errorf("crashed = %v, want %v (load %d)", got, tt.crashed, res.ComponentLoads["x"])
+			}
+		})
+	}
+}
+
+// WAF 攔截 90% 的惡意流量，並誤擋 2% 的正常請求
+func TestWAFFilter(t *testing.T) {
+	b := BehaviorFor(component.WAF)
+	ctx := newTickContext(newComponent("waf", component.WAF, nil), 2500, 10)
+	tests := []struct {
+		name string
+		in   Flow
+		want Flow
+	}{
+		{"mixed", Flow{Read: 1000, Write: 500, Malicious: 1000}, Flow{Read: 980, Write: 490, Malicious: 100}},
+		{"no attack", Flow{Read: 100, Write: 50}, Flow{Read: 98, Write: 49}},
+		{"truncated", Flow{Read: 1, Write: 1, Malicious: 5}, Flow{Read: 0, Write: 0, Malicious: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Filter(ctx, tt.in); got != tt.want {
+				t.Errorf("Filter(%+v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			// 往下游傳遞時惡意流量再被攔截 90%，正常請求不變
+			out := b.Output(tt.in)
+			if want := (Flow{Read: tt.in.Read, Write: tt.in.Write, Malicious: int64(float64(tt.in.Malicious) * 0.1)}); out != want {
+				t.Errorf("Output(%+v) = %+v, want %+v", tt.in, out, want)
+			}
+		})
+	}
+}
+
+// PUSH 模式照單全收；PULL 模式下消費者只拉取擴展到極限後處理得了的量
+func TestQueueDelivery(t *testing.T) {
+	worker := newComponent("worker", component.Worker, component.Metadata{"max_qps": 300})
+	scaling := newComponent("web", component.WebServer, component.Metadata{"max_qps": 100, "auto_scaling": true, "max_replicas": 5})
+	tests := []struct {
+		name string
+		mode string
+		to   component.Component
+		in   Flow
+		want Flow
+	}{
+		{"push over capacity", "PUSH", worker, Flow{Read: 400, Write: 100, Malicious: 10}, Flow{Read: 400, Write: 100, Malicious: 10}},
+		{"default is push", "", worker, Flow{Read: 400, Write: 100}, Flow{Read: 400, Write: 100}},
+		{"pull over capacity", "PULL", worker, Flow{Read: 400, Write: 100, Malicious: 10}, Flow{Read: 240, Write: 60, Malicious: 10}},
+		{"pull within capacity", "PULL", worker, Flow{Read: 200, Write: 100}, Flow{Read: 200, Write: 100}},
+		{"pull counts max replicas", "PULL", scaling, Flow{Read: 800, Write: 200}, Flow{Read: 400, Write: 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props := component.Metadata{"max_qps": 10000}
+			if tt.mode != "" {
+				props["delivery_mode"] = tt.mode
+			}
+			mq := newComponent("mq", component.MessageQueue, props)
+			ctx := newTickContext(mq, tt.in.Total(), 10)
+			if got := BehaviorFor(component.MessageQueue).Deliver(ctx, tt.to, tt.in); got != tt.want {
+				t.Errorf("Deliver(%+v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// Slave 只能處理讀取：寫入不算成功取得資料，並降低一致性分數與發出警告
+func TestStorageSlaveWrites(t *testing.T) {
+	tests := []struct {
+		name        string
+		typ         component.Type
+		mode        string
+		in          Flow
+		read, write int64
+		consistency float64
+		events      []evaluation.EventCode
+	}{
+		{"single database", component.Database, "", Flow{Read: 100, Write: 50}, 100, 50, 100, nil},
+		{"master", component.Database, "MASTER_SLAVE", Flow{Read: 100, Write: 50}, 100, 50, 100, nil},
+		{"slave with writes", component.Database, "SLAVE", Flow{Read: 100, Write: 50}, 100, 0, 99, []evaluation.EventCode{evaluation.EventSlaveWrite}},
+		{"slave read only", component.Database, "SLAVE", Flow{Read: 100}, 100, 0, 100, nil},
+		{"nosql slave", component.NoSQL, "SLAVE", Flow{Read: 10, Write: 5}, 10, 0, 99, []evaluation.EventCode{evaluation.EventSlaveWrite}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props := component.Metadata{}
+			if tt.mode != "" {
+				props["replication_mode"] = tt.mode
+			}
+			ctx := newTickContext(newComponent("db", tt.typ, props), tt.in.Total(), 10)
+			read, write := BehaviorFor(tt.typ).Fulfill(ctx, tt.in)
+			if read != tt.read || write != tt.write {
+				t.Errorf("Fulfill(%+v) = (%d, %d), want (%d, %d)", tt.in, read, write, tt.read, tt.write)
+			}
+			if *ctx.consistency != tt.consistency {
+				t.Errorf("consistency = %v, want %v", *ctx.consistency, tt.consistency)
+			}
+			if got := eventCodes(ctx); !slices.Equal(got, tt.events) {
+				t.Errorf("events = %v, want %v", got, tt.events)
+			}
+		})
+	}
+}
+
+// 副本數不超過 max_replicas，額外副本與基礎機器以相同的單價 (含計價方式的折扣) 計費
+func TestAutoScalingReplicas(t *testing.T) {
+	startTimes := func(times ...float64) []interface{} {
+		out := make([]interface{}, len(times))
+		for i, v := range times {
+			out[i] = v
+		}
+		return out
+	}
+	tests := []struct {
+		name     string
+		typ      component.Type
+		props    component.Metadata
+		cost     float64 // Component.OperationalCost，0 代表使用類型的預設
+		maxQPS   int64
+		replicas int
+		nodeCost float64
+	}{
+		{"scaling disabled", component.WebServer, component.Metadata{"replica_start_times": startTimes(0, 0)}, 0, 1000, 1, 0},
+		{"warm replicas", component.WebServer, component.Metadata{"auto_scaling": true, "replica_start_times": startTimes(0, 0)}, 0, 3000, 3, 0.4},
+		{"booting replica", component.WebServer, component.Metadata{"auto_scaling": true, "replica_start_times": startTimes(0, 95)}, 0, 2000, 3, 0.4},
+		{"capped at max replicas", component.WebServer, component.Metadata{"auto_scaling": true, "max_replicas": 3, "replica_start_times": startTimes(0, 0, 0, 0, 0, 0)}, 0, 3000, 3, 0.4},
+		{"single replica allowed", component.WebServer, component.Metadata{"auto_scaling": true, "max_replicas": 1, "replica_start_times": startTimes(0, 0)}, 0, 1000, 1, 0},
+		{"component price", component.AutoScalingGroup, component.Metadata{"auto_scaling": true, "replica_start_times": startTimes(0, 0)}, 0.5, 3000, 3, 1.0},
+		{"reserved discount", component.WebServer, component.Metadata{"auto_scaling": true, "pricing": PricingReserved, "replica_start_times": startTimes(0, 0)}, 0, 3000, 3, 0.24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.props["max_qps"] = 1000
+			comp := newComponent("asg", tt.typ, tt.props)
+			comp.OperationalCost = tt.cost
+			ctx := newTickContext(comp, 100, 100)
+			maxQPS, replicas := BehaviorFor(tt.typ).Scale(ctx, Flow{Read: 100})
+			if maxQPS != tt.maxQPS || replicas != tt.replicas {
+				t.Errorf("Scale = (%d, %d), want (%d, %d)", maxQPS, replicas, tt.maxQPS, tt.replicas)
+			}
+			assertClose(t, "replica cost", ctx.costs.total, tt.nodeCost)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"sort"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/scenario"
+)
+
+// CapacityOptions 定義容量極限搜尋的參數
+type CapacityOptions struct {
+	MinFulfillment float64 // 資料獲取率低於此值即視為失效，預設 0.95
+	MaxQPS         int64   // 搜尋上限，預設 10,000,000
+	ProbeSeconds   int64   // 每個 QPS 的探測秒數，預設 30 (讓 ASG 有時間完成暖機)
+}
+
+// probeOutcome 是單次 QPS 探測的結果
+type probeOutcome struct {
+	ok          bool
+	reason      string
+	saturatedID string
+	last        *evaluation.Result
+}
+
+// CapacityLimit 讀取設計圖與關卡後搜尋其容量極限
+func (e *SimpleEngine) CapacityLimit(designID string, opts CapacityOptions) (*evaluation.CapacityReport, error) {
+	d, s, err := e.load(designID)
+	if err != nil {
+		return nil, err
+	}
+	return e.CapacityLimitDesign(d, s, opts)
+}
+
+// CapacityLimitDesign 以二分搜尋找出設計能持續承受的最大輸入 QPS
+// 探測時關閉突發、攻擊、隨機故障與自然波動，只看穩定流量下的極限；
+// 設計圖中的模擬狀態 (崩潰、積壓、副本與留存率) 會先被清除，每次探測都從頭開始
+func (e *SimpleEngine) CapacityLimitDesign(d *design.Design, s *scenario.Scenario, opts CapacityOptions) (*evaluation.CapacityReport, error) {
+	d = freshDesign(d)
+	if opts.MinFulfillment <= 0 {
+		opts.MinFulfillment = 0.95
+	}
+	if opts.MaxQPS <= 0 {
+		opts.MaxQPS = 10_000_000
+	}
+	if opts.ProbeSeconds <= 0 {
+		opts.ProbeSeconds = 30
+	}
+
+	report := &evaluation.CapacityReport{
+		DesignID:       d.ID,
+		ScenarioID:     s.ID,
+		MinFulfillment: opts.MinFulfillment,
+	}
+
+	// 1. 指數搜尋：找出第一個失效的 QPS 作為上界
+	var lo int64
+	var loOutcome *probeOutcome
+	hi := int64(100)
+	var hiOutcome *probeOutcome
+	for {
+		if hi > opts.MaxQPS {
+			hi = opts.MaxQPS
+		}
+		out, err := e.probe(d, s, hi, opts)
+		if err != nil {
+			return nil, err
+		}
+		if !out.ok {
+			hiOutcome = out
+			break
+		}
+		lo, loOutcome = hi, out
+		if hi == opts.MaxQPS {
+			break
+		}
+		hi *= 2
+	}
+
+	// 2. 二分搜尋：精度為 1% 或 1 QPS
+	if hiOutcome != nil {
+		for hi-lo > 1 && hi-lo > lo/100 {
+			mid := lo + (hi-lo)/2
+			out, err := e.probe(d, s, mid, opts)
+			if err != nil {
+				return nil, err
+			}
+			if out.ok {
+				lo, loOutcome = mid, out
+			} else {
+				hi, hiOutcome = mid, out
+			}
+		}
+		report.BreakingQPS = hi
+		report.BreakingReason = hiOutcome.reason
+		report.FirstSaturatedID = hiOutcome.saturatedID
+	}
+	report.MaxSustainableQPS = lo
+
+	// 3. 各組件在最大可持續流量下的剩餘容量
+	if loOutcome != nil {
+		report.Headroom = headroom(d, loOutcome.last)
+		if report.FirstSaturatedID == "" && len(report.Headroom) > 0 {
+			report.FirstSaturatedID = report.Headroom[0].ComponentID
+		}
+	}
+	return report, nil
+}
+
+// probe 以固定輸入 QPS 執行一小段穩定模擬，判斷設計是否能承受
+func (e *SimpleEngine) probe(d *design.Design, s *scenario.Scenario, qps int64, opts CapacityOptions) (*probeOutcome, error) {
+	pd := d.Clone()
+	if pd.Properties == nil {
+		pd.Properties = component.Metadata{}
+	}
+	pd.Properties["steady_traffic"] = true
+	for i := range pd.Components {
+		comp := &pd.Components[i]
+		if comp.Type != component.TrafficSource {
+			// spot 節點被回收是隨機事件，不代表設計的容量：探測時以 on_demand 計價，不會被中斷
+			if pricingOf(*comp) == PricingSpot {
+				comp.Properties["pricing"] = PricingOnDemand
+			}
+			continue
+		}
+		if comp.Properties == nil {
+			comp.Properties = component.Metadata{}
+		}
+		comp.Properties["start_qps"] = 0.0
+		comp.Properties["burst_traffic"] = false
+		comp.Properties["enable_attacks"] = false
+		comp.Properties["enable_failures"] = false
+	}
+
+	ps := *s
+	ps.Phases = []scenario.TrafficPhase{
+		{Name: "容量探測", StartQPS: qps, EndQPS: qps, DurationSeconds: int(opts.ProbeSeconds)},
+	}
+
+	sim := e.NewSimulation(pd, &ps, 0)
+	out := &probeOutcome{ok: true}
+	for t := int64(0); t < opts.ProbeSeconds; t++ {
+		res, err := sim.Step()
+		if err != nil {
+			return nil, err
+		}
+		// 容量測試只關心系統本身，固定留存率避免流量被「使用者流失」稀釋
+		sim.design.Properties["retention_rate"] = 1.0
+		out.last = res
+
+		if len(res.CrashedComponentIDs) > 0 {
+			out.ok = false
+			out.reason = "crash"
+			out.saturatedID = mostUtilized(res, res.CrashedComponentIDs)
+			return out, nil
+		}
+		// 前半段為暖機期 (ASG 擴展、MQ 建立積壓)，只在後半段檢查資料獲取率
+		if t >= opts.ProbeSeconds/2 && res.TotalQPS > 0 {
+			if float64(res.FulfilledQPS)/float64(res.TotalQPS) < opts.MinFulfillment {
+				out.ok = false
+				out.reason = "fulfillment"
+				out.saturatedID = mostUtilized(res, nil)
+				return out, nil
+			}
+		}
+	}
+	return out, nil
+}
+
+// mostUtilized 從候選組件中找出使用率最高者 (候選為空時看全部組件)
+func mostUtilized(res *evaluation.Result, candidates []string) string {
+	if len(candidates) == 0 {
+		for id := range res.ComponentLoads {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Strings(candidates)
+	best, bestUtil := "", -1.0
+	for _, id := range candidates {
+		util := utilization(res.ComponentLoads[id], res.ComponentEffectiveMaxQPS[id])
+		if util > bestUtil {
+			best, bestUtil = id, util
+		}
+	}
+	return best
+}
+
+// headroom 計算每個組件的剩餘容量，依使用率由高到低排序
+func headroom(d *design.Design, res *evaluation.Result) []evaluation.ComponentHeadroom {
+	var out []evaluation.ComponentHeadroom
+	for _, comp := range d.Components {
+		if comp.Type == component.TrafficSource {
+			continue
+		}
+		maxQPS := res.ComponentEffectiveMaxQPS[comp.ID]
+		load := res.ComponentLoads[comp.ID]
+		h := evaluation.ComponentHeadroom{
+			ComponentID:     comp.ID,
+			Name:            comp.Name,
+			Load:            load,
+			EffectiveMaxQPS: maxQPS,
+			Utilization:     utilization(load, maxQPS),
+		}
+		if maxQPS > load {
+			h.HeadroomQPS = maxQPS - load
+		}
+		out = append(out, h)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Utilization > out[j].Utilization
+	})
+	return out
+}
+
+// utilization 計算使用率，未設定容量上限的組件視為 0
+func utilization(load, maxQPS int64) float64 {
+	if maxQPS <= 0 {
+		return 0
+	}
+	return float64(load) / float64(maxQPS)
+}
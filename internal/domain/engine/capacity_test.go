@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"testing"
+)
+
+// bottleneckDesign 建立只有資料庫會飽和的設計：資料庫 max_qps 1000，其餘組件容量充足
+func bottleneckDesign() *design.Design {
+	return chainDesign(80,
+		newComponent("web", component.WebServer, component.Metadata{"max_qps": 100000}),
+		newComponent("db", component.Database, component.Metadata{"max_qps": 1000}),
+	)
+}
+
+// 單一固定容量的資料庫是唯一瓶頸：資料獲取率為 1000/qps，極限為 1000/MinFulfillment，
+// 但負載超過 1.5 倍容量時資料庫崩潰 (二分搜尋精度 1%)
+func TestCapacityLimitSingleBottleneck(t *testing.T) {
+	tests := []struct {
+		name           string
+		minFulfillment float64
+		limit          float64
+		reason         string
+	}{
+		{"default fulfillment", 0, 1000 / 0.95, "fulfillment"},
+		{"full fulfillment", 1, 1000, "fulfillment"},
+		{"crash threshold", 0.5, 1500, "crash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := newTestEngine().CapacityLimitDesign(bottleneckDesign(), steadyScenario(100, 30), CapacityOptions{MinFulfillment: tt.minFulfillment})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := float64(report.MaxSustainableQPS); got > tt.limit || got < tt.limit*0.99 {
+				t.Errorf("max sustainable qps = %d, want within 1%% below %v", report.MaxSustainableQPS, tt.limit)
+			}
+			if got := float64(report.BreakingQPS); got <= tt.limit {
+				t.Errorf("breaking qps = %d, want above %v", report.BreakingQPS, tt.limit)
+			}
+			if report.BreakingReason != tt.reason || report.FirstSaturatedID != "db" {
+				t.Errorf("breaking reason = %q at %q, want %q at db", report.BreakingReason, report.FirstSaturatedID, tt.reason)
+			}
+		})
+	}
+}
+
+// 儲存自進行中模擬的設計圖帶有崩潰與積壓等狀態，容量搜尋應與全新的設計圖得到相同的結果
+func TestCapacityLimitIgnoresStoredState(t *testing.T) {
+	e := newTestEngine()
+	s := steadyScenario(100, 30)
+	want, err := e.CapacityLimitDesign(bottleneckDesign(), s, CapacityOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stale := bottleneckDesign()
+	stale.Components[1].Properties["crashed"] = true
+	stale.Components[1].Properties["restartedAt"] = 3.0
+	stale.Components[2].Properties["backlog"] = 50000.0
+	stale.Properties["retention_rate"] = 0.2
+	got, err := e.CapacityLimitDesign(stale, s, CapacityOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MaxSustainableQPS != want.MaxSustainableQPS || got.BreakingQPS != want.BreakingQPS {
+		t.Errorf("stale design: max %d breaking %d; want max %d breaking %d",
+			got.MaxSustainableQPS, got.BreakingQPS, want.MaxSustainableQPS, want.BreakingQPS)
+	}
+	if !stale.Components[1].Properties.Enabled("crashed") {
+		t.Error("CapacityLimitDesign must not modify the caller's design")
+	}
+}
+
+// spot 節點的回收是隨機事件，容量搜尋不受影響：結果與全部使用 on_demand 的設計相同
+// (探測 120 秒，判定用的後半段涵蓋一整個 60 秒的回收窗口，回收機率 1 時必定發生)
+func TestCapacityLimitIgnoresSpotInterruptions(t *testing.T) {
+	e := newTestEngine()
+	s := steadyScenario(100, 30)
+	opts := CapacityOptions{ProbeSeconds: 120}
+	want, err := e.CapacityLimitDesign(bottleneckDesign(), s, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spot := bottleneckDesign()
+	for _, c := range spot.Components[1:] {
+		c.Properties["pricing"] = PricingSpot
+		c.Properties["spot_interruption_probability"] = 1.0
+	}
+	got, err := e.CapacityLimitDesign(spot, s, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MaxSustainableQPS != want.MaxSustainableQPS || got.BreakingQPS != want.BreakingQPS || got.BreakingReason != want.BreakingReason {
+		t.Errorf("spot design: max %d breaking %d (%s); want max %d breaking %d (%s)",
+			got.MaxSustainableQPS, got.BreakingQPS, got.BreakingReason, want.MaxSustainableQPS, want.BreakingQPS, want.BreakingReason)
+	}
+	if p, _ := spot.Components[2].Properties.Text("pricing"); p != PricingSpot {
+		t.Error("CapacityLimitDesign must not modify the caller's design")
+	}
+}
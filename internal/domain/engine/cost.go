@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+)
+
+// 組件的計價方式 (組件屬性 pricing)
+const (
+	PricingOnDemand = "on_demand" // 依使用付費
+	PricingReserved = "reserved"  // 預留容量：較便宜，但即使沒有流量或崩潰也要付費
+	PricingSpot     = "spot"      // spot 節點：最便宜，但可能被雲端回收而中斷
+)
+
+// 計價參數
+const (
+	ReservedDiscount                   = 0.4  // reserved 節點便宜 40%
+	SpotDiscount                       = 0.7  // spot 節點便宜 70%
+	DefaultSpotInterruptionProbability = 0.05 // spot 節點每 60 秒被回收的機率
+	SpotInterruptionSeconds            = 10   // spot 節點被回收後多久以新的節點恢復
+	DefaultReadSizeKB                  = 20.0 // 讀取請求 (含回應) 的預設大小
+	DefaultWriteSizeKB                 = 5.0  // 寫入請求的預設大小
+	DefaultAttackSizeKB                = 1.0  // 惡意請求的預設大小
+	kbPerGB                            = 1e6
+)
+
+// RequestSizes 是各流量類別單一請求的大小 (KB)，由流量來源的 read_size_kb、write_size_kb、attack_size_kb 設定
+type RequestSizes struct {
+	Read      float64
+	Write     float64
+	Malicious float64
+}
+
+// CostModel 決定組件的每秒成本，引擎在流量經過組件時依序計價：
+// 節點 (含 Auto Scaling 副本)、實際接收的請求 (按量計費)、處理後送出的流量 (egress)。
+// 自訂的計價方式可實作此介面並以 SimpleEngine.SetCostModel 替換
+type CostModel interface {
+	// Capacity 回傳 nodes 台每秒單價為 unitPrice 的節點成本
+	Capacity(comp component.Component, unitPrice float64, nodes int) float64
+	// Committed 判斷組件是否不論有沒有運作都要付出節點成本 (如預留容量)
+	Committed(comp component.Component) bool
+	// InterruptionProbability 回傳組件每 60 秒被中斷的機率，0 表示不會被中斷
+	InterruptionProbability(comp component.Component) float64
+	// Requests 回傳依實際接收 (過濾後) 的請求按量計費的成本
+	Requests(comp component.Component, traits Traits, in Flow) evaluation.CostBreakdown
+	// Egress 回傳依處理的流量大小計費的成本
+	Egress(comp component.Component, out Flow, sizes RequestSizes) evaluation.CostBreakdown
+}
+
+// StandardCostModel 是預設的計價方式：
+//   - 節點依 pricing 折扣：reserved 便宜 40% 但一定要付費，spot 便宜 70% 但可能被回收
+//   - price_per_million_requests (未設定時使用該類型的預設，如外部 API) 依正常請求數計費 (惡意請求不計費)
+//   - egress_per_gb 依處理的流量大小計費
+type StandardCostModel struct{}
+
+func (StandardCostModel) Capacity(comp component.Component, unitPrice float64, nodes int) float64 {
+	cost := unitPrice * float64(nodes)
+	switch pricingOf(comp) {
+	case PricingReserved:
+		cost *= 1 - ReservedDiscount
+	case PricingSpot:
+		cost *= 1 - SpotDiscount
+	}
+	return cost
+}
+
+func (StandardCostModel) Committed(comp component.Component) bool {
+	return pricingOf(comp) == PricingReserved
+}
+
+func (StandardCostModel) InterruptionProbability(comp component.Component) float64 {
+	if pricingOf(comp) != PricingSpot {
+		return 0
+	}
+	return comp.Properties.FloatOr("spot_interruption_probability", DefaultSpotInterruptionProbability)
+}
+
+func (StandardCostModel) Requests(comp component.Component, traits Traits, in Flow) evaluation.CostBreakdown {
+	price := comp.Properties.FloatOr("price_per_million_requests", traits.PricePerMillionRequests) / 1e6
+	if price <= 0 {
+		return evaluation.CostBreakdown{}
+	}
+	return evaluation.CostBreakdown{
+		Read:  float64(in.Read) * price,
+		Write: float64(in.Write) * price,
+	}
+}
+
+func (StandardCostModel) Egress(comp component.Component, out Flow, sizes RequestSizes) evaluation.CostBreakdown {
+	price := comp.Properties.FloatOr("egress_per_gb", 0) / kbPerGB
+	if price <= 0 {
+		return evaluation.CostBreakdown{}
+	}
+	return evaluation.CostBreakdown{
+		Read:      float64(out.Read) * sizes.Read * price,
+		Write:     float64(out.Write) * sizes.Write * price,
+		Malicious: float64(out.Malicious) * sizes.Malicious * price,
+	}
+}
+
+// pricingOf 回傳組件的計價方式
+func pricingOf(comp component.Component) string {
+	return comp.Properties.TextOr("pricing", PricingOnDemand)
+}
+
+// nodePrice 回傳組件單一節點的每秒單價，未設定 OperationalCost 時使用該類型的預設維運成本
+func nodePrice(comp component.Component, traits Traits) float64 {
+	if comp.OperationalCost == 0 {
+		return traits.OperationalCost
+	}
+	return comp.OperationalCost
+}
+
+// costAccount 累計一個 tick 的成本，並歸屬到組件與流量類別
+type costAccount struct {
+	model       CostModel
+	total       float64
+	byClass     evaluation.CostBreakdown
+	byComponent map[string]float64
+	committed   map[string]bool // 已付出節點成本的組件
+}
+
+func newCostAccount(model CostModel, comps []component.Component) *costAccount {
+	a := &costAccount{model: model, byComponent: make(map[string]float64, len(comps)), committed: make(map[string]bool)}
+	for _, c := range comps {
+		a.byComponent[c.ID] = 0
+	}
+	return a
+}
+
+// charge 將成本歸屬到組件
+func (a *costAccount) charge(id string, c evaluation.CostBreakdown) {
+	a.total += c.Total()
+	a.byClass.Add(c)
+	a.byComponent[id] += c.Total()
+}
+
+// chargeNodes 以組件的計價方式收取 nodes 台節點的成本
+func (a *costAccount) chargeNodes(comp component.Component, unitPrice float64, nodes int) {
+	a.committed[comp.ID] = true
+	a.charge(comp.ID, evaluation.CostBreakdown{Capacity: a.model.Capacity(comp, unitPrice, nodes)})
+}
+
+// chargeCommitted 收取這個 tick 沒有運作 (沒有流量、崩潰或中斷) 但仍須付費的節點成本
+func (a *costAccount) chargeCommitted(comps []component.Component) {
+	for _, c := range comps {
+		if c.Type != component.TrafficSource && !a.committed[c.ID] && a.model.Committed(c) {
+			a.chargeNodes(c, nodePrice(c, BehaviorFor(c.Type).Traits()), 1)
+		}
+	}
+}
+
+// SetCostModel 替換引擎的計價方式 (預設為 StandardCostModel)
+func (e *SimpleEngine) SetCostModel(m CostModel) {
+	e.costModel = m
+}
+
+func (e *SimpleEngine) costs() CostModel {
+	if e.costModel == nil {
+		return StandardCostModel{}
+	}
+	return e.costModel
+}
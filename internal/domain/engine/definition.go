@@ -0,0 +1,64 @@
+package engine
+
+import "system-design-game/internal/domain/component"
+
+// definitionBehavior 是依自訂組件定義 (component.Definition) 產生的行為
+type definitionBehavior struct {
+	baseBehavior
+	def component.Definition
+}
+
+func newDefinitionBehavior(def component.Definition) definitionBehavior {
+	return definitionBehavior{
+		baseBehavior: baseBehavior{traits: Traits{
+			OperationalCost:    def.OperationalCost,
+			LatencyMS:          def.LatencyMS,
+			ConsistencyPenalty: def.ConsistencyPenalty,
+			CrashThreshold:     def.CrashThreshold,
+			Sensitive:          def.Sensitive,
+			Gateway:            def.Gateway,
+		}},
+		def: def,
+	}
+}
+
+// MaxQPS 組件本身設定的 max_qps 優先，否則使用定義的預設值
+func (b definitionBehavior) MaxQPS(comp component.Component) int64 {
+	if v, ok := comp.Properties.Int("max_qps"); ok {
+		return v
+	}
+	return b.def.MaxQPS
+}
+
+func (b definitionBehavior) MaxPotentialQPS(comp component.Component) int64 {
+	return b.MaxQPS(comp)
+}
+
+// Fulfill 資料落點的讀寫全數成功；類似快取的組件依吸收比例成功取得讀取
+func (b definitionBehavior) Fulfill(ctx *TickContext, in Flow) (int64, int64) {
+	if b.def.DataSink {
+		return in.Read, in.Write
+	}
+	return int64(float64(in.Read) * b.def.ReadAbsorption), 0
+}
+
+// Output 被吸收的讀取不再往下游傳遞
+func (b definitionBehavior) Output(in Flow) Flow {
+	if b.def.ReadAbsorption > 0 {
+		in.Read = int64(float64(in.Read) * (1 - b.def.ReadAbsorption))
+	}
+	return in
+}
+
+// RegisterDefinitions 驗證並註冊自訂組件定義，重複的類型以後者為準
+func RegisterDefinitions(defs ...component.Definition) error {
+	for _, def := range defs {
+		if err := def.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, def := range defs {
+		RegisterBehavior(def.Type, newDefinitionBehavior(def))
+	}
+	return nil
+}
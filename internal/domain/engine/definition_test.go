@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"system-design-game/internal/domain/component"
+	"testing"
+)
+
+// 任一定義不合法時整批都不註冊，內建類型的行為不會被覆寫
+func TestRegisterDefinitionsRejectsBuiltinTypes(t *testing.T) {
+	before := BehaviorFor(component.Database)
+	err := RegisterDefinitions(
+		component.Definition{Type: "TestRejectedBatch", MaxQPS: 100},
+		component.Definition{Type: component.Database, MaxQPS: 1},
+	)
+	if err == nil {
+		t.Fatal("registering a built-in type should fail")
+	}
+	if _, ok := BehaviorFor("TestRejectedBatch").(definitionBehavior); ok {
+		t.Error("valid definitions in a rejected batch must not be registered")
+	}
+	if BehaviorFor(component.Database) != before {
+		t.Error("the built-in Database behavior was replaced")
+	}
+}
+
+// 自訂組件依定義的容量、吸收比例、延遲與崩潰閾值參與模擬
+func TestDefinitionBehaviorInEvaluateTick(t *testing.T) {
+	if err := RegisterDefinitions(component.Definition{
+		Type:            "TestGraphQLGateway",
+		MaxQPS:          2000,
+		LatencyMS:       8,
+		OperationalCost: 0.2,
+		CrashThreshold:  2,
+		ReadAbsorption:  0.5,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	e := newTestEngine()
+
+	d := chainDesign(100,
+		newComponent("gql", "TestGraphQLGateway", nil),
+		newComponent("db", component.Database, component.Metadata{"max_qps": 5000}),
+	)
+	res, err := e.EvaluateTick(d, steadyScenario(1000, 60), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.ComponentEffectiveMaxQPS["gql"]; got != 2000 {
+		t.Errorf("gql max qps = %d, want the definition default 2000", got)
+	}
+	if got := res.ComponentLoads["db"]; got != 500 {
+		t.Errorf("db load = %d, want 500 (half of the reads absorbed by gql)", got)
+	}
+	if res.FulfilledQPS != 1000 {
+		t.Errorf("fulfilled = %d, want 1000", res.FulfilledQPS)
+	}
+	assertClose(t, "gql cost", res.ComponentCosts["gql"], 0.2)
+
+	// 負載 3000 未超過 2 倍容量，不會崩潰；5000 超過後崩潰
+	for _, tt := range []struct {
+		qps     int64
+		crashed bool
+	}{{3000, false}, {5000, true}} {
+		res, err := e.EvaluateTick(d, steadyScenario(tt.qps, 60), 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		crashed := false
+		for _, id := range res.CrashedComponentIDs {
+			crashed = crashed || id == "gql"
+		}
+		if crashed != tt.crashed {
+			t.Errorf("qps %d: gql crashed = %v, want %v", tt.qps, crashed, tt.crashed)
+		}
+	}
+
+	// 組件自己的 max_qps 優先於定義的預設值
+	d.Components[1].Properties["max_qps"] = 800
+	res, err = e.EvaluateTick(d, steadyScenario(1000, 60), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.ComponentEffectiveMaxQPS["gql"]; got != 800 {
+		t.Errorf("gql max qps = %d, want the component's 800", got)
+	}
+}
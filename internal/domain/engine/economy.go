@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"system-design-game/internal/domain/catalog"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/scenario"
+	"system-design-game/internal/domain/world"
+	"system-design-game/internal/i18n"
+)
+
+// 經濟模式 (設計圖全域屬性 economy) 的參數
+const (
+	DefaultBudget          = 50.0   // 關卡沒有 budget 限制時的每秒預算
+	DefaultStartingBalance = 1000.0 // 關卡沒有 starting_balance 限制時的起始資金
+	BudgetGraceSeconds     = 10.0   // 連續超出每秒預算多少秒後模擬失敗
+)
+
+var (
+	// ErrInsufficientFunds 表示經濟模式下的資金不足以購買組件
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrInvalidComponent 表示新增的組件不合法 (缺少 ID 或類型、ID 重複、成本為負)
+	ErrInvalidComponent = errors.New("invalid component")
+)
+
+// scenarioBudget 回傳關卡的每秒運作成本預算
+func scenarioBudget(s *scenario.Scenario) float64 {
+	return constraintOr(s, scenario.ConstraintBudget, DefaultBudget)
+}
+
+// constraintOr 回傳關卡中指定類型的限制值，未設定時回傳 def
+func constraintOr(s *scenario.Scenario, typ string, def float64) float64 {
+	v := def
+	for _, c := range s.Constraints {
+		if c.Type == typ {
+			v = float64(c.Value)
+		}
+	}
+	return v
+}
+
+// ledger 記錄經濟模式下一場模擬的資金與預算：組件依 setup_cost 從起始資金購買，
+// 每秒運作成本連續超出預算 BudgetGraceSeconds 秒後，本場模擬失敗 (之後的 tick 一律不通過)
+// 無盡模式的場次以玩家的遊戲狀態作為資金 (UseGameState)，購買、收益與成本都記在同一份金幣上
+type ledger struct {
+	balance    *float64
+	budget     float64
+	overBudget float64 // 連續超出預算的秒數
+	failed     bool
+}
+
+// newLedger 在設計圖啟用經濟模式時建立帳本，並購買設計圖中所有的組件 (資金可能因此為負，由 checkFunds 回報)
+func newLedger(d *design.Design, s *scenario.Scenario) *ledger {
+	if !d.Properties.Enabled("economy") {
+		return nil
+	}
+	balance := constraintOr(s, scenario.ConstraintStartingBalance, DefaultStartingBalance)
+	for _, c := range d.Components {
+		balance -= math.Max(0, c.SetupCost)
+	}
+	return &ledger{balance: &balance, budget: scenarioBudget(s)}
+}
+
+// purchase 以剩餘資金購買組件，資金不足時拒絕
+func (l *ledger) purchase(c component.Component) error {
+	if c.SetupCost > *l.balance {
+		return reason(ErrInsufficientFunds, "reason.insufficient_funds", map[string]interface{}{"component": c.ID, "cost": c.SetupCost, "balance": *l.balance})
+	}
+	*l.balance -= c.SetupCost
+	return nil
+}
+
+// settle 依這個 tick 的每秒成本更新預算狀態，寫入結果並在超出預算與失敗時記錄事件
+func (l *ledger) settle(res *evaluation.Result) {
+	if res.CostPerSec > l.budget {
+		if l.overBudget == 0 && !l.failed {
+			res.Events = append(res.Events, budgetEvent(res, evaluation.EventOverBudget, evaluation.SeverityWarning, map[string]interface{}{
+				"cost": res.CostPerSec, "budget": l.budget, "grace": BudgetGraceSeconds,
+			}))
+		}
+		l.overBudget += res.DT
+	} else {
+		l.overBudget = 0
+	}
+	if !l.failed && l.overBudget >= BudgetGraceSeconds {
+		l.failed = true
+		res.Events = append(res.Events, budgetEvent(res, evaluation.EventBudgetFailed, evaluation.SeverityCritical, map[string]interface{}{
+			"budget": l.budget, "seconds": l.overBudget,
+		}))
+	}
+	if l.failed {
+		res.Passed = false
+	}
+	res.Budget = &evaluation.BudgetStatus{
+		Balance:           *l.balance,
+		BudgetPerSec:      l.budget,
+		OverBudgetSeconds: l.overBudget,
+		GraceSeconds:      BudgetGraceSeconds,
+		Failed:            l.failed,
+	}
+}
+
+func budgetEvent(res *evaluation.Result, code evaluation.EventCode, severity evaluation.EventSeverity, params map[string]interface{}) evaluation.Event {
+	return evaluation.Event{Code: code, Severity: severity, Tick: res.CreatedAt, Params: params}
+}
+
+// checkFunds 確認開始時的資金足以購買設計圖中所有的組件
+func (sim *Simulation) checkFunds() error {
+	if sim.ledger != nil && *sim.ledger.balance < 0 {
+		return reason(ErrInsufficientFunds, "reason.starting_balance_short", map[string]interface{}{"shortfall": -*sim.ledger.balance})
+	}
+	return nil
+}
+
+// UseGameState 讓模擬以無盡模式玩家的遊戲狀態結算：每個 tick 依成功取得資料的 QPS 帶來收益、扣除運作成本
+// (world.GameState.UpdateMetrics / DeductCost)；經濟模式下帳本改用玩家的金幣，新增組件直接從中扣款
+// (開始時設計圖中的組件視為已擁有)。開始的遊戲狀態會寫入執行紀錄，重播時以相同的金幣重現購買與收支
+func (sim *Simulation) UseGameState(game *world.GameState) {
+	sim.game = game
+	if sim.ledger != nil {
+		sim.ledger.balance = &game.Balance
+	}
+	if sim.log != nil {
+		start := *game
+		sim.log.GameState = &start
+	}
+}
+
+// BudgetFailed 判斷經濟模式下這場模擬是否已因持續超出預算而失敗
+func (sim *Simulation) BudgetFailed() bool {
+	return sim.ledger != nil && sim.ledger.failed
+}
+
+// AddComponent 在模擬進行中新增 (購買) 一個組件，引用 SKU 時以目錄的規格與成本覆寫 (同儲存設計圖)，
+// 模擬狀態 (如 crashed) 會被清除；經濟模式下以剩餘資金支付 setup_cost，不足時回傳 ErrInsufficientFunds
+func (sim *Simulation) AddComponent(c component.Component) error {
+	c.Properties = c.Properties.Clone()
+	if c.SKU != "" && sim.engine.catalogRepo != nil {
+		probe := &design.Design{Components: []component.Component{c}}
+		if err := catalog.Resolve(probe, sim.engine.catalogRepo); err != nil {
+			return err
+		}
+		c = probe.Components[0]
+	}
+	if c.ID == "" || c.Type == "" {
+		return fmt.Errorf("%w: 缺少 id 或 type", ErrInvalidComponent)
+	}
+	if sim.component(c.ID) != nil {
+		return fmt.Errorf("%w: %s 已存在", ErrInvalidComponent, c.ID)
+	}
+	if c.SetupCost < 0 || c.OperationalCost < 0 {
+		return fmt.Errorf("%w: %s 的成本不可為負", ErrInvalidComponent, c.ID)
+	}
+	clearState(&c)
+	if errs := c.ValidateProperties(); len(errs) > 0 {
+		ve := &component.ValidationError{Errors: errs}
+		ve.Localize(i18n.DefaultLocale)
+		return ve
+	}
+	if sim.ledger != nil {
+		if err := sim.ledger.purchase(c); err != nil {
+			return err
+		}
+	}
+	sim.design.Components = append(sim.design.Components, c)
+	recorded := c
+	recorded.Properties = c.Properties.Clone()
+	sim.recordAction(RunAction{Type: ActionAddComponent, Component: &recorded})
+	return nil
+}
+
+// clearState 移除組件中的模擬狀態屬性 (崩潰、積壓、副本啟動時間等)
+func clearState(c *component.Component) {
+	for _, spec := range component.SchemaFor(c.Type).Properties {
+		if spec.State {
+			delete(c.Properties, spec.Name)
+		}
+	}
+}
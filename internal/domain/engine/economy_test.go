@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"errors"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/scenario"
+	"system-design-game/internal/domain/world"
+	"testing"
+)
+
+// economyDesign 建立啟用經濟模式的設計圖 (src → web → db) 與關卡 (起始資金 1000、每秒預算 budget)，web 的購買成本為 200
+func economyDesign(budget int64) (*design.Design, *scenario.Scenario) {
+	web := newComponent("web", component.WebServer, component.Metadata{"max_qps": float64(1000)})
+	web.SetupCost = 200
+	d := chainDesign(80, web, newComponent("db", component.Database, component.Metadata{"max_qps": float64(1000)}))
+	d.Properties["economy"] = true
+	s := steadyScenario(100, 60)
+	s.Constraints = []scenario.Constraint{
+		{Type: scenario.ConstraintStartingBalance, Value: 1000},
+		{Type: scenario.ConstraintBudget, Value: budget},
+	}
+	return d, s
+}
+
+func cacheComponent(id string, setupCost float64) component.Component {
+	c := newComponent(id, component.Cache, nil)
+	c.SetupCost = setupCost
+	return c
+}
+
+func reasonID(err error) string {
+	var re *ReasonError
+	if errors.As(err, &re) {
+		return re.MessageID
+	}
+	return ""
+}
+
+func TestEconomyPurchase(t *testing.T) {
+	d, s := economyDesign(50)
+	sim := newTestEngine().NewSimulation(d, s, 42)
+	if err := sim.checkFunds(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sim.AddComponent(cacheComponent("cache", 300)); err != nil {
+		t.Fatal(err)
+	}
+	res, err := sim.Step()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "balance after purchase", res.Budget.Balance, 1000-200-300)
+
+	// 資金不足時拒絕購買，資金與設計圖都不變
+	err = sim.AddComponent(cacheComponent("cache-2", 600))
+	if !errors.Is(err, ErrInsufficientFunds) || reasonID(err) != "reason.insufficient_funds" {
+		t.Errorf("AddComponent = %v, want reason.insufficient_funds", err)
+	}
+	if sim.component("cache-2") != nil {
+		t.Error("a rejected component should not be added")
+	}
+	assertClose(t, "balance after rejection", *sim.ledger.balance, 500)
+
+	// 開始時的資金不足以購買設計圖中的組件
+	d.Components[1].SetupCost = 1200
+	err = newTestEngine().NewSimulation(d, s, 42).checkFunds()
+	if !errors.Is(err, ErrInsufficientFunds) || reasonID(err) != "reason.starting_balance_short" {
+		t.Errorf("checkFunds = %v, want reason.starting_balance_short", err)
+	}
+}
+
+// 連續超出預算 BudgetGraceSeconds 秒 (依 tick 長度累計) 後失敗，中途回到預算內會重新計算
+func TestLedgerBudgetGrace(t *testing.T) {
+	balance := 100.0
+	l := &ledger{balance: &balance, budget: 10}
+	settle := func(cost, dt float64) *evaluation.Result {
+		res := &evaluation.Result{CostPerSec: cost, DT: dt, Passed: true}
+		l.settle(res)
+		return res
+	}
+	codes := func(res *evaluation.Result) []evaluation.EventCode {
+		var out []evaluation.EventCode
+		for _, ev := range res.Events {
+			out = append(out, ev.Code)
+		}
+		return out
+	}
+
+	if res := settle(20, 4); len(res.Events) != 1 || res.Events[0].Code != evaluation.EventOverBudget {
+		t.Errorf("first over-budget tick events = %v, want OVER_BUDGET", codes(res))
+	}
+	if res := settle(20, 4); len(res.Events) != 0 || !res.Passed {
+		t.Errorf("still within grace: events %v, passed %v", codes(res), res.Passed)
+	}
+	if res := settle(5, 4); res.Budget.OverBudgetSeconds != 0 || res.Budget.Failed {
+		t.Errorf("back under budget: %+v, want the counter reset", res.Budget)
+	}
+
+	for i := 0; i < 4; i++ {
+		settle(20, 2.5)
+	}
+	if !l.failed {
+		t.Fatalf("over budget for %v seconds should fail", l.overBudget)
+	}
+	res := settle(5, 1)
+	if res.Passed || !res.Budget.Failed {
+		t.Errorf("after failing every tick fails: passed %v, budget %+v", res.Passed, res.Budget)
+	}
+	assertClose(t, "balance", res.Budget.Balance, 100)
+}
+
+// 無盡模式只有一份資金：收益、運作成本與新增組件都記在玩家的遊戲狀態上，重播時以紀錄中的遊戲狀態重現
+func TestGameStateIsTheOnlyBalance(t *testing.T) {
+	d, s := economyDesign(50)
+	e := newTestEngine()
+	sim := e.NewSimulation(d, s, 42)
+	sim.Record()
+	game := &world.GameState{PlayerID: "alice", Balance: 2000, SystemHealth: 100}
+	sim.UseGameState(game)
+
+	if err := sim.AddComponent(cacheComponent("cache", 300)); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "balance after purchase", game.Balance, 1700)
+	before := game.Balance
+	var last *evaluation.Result
+	for i := 0; i < 3; i++ {
+		res, err := sim.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.RevenuePerSec <= 0 {
+			t.Errorf("tick %d has no revenue", i)
+		}
+		before += (res.RevenuePerSec - res.CostPerSec) * res.DT
+		assertClose(t, "budget balance", res.Budget.Balance, game.Balance)
+		last = res
+	}
+	assertClose(t, "balance after 3 ticks", game.Balance, before)
+	assertClose(t, "uptime", game.Uptime, 3)
+	if err := sim.AddComponent(cacheComponent("too-expensive", game.Balance+1)); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("AddComponent over the player's balance = %v, want ErrInsufficientFunds", err)
+	}
+
+	log := sim.log.Snapshot()
+	if log.GameState == nil || log.GameState.Balance != 2000 {
+		t.Fatalf("run log game state = %+v, want the starting balance 2000", log.GameState)
+	}
+	report, err := e.Replay(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Matched {
+		t.Errorf("replay diverged at tick %d", report.DivergedAt)
+	}
+	assertClose(t, "replayed balance", report.Results[len(report.Results)-1].Budget.Balance, last.Budget.Balance)
+}
@@ -1,56 +1,156 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"sort"
+	"system-design-game/internal/domain/catalog"
 	"system-design-game/internal/domain/component"
 	"system-design-game/internal/domain/design"
 	"system-design-game/internal/domain/evaluation"
 	"system-design-game/internal/domain/scenario"
+	"system-design-game/internal/i18n"
+	"time"
 )
 
 // Engine 定義評估引擎的介面
 type Engine interface {
 	Evaluate(designID string, elapsedSeconds int64) (*evaluation.Result, error)
+	EvaluateStrict(designID string, elapsedSeconds int64) (*evaluation.Result, error)
+	MonteCarlo(ctx context.Context, designID string, opts MonteCarloOptions) (*evaluation.MonteCarloReport, error)
+	CapacityLimit(designID string, opts CapacityOptions) (*evaluation.CapacityReport, error)
+	Simulate(designID string) (*Simulation, error)
+	Replay(log *RunLog) (*evaluation.ReplayReport, error)
+	Verify(req VerificationRequest) (*evaluation.VerificationReport, error)
+}
+
+// edge 是連線地圖中的一條連線
+type edge struct {
+	ToID        string
+	TrafficType string // "all", "read", "write"
 }
 
 // SimpleEngine 是一個基礎的評估引擎實作
 type SimpleEngine struct {
 	designRepo   design.Repository
 	scenarioRepo scenario.Repository
+	catalogRepo  catalog.Repository // 嚴格模式使用的伺服器端組件目錄
+	defaultSeed  int64              // 設計未指定種子時使用，於引擎建立時隨機產生
+	costModel    CostModel          // 計價方式，未設定時使用 StandardCostModel
+	clock        func() time.Time   // 決定每日挑戰的日期 (由外部注入，領域層不直接讀取系統時間)
 }
 
-func NewSimpleEngine(dr design.Repository, sr scenario.Repository) *SimpleEngine {
+// NewSimpleEngine 建立評估引擎，clock 用於決定每日挑戰的日期 (通常傳入 time.Now)
+func NewSimpleEngine(dr design.Repository, sr scenario.Repository, cr catalog.Repository, clock func() time.Time) *SimpleEngine {
 	return &SimpleEngine{
 		designRepo:   dr,
 		scenarioRepo: sr,
+		catalogRepo:  cr,
+		defaultSeed:  randomSeed(),
+		clock:        clock,
+	}
+}
+
+// resolveSeed 決定本次模擬使用的種子：設計指定的 seed > 每日挑戰 > 引擎預設種子
+// 每日挑戰的種子應在一場模擬開始時決定一次並寫入設計圖的 seed (儲存設計圖、建立 Simulation 時)，跨過 UTC 午夜也不會改變
+func (e *SimpleEngine) resolveSeed(d *design.Design) int64 {
+	if v, ok := d.Properties.Int("seed"); ok {
+		return v
+	}
+	if d.Properties.Enabled("daily_challenge") && e.clock != nil {
+		return DailySeed(e.clock())
 	}
+	return e.defaultSeed
 }
 
 // Evaluate 實作評估邏輯
 func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluation.Result, error) {
-	d, err := e.designRepo.GetByID(designID)
+	d, s, err := e.load(designID)
 	if err != nil {
 		return nil, err
 	}
+	return e.EvaluateDesign(d, s, elapsedSeconds)
+}
+
+// EvaluateStrict 以嚴格模式評估：組件的成本與容量由目錄決定，客戶端的覆寫會被忽略並列在結果中
+func (e *SimpleEngine) EvaluateStrict(designID string, elapsedSeconds int64) (*evaluation.Result, error) {
+	d, s, err := e.load(designID)
+	if err != nil {
+		return nil, err
+	}
+	enforced, discarded, err := e.enforce(d)
+	if err != nil {
+		return nil, err
+	}
+	res, err := e.EvaluateDesign(enforced, s, elapsedSeconds)
+	if err != nil {
+		return nil, err
+	}
+	res.Mode = evaluation.ModeStrict
+	res.DiscardedProperties = discarded
+	res.Localize(i18n.DefaultLocale)
+	return res, nil
+}
+
+// enforce 以目錄規格重建設計圖 (嚴格模式)
+func (e *SimpleEngine) enforce(d *design.Design) (*design.Design, []evaluation.DiscardedProperty, error) {
+	if e.catalogRepo == nil {
+		return nil, nil, fmt.Errorf("嚴格模式需要組件目錄")
+	}
+	return catalog.Enforce(d, e.catalogRepo)
+}
+
+// load 讀取設計圖與其所屬的關卡
+func (e *SimpleEngine) load(designID string) (*design.Design, *scenario.Scenario, error) {
+	d, err := e.designRepo.GetByID(designID)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	s, err := e.scenarioRepo.GetByID(d.ScenarioID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, s, nil
+}
+
+// EvaluateDesign 針對給定的設計圖與關卡評估某一秒的系統狀態 (不經過 Repository)
+// 設計圖不會被修改：使用者體驗與下一個 tick 的留存率放在結果的 Experience，由客戶端帶回下一次評估
+func (e *SimpleEngine) EvaluateDesign(d *design.Design, s *scenario.Scenario, elapsedSeconds int64) (*evaluation.Result, error) {
+	res, err := e.EvaluateTick(d, s, float64(elapsedSeconds), 1)
 	if err != nil {
 		return nil, err
 	}
+	props := d.Properties.Clone()
+	if props == nil {
+		props = component.Metadata{}
+	}
+	res.Experience = experience(props, s, res)
+	return res, nil
+}
 
-	// 1. 建立連線地圖 (Adjacency List)
-	type edgeInfo struct {
-		ToID        string
-		TrafficType string
+// EvaluateTick 評估從 elapsed - dt 到 elapsed 這一段時間 (dt 秒) 的系統狀態
+// QPS、成本等「每秒」的量不受 dt 影響；MQ 積壓這類累積量依 dt 縮放，
+// 因此無論以 0.5 秒或 5 秒為一個 tick，相同時間內的結果一致
+func (e *SimpleEngine) EvaluateTick(d *design.Design, s *scenario.Scenario, elapsed, dt float64) (*evaluation.Result, error) {
+	if dt <= 0 {
+		return nil, fmt.Errorf("dt 必須大於 0: %g", dt)
 	}
-	adj := make(map[string][]edgeInfo)
+	designID := d.ID
+
+	// 隨機事件以整數秒為單位判定：second 是目前所在的秒，prevSecond 是上一個 tick 所在的秒
+	second := int64(math.Floor(elapsed))
+	prevSecond := int64(math.Floor(elapsed - dt))
+
+	// 1. 建立連線地圖 (Adjacency List)
+	adj := make(map[string][]edge)
 	for _, conn := range d.Connections {
 		tType := conn.TrafficType
 		if tType == "" {
 			tType = "all"
 		}
-		adj[conn.FromID] = append(adj[conn.FromID], edgeInfo{ToID: conn.ToID, TrafficType: tType})
+		adj[conn.FromID] = append(adj[conn.FromID], edge{ToID: conn.ToID, TrafficType: tType})
 	}
 
 	// 2. 找出所有組件與流量起點
@@ -63,37 +163,44 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 		}
 	}
 
+	// 隨機事件模型：所有突發、驟降、攻擊與故障都由同一個種子決定，確保可重現
+	seed := e.resolveSeed(d)
+	events := NewEventModel(seed)
+	recorder := newEventRecorder(second, compMap)
+
 	// 3. 獲取當前應有的 QPS
 	var baseQPS int64
 	var isBurstActive bool
 	for _, comp := range d.Components {
 		if comp.Type == component.TrafficSource {
-			if v, ok := comp.Properties["start_qps"].(float64); ok {
-				baseQPS = int64(v)
-			} else if v, ok := comp.Properties["start_qps"].(int64); ok {
+			if v, ok := comp.Properties.Int("start_qps"); ok {
 				baseQPS = v
 			}
 
 			// 處理突發流量 (Burst)
-			if burst, ok := comp.Properties["burst_traffic"].(bool); ok && burst {
-				// 每 10 秒會有一次 5 倍流量的突發，持續 3 秒
-				if elapsedSeconds%10 < 3 {
-					baseQPS *= 5
+			if comp.Properties.Enabled("burst_traffic") {
+				// 每 10 秒的窗口內依機率 (預設 30%) 發生一次持續 3 秒的突發
+				probability := comp.Properties.FloatOr("burst_probability", 0.3)
+				if active, multiplier := events.Burst(second, probability); active {
+					baseQPS = int64(float64(baseQPS) * multiplier)
 					isBurstActive = true
+					if wasActive, _ := events.Burst(prevSecond, probability); !wasActive {
+						recorder.emit(evaluation.EventBurstStart, evaluation.SeverityWarning, comp.ID, map[string]interface{}{"multiplier": multiplier})
+					}
 				}
 			}
 		}
 	}
 
 	var currentQPS int64
-	tempElapsed := elapsedSeconds
+	tempElapsed := elapsed
 	for _, phase := range s.Phases {
-		if tempElapsed < int64(phase.DurationSeconds) {
-			progress := float64(tempElapsed) / float64(phase.DurationSeconds)
+		if tempElapsed < float64(phase.DurationSeconds) {
+			progress := tempElapsed / float64(phase.DurationSeconds)
 			currentQPS = phase.StartQPS + int64(float64(phase.EndQPS-phase.StartQPS)*progress)
 			break
 		}
-		tempElapsed -= int64(phase.DurationSeconds)
+		tempElapsed -= float64(phase.DurationSeconds)
 		currentQPS = phase.EndQPS
 	}
 	if currentQPS <= 0 && len(s.Phases) > 0 {
@@ -103,25 +210,33 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 	// 加上使用者設定的初始流量
 	totalBaseQPS := currentQPS + baseQPS
 
+	// 穩定流量模式 (容量測試使用)：關閉自然波動與隨機驟降
+	steadyTraffic := d.Properties.Enabled("steady_traffic")
+
 	// 加上隨機波動 (Fluctuation)
 	// 使用 Sine 波模擬自然波動 (±5%)
-	fluctuation := 1.0 + 0.05*math.Sin(float64(elapsedSeconds)/5.0)
+	fluctuation := 1.0 + 0.05*math.Sin(elapsed/5.0)
+	if steadyTraffic {
+		fluctuation = 1.0
+	}
 
 	// 隨機驟降事件 (Unknown random drops)
 	isRandomDrop := false
 	// 每 15 秒判定一次，有 10% 機率發生 40% 的驟降，持續 3 秒
-	if (elapsedSeconds/15)%10 == 7 && elapsedSeconds%15 < 3 {
+	dropProbability := d.Properties.FloatOr("random_drop_probability", 0.1)
+	if steadyTraffic {
+		dropProbability = 0
+	}
+	if events.RandomDrop(second, dropProbability) {
 		fluctuation *= 0.6
 		isRandomDrop = true
+		if !events.RandomDrop(prevSecond, dropProbability) {
+			recorder.emit(evaluation.EventRandomDrop, evaluation.SeverityInfo, "", map[string]interface{}{"factor": 0.6})
+		}
 	}
 
 	// 使用者留存率 (User Churn / Retention)
-	retentionRate := 1.0
-	if d.Properties != nil {
-		if v, ok := d.Properties["retention_rate"].(float64); ok {
-			retentionRate = v
-		}
-	}
+	retentionRate := d.Properties.FloatOr("retention_rate", 1.0)
 
 	// 最終實際流量
 	currentQPS = int64(float64(totalBaseQPS) * fluctuation * retentionRate)
@@ -131,23 +246,37 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 	currentMaliciousQPS := int64(0)
 
 	enableAttacks := false
+	attackProbability := 0.5
+	enableFailures := false
+	failureProbability := 0.02
 	for _, root := range roots {
-		if v, ok := compMap[root].Properties["enable_attacks"].(bool); ok {
+		props := compMap[root].Properties
+		if v, ok := props.Bool("enable_attacks"); ok {
 			enableAttacks = v
 		}
+		attackProbability = props.FloatOr("attack_probability", attackProbability)
+		if v, ok := props.Bool("enable_failures"); ok {
+			enableFailures = v
+		}
+		failureProbability = props.FloatOr("failure_probability", failureProbability)
 	}
 
-	// 每 40 秒發動一次持續 5 秒的大型突發攻擊
-	if enableAttacks && elapsedSeconds > 15 && elapsedSeconds%40 < 5 {
-		isAttackActive = true
-		// 攻擊流量強度：基礎 3000 QPS + 隨機波動
-		attackIntensity := 3000.0 + math.Abs(math.Sin(float64(elapsedSeconds)))*5000.0
-		currentMaliciousQPS = int64(attackIntensity)
+	// 每 40 秒的窗口內依機率發動一次持續 5 秒的大型突發攻擊
+	// 攻擊流量強度：基礎 3000 QPS + 隨機波動
+	if enableAttacks {
+		isAttackActive, currentMaliciousQPS = events.Attack(second, attackProbability)
+		wasAttackActive, _ := events.Attack(prevSecond, attackProbability)
+		if isAttackActive && !wasAttackActive {
+			recorder.emit(evaluation.EventAttackStart, evaluation.SeverityCritical, "", map[string]interface{}{"malicious_qps": currentMaliciousQPS})
+		} else if !isAttackActive && wasAttackActive {
+			recorder.emit(evaluation.EventAttackStop, evaluation.SeverityInfo, "", nil)
+		}
 	}
 
 	// 4. 核心物理流量模擬：計算負載與截斷
 	visited := make(map[string]bool)
 	crashedNodes := make(map[string]bool)
+	failedNodes := make(map[string]bool)          // 因隨機故障而掛掉的組件
 	compLoads := make(map[string]int64)           // 紀錄組件收到的「總輸入流量」
 	compReadLoads := make(map[string]int64)       // 紀錄組件收到的「讀取流量」
 	compWriteLoads := make(map[string]int64)      // 紀錄組件收到的「寫入流量」
@@ -165,29 +294,56 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 	// 讀寫分離比例 (預設 80% 讀)
 	readRatio := 0.8
 	for _, root := range roots {
-		if v, ok := compMap[root].Properties["read_ratio"].(float64); ok {
+		if v, ok := compMap[root].Properties.Float("read_ratio"); ok {
 			readRatio = v / 100.0
 		}
 	}
 	currentReadQPS := int64(float64(currentQPS) * readRatio)
 	currentWriteQPS := currentQPS - currentReadQPS
 
+	// 各流量類別的請求大小，用於計算 egress 成本
+	sizes := RequestSizes{Read: DefaultReadSizeKB, Write: DefaultWriteSizeKB, Malicious: DefaultAttackSizeKB}
+	for _, root := range roots {
+		props := compMap[root].Properties
+		sizes.Read = props.FloatOr("read_size_kb", sizes.Read)
+		sizes.Write = props.FloatOr("write_size_kb", sizes.Write)
+		sizes.Malicious = props.FloatOr("attack_size_kb", sizes.Malicious)
+	}
+
+	// 成本依 CostModel 計價，並歸屬到組件與流量類別
+	costModel := e.costs()
+	costs := newCostAccount(costModel, d.Components)
+
+	// spot 組件可能被回收：中斷期間流量在此斷掉，也不收取節點成本，恢復後不需手動重啟
+	interruptedNodes := make(map[string]bool)
+	for _, comp := range d.Components {
+		probability := costModel.InterruptionProbability(comp)
+		if comp.Type == component.TrafficSource || probability <= 0 || comp.Properties.Enabled("crashed") {
+			continue
+		}
+		if events.SpotInterruption(second, comp.ID, probability) {
+			interruptedNodes[comp.ID] = true
+			if !events.SpotInterruption(prevSecond, comp.ID, probability) {
+				recorder.emit(evaluation.EventSpotInterrupted, evaluation.SeverityWarning, comp.ID, map[string]interface{}{"seconds": SpotInterruptionSeconds})
+			}
+		}
+	}
+
 	var totalFulfilledQPS int64
 	var totalReadFulfilled int64
 	var totalWriteFulfilled int64
-	var totalOperationalCost float64
 	var totalBaseLatency float64
 	var consistencyScore = 100.0
 	var securityIncidents float64 // 紀錄抵達敏感節點的惡意流量
-	var warnings []string         // 收集架構警告訊息
+	var filteredQPS int64         // 被組件刻意過濾的正常請求 (不算在使用者體驗的錯誤率中)
 
 	// Pass 1: 計算潛在總負載 (Potential Load)
 	// 這一步只累加流量，不進行截斷，也不觸發崩潰邏輯
 	// 目的：讓每個節點知道自己「將會」收到多少流量
 	passesInputLoad := make(map[string]int64)
 
-	var calculateLoad func(string, int64, int64, int64, map[string]bool)
-	calculateLoad = func(id string, read, write, mal int64, pathVisited map[string]bool) {
+	var calculateLoad func(string, Flow, map[string]bool)
+	calculateLoad = func(id string, in Flow, pathVisited map[string]bool) {
 		comp, exists := compMap[id]
 		if !exists {
 			return
@@ -201,58 +357,23 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 		}
 		newPathVisited[id] = true
 
-		passesInputLoad[id] += (read + write + mal)
-
-		downstreamEdges := adj[id]
-		if len(downstreamEdges) > 0 {
-			outRead, outWrite := read, write
-
-			// 快取/CDN 特性：讀取會被攔截 (Hit)，寫入會穿透 (Pass-through)
-			if comp.Type == component.Cache || comp.Type == component.CDN {
-				outRead = int64(float64(read) * 0.2) // 假設 80% Cache Hit
-				outWrite = write                     // 寫入 100% 穿透
-			}
-
-			malOutput := int64(float64(mal) * 1.0)
-			if comp.Type == component.WAF {
-				malOutput = int64(float64(mal) * 0.1)
-			}
-
-			// 計算接受讀取和寫入的連線數量
-			readTargets := 0
-			writeTargets := 0
-			for _, edge := range downstreamEdges {
-				if edge.TrafficType == "all" || edge.TrafficType == "read" {
-					readTargets++
-				}
-				if edge.TrafficType == "all" || edge.TrafficType == "write" {
-					writeTargets++
-				}
-			}
-
-			for _, edge := range downstreamEdges {
-				var rSplit, wSplit, mSplit int64
-				if (edge.TrafficType == "all" || edge.TrafficType == "read") && readTargets > 0 {
-					rSplit = outRead / int64(readTargets)
-				}
-				if (edge.TrafficType == "all" || edge.TrafficType == "write") && writeTargets > 0 {
-					wSplit = outWrite / int64(writeTargets)
-				}
-				// 惡意流量均分給所有連線
-				mSplit = malOutput / int64(len(downstreamEdges))
+		passesInputLoad[id] += in.Total() + in.Malicious
 
-				calculateLoad(edge.ToID, rSplit, wSplit, mSplit, newPathVisited)
+		if edges := adj[id]; len(edges) > 0 {
+			out := BehaviorFor(comp.Type).Output(in)
+			for i, f := range splitFlow(edges, out) {
+				calculateLoad(edges[i].ToID, f, newPathVisited)
 			}
 		}
 	}
 
 	for _, root := range roots {
-		calculateLoad(root, currentReadQPS, currentWriteQPS, currentMaliciousQPS, make(map[string]bool))
+		calculateLoad(root, Flow{Read: currentReadQPS, Write: currentWriteQPS, Malicious: currentMaliciousQPS}, make(map[string]bool))
 	}
 
 	// Pass 2: 實際流量傳播 (Actual Flow Propagation)
-	var propagateFlow func(string, int64, int64, int64, map[string]bool)
-	propagateFlow = func(id string, read, write, mal int64, pathVisited map[string]bool) {
+	var propagateFlow func(string, Flow, map[string]bool)
+	propagateFlow = func(id string, in Flow, pathVisited map[string]bool) {
 		comp, exists := compMap[id]
 		if !exists {
 			return
@@ -268,478 +389,148 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 		newPathVisited[id] = true
 
 		// 檢查持久性崩潰
-		if crashed, ok := comp.Properties["crashed"].(bool); ok && crashed {
+		if comp.Properties.Enabled("crashed") {
 			crashedNodes[id] = true
 			return
 		}
 
-		baseMaxQPS := getCompMaxQPS(comp)
-		currentMaxQPS := baseMaxQPS
-
-		// 基礎開課成本 (Setup + Operational)
-		compCost := comp.OperationalCost
-		if compCost == 0 {
-			// 預設維運成本
-			switch comp.Type {
-			case component.WAF:
-				compCost = 0.15
-			case component.LoadBalancer:
-				compCost = 0.1
-			case component.Database:
-				compCost = 0.5
-			case component.Cache:
-				compCost = 0.3
-			case component.WebServer:
-				compCost = 0.2
-			case component.APIGateway:
-				compCost = 0.15
-			case component.NoSQL:
-				compCost = 0.4
-			}
+		// 隨機故障 (如硬體損壞)：與負載無關，需由玩家手動重啟
+		// 每秒判定一次，tick 跨越多秒時逐秒判定，跨越不到一秒時只在進入新的一秒時判定
+		if enableFailures && failedDuring(events, prevSecond, second, id, failureProbability) {
+			crashedNodes[id] = true
+			failedNodes[id] = true
+			recorder.emit(evaluation.EventComponentFailure, evaluation.SeverityCritical, id, nil)
+			return
 		}
-		totalOperationalCost += compCost
 
-		// 基礎延遲累積
-		if v, ok := comp.Properties["base_latency"].(float64); ok {
-			totalBaseLatency += v
-		} else if v, ok := comp.Properties["base_latency"].(int64); ok {
-			totalBaseLatency += float64(v)
-		} else {
-			// 預設延遲
-			switch comp.Type {
-			case component.LoadBalancer:
-				totalBaseLatency += 5.0
-			case component.WebServer:
-				totalBaseLatency += 20.0
-			case component.Database:
-				totalBaseLatency += 50.0
-			case component.MessageQueue:
-				totalBaseLatency += 200.0 // MQ 的非同步延遲代價
-				consistencyScore -= 5.0   // MQ 引入最終一致性風險
-			case component.Cache, component.CDN:
-				totalBaseLatency += 2.0
-				consistencyScore -= 10.0 // Cache 引入資料不一致風險
-			case component.APIGateway:
-				totalBaseLatency += 2.0
-			case component.NoSQL:
-				totalBaseLatency += 10.0
-				consistencyScore -= 15.0 // NoSQL 通常為最終一致性
-			case component.ExternalAPI:
-				totalBaseLatency += 200.0 // 第三方服務通常很慢
-			}
+		if interruptedNodes[id] {
+			return
 		}
 
+		behavior := BehaviorFor(comp.Type)
+		traits := behavior.Traits()
+
 		// 這裡使用 Pass 1 計算出的 "潛在總流量" 來判斷是否崩潰
 		// 因為崩潰是看「嘗試打進來的量」，而不是「成功擠進來的量」
-		potentialTotalLoad := passesInputLoad[id]
-		compLoads[id] = potentialTotalLoad // 前端顯示的是「嘗試請求量」
-		compReadLoads[id] = read           // 記錄讀取流量
-		compWriteLoads[id] = write         // 記錄寫入流量
-
-		// Auto Scaling Logic (Shared by WebServer and AutoScalingGroup)
-		if comp.Type == component.WebServer || comp.Type == component.AutoScalingGroup {
-			if auto, ok := comp.Properties["auto_scaling"].(bool); ok && auto {
-				maxReplicas := 5
-				if v, ok := comp.Properties["max_replicas"].(float64); ok {
-					maxReplicas = int(v)
-				}
-
-				threshold := 70.0 // 預設 70% 資源使用率就擴展
-				if v, ok := comp.Properties["scale_up_threshold"].(float64); ok {
-					threshold = v
-				}
-
-				warmup := int64(10) // 預設 10 秒暖機
-				if v, ok := comp.Properties["warmup_seconds"].(float64); ok {
-					warmup = int64(v)
-				}
-
-				// 擴展指標：預設使用 CPU，也可以設定為 RAM
-				scaleMetric := "cpu"
-				if v, ok := comp.Properties["scale_metric"].(string); ok {
-					scaleMetric = v
-				}
-
-				// 考慮暖機時間 (由前端傳來的啟動時間清單)
-				activeCount := 1
-				bootingCount := 0
-				if startTimes, ok := comp.Properties["replica_start_times"].([]interface{}); ok {
-					for _, tObj := range startTimes {
-						startTime := int64(tObj.(float64))
-						if elapsedSeconds-startTime >= warmup {
-							activeCount++
-						} else {
-							bootingCount++
-						}
-					}
-				}
-
-				// 確保至少有一台基礎機器
-				if activeCount < 1 {
-					activeCount = 1
-				}
-
-				// 計算當前單機的資源使用率
-				effectiveResourceLoad := float64(read) + float64(write)*4.0 // 寫入加權
-				currentCPUUsage := 0.0
-				currentRAMUsage := 0.0
-
-				if baseMaxQPS > 0 {
-					// CPU 使用率計算
-					currentCPUUsage = math.Min(100.0, 10.0+(effectiveResourceLoad/float64(baseMaxQPS)/float64(activeCount))*90.0)
-
-					// RAM 使用率計算（簡化版）
-					currentRAMUsage = math.Min(100.0, 20.0+(float64(potentialTotalLoad)/float64(baseMaxQPS)/float64(activeCount))*60.0)
-				}
-
-				// 根據選擇的指標決定是否擴展
-				currentMetricValue := currentCPUUsage
-				if scaleMetric == "ram" {
-					currentMetricValue = currentRAMUsage
-				}
+		ctx := &TickContext{
+			Elapsed:         elapsed,
+			DT:              dt,
+			Component:       comp,
+			Load:            passesInputLoad[id],
+			BaseMaxQPS:      behavior.MaxQPS(comp),
+			recorder:        recorder,
+			costs:           costs,
+			latency:         &totalBaseLatency,
+			consistency:     &consistencyScore,
+			backlogs:        compBacklogs,
+			effectiveMaxQPS: compEffectiveMaxQPS,
+		}
+
+		// 基礎開課成本 (Setup + Operational)，未設定時使用該類型的預設維運成本
+		ctx.AddNodeCost(nodePrice(comp, traits), 1)
+
+		// 基礎延遲累積，未設定時使用該類型的預設延遲與一致性代價
+		if v, ok := comp.Properties.Float("base_latency"); ok {
+			totalBaseLatency += v
+		} else {
+			totalBaseLatency += traits.LatencyMS
+			consistencyScore -= traits.ConsistencyPenalty
+		}
 
-				// 計算目標副本數
-				targetReplicas := activeCount
-				if currentMetricValue > threshold && activeCount < maxReplicas {
-					// 需要擴展：根據使用率計算需要多少台機器
-					// 例如：如果當前 CPU 90%，閾值 70%，則需要 90/70 = 1.28 倍的機器
-					needed := math.Ceil(float64(activeCount) * currentMetricValue / threshold)
-					targetReplicas = int(needed)
-					if targetReplicas > maxReplicas {
-						targetReplicas = maxReplicas
-					}
-				} else if currentMetricValue < threshold*0.5 && activeCount > 1 {
-					// 縮容：如果使用率低於閾值的 50%，可以縮減
-					targetReplicas = activeCount - 1
-				}
+		compLoads[id] = ctx.Load      // 前端顯示的是「嘗試請求量」
+		compReadLoads[id] = in.Read   // 記錄讀取流量
+		compWriteLoads[id] = in.Write // 記錄寫入流量
+		// 惡意流量在崩潰判定前記錄，被攻擊打掛的組件也看得出收到多少惡意請求 (供根因分析歸因)
+		compMaliciousLoads[id] += in.Malicious
 
-				currentMaxQPS = baseMaxQPS * int64(activeCount)
-				compReplicas[id] = activeCount + bootingCount
-				// ASG 額外成本：每台機器都要算錢
-				totalOperationalCost += comp.OperationalCost * float64(activeCount+bootingCount-1)
-			} else {
-				compReplicas[id] = 1
-			}
-		}
-		compEffectiveMaxQPS[id] = currentMaxQPS
+		// Auto Scaling：決定有效處理能力與副本數
+		ctx.MaxQPS, compReplicas[id] = behavior.Scale(ctx, in)
+		compEffectiveMaxQPS[id] = ctx.MaxQPS
 
 		// 判斷崩潰
 		isGracePeriod := false
-		if restartedAt, ok := comp.Properties["restartedAt"].(float64); ok {
-			if float64(elapsedSeconds)-restartedAt < 5.0 {
-				isGracePeriod = true
-			}
-		} else if restartedAt, ok := comp.Properties["restartedAt"].(int64); ok {
-			if elapsedSeconds-restartedAt < 5 {
-				isGracePeriod = true
-			}
-		}
-
-		// 崩潰閾值設定
-		crashThreshold := 1.5
-		if comp.Type == component.AutoScalingGroup {
-			crashThreshold = 3.0 // ASG 具有一定的彈性緩衝，允許短暫過載以等待機器啟動
-		} else if comp.Type == component.MessageQueue || comp.Type == component.ObjectStorage {
-			crashThreshold = 50.0 // MQ 和 ObjectStorage 非常難以崩潰
-		} else if comp.Type == component.LoadBalancer || comp.Type == component.CDN || comp.Type == component.WAF {
-			crashThreshold = 5.0 // Infra 組件相對耐用
+		if restartedAt, ok := comp.Properties.Float("restartedAt"); ok && elapsed-restartedAt < 5.0 {
+			isGracePeriod = true
 		}
 
-		if !isGracePeriod && currentMaxQPS > 0 && potentialTotalLoad > int64(float64(currentMaxQPS)*crashThreshold) {
+		if !isGracePeriod && ctx.MaxQPS > 0 && ctx.Load > int64(float64(ctx.MaxQPS)*traits.CrashThreshold) {
 			crashedNodes[id] = true
+			recorder.emit(evaluation.EventComponentCrash, evaluation.SeverityCritical, id, map[string]interface{}{
+				"load":      ctx.Load,
+				"max_qps":   ctx.MaxQPS,
+				"threshold": traits.CrashThreshold,
+			})
 			return // 崩潰，流量在此斷掉
 		}
 
 		// --- 資源消耗計算 ---
-		// CPU 消耗：與負載成正比，100% 負荷代表達到處理上限
-		cpu := 10.0 // 基礎 CPU 消耗 (Idle)
-		// 如果是 ASG，計算的是「平均單機 CPU」，因為流量會被 LB 均分
-		if comp.Type == component.AutoScalingGroup && baseMaxQPS > 0 {
-			activeNodes := 1
-			if startTimes, ok := comp.Properties["replica_start_times"].([]interface{}); ok {
-				activeNodes = 1 + len(startTimes) // 假設所有 replica 都參與分擔 (或僅 active，此處暫採全部，因 booting 也佔資源)
-			}
-			// 平均單機負載
-			avgLoadPerNode := float64(potentialTotalLoad) / float64(activeNodes)
-			cpu += (avgLoadPerNode / float64(baseMaxQPS)) * 90.0
-		} else if currentMaxQPS > 0 {
-			cpu += (float64(potentialTotalLoad) / float64(currentMaxQPS)) * 90.0
-		}
+		cpu, ram := behavior.Resources(ctx)
 		compCPUUsage[id] = math.Min(100.0, cpu) // CPU 使用率最高 100%
-
-		// RAM 消耗：不同組件有不同特性
-		ram := 15.0 // 基礎 RAM 消耗
-
-		// 為了計算準確，ASG 類型的 RAM 也是算單機平均
-		effectiveLoadForRAM := float64(potentialTotalLoad)
-		effectiveMaxForRAM := float64(currentMaxQPS)
-		if comp.Type == component.AutoScalingGroup && baseMaxQPS > 0 {
-			activeNodes := 1
-			if startTimes, ok := comp.Properties["replica_start_times"].([]interface{}); ok {
-				activeNodes = 1 + len(startTimes)
-			}
-			effectiveLoadForRAM = float64(potentialTotalLoad) / float64(activeNodes)
-			effectiveMaxForRAM = float64(baseMaxQPS)
-		}
-
-		switch comp.Type {
-		case component.Cache:
-			// 快取組件：RAM 隨流量增長而填滿 (模擬快取物件增加)
-			if effectiveMaxForRAM > 0 {
-				ram += (effectiveLoadForRAM / effectiveMaxForRAM) * 70.0
-			}
-		case component.MessageQueue:
-			// MQ 組件：RAM 隨積壓量 (Backlog) 增加
-			prevBacklog := int64(0)
-			if v, ok := comp.Properties["backlog"].(float64); ok {
-				prevBacklog = int64(v)
-			}
-			ram += (float64(prevBacklog) / 50000.0) * 80.0 // 假設 5萬筆積壓會爆 RAM
-		case component.Worker:
-			// Worker: 記憶體消耗較高，與負載相關
-			if effectiveMaxForRAM > 0 {
-				ram += 20.0 + (effectiveLoadForRAM/effectiveMaxForRAM)*40.0
-			}
-		case component.VideoTranscoding:
-			// 影片轉碼：極高 CPU 和中等 RAM 消耗
-			// CPU: 轉碼是 CPU 密集型任務
-			cpu += 40.0 // 基礎 CPU 消耗就很高
-			if currentMaxQPS > 0 {
-				cpu += (float64(potentialTotalLoad) / float64(currentMaxQPS)) * 50.0
-			}
-			// RAM: 需要載入影片到記憶體
-			if effectiveMaxForRAM > 0 {
-				ram += 30.0 + (effectiveLoadForRAM/effectiveMaxForRAM)*40.0
-			}
-		case component.Database, component.NoSQL:
-			ram += 30.0 + (effectiveLoadForRAM/10000.0)*20.0
-		case component.ExternalAPI:
-			// 外部 API 不佔我們自己的資源，基礎佔用極低
-			ram = 5.0
-			cpu = 2.0
-		default:
-			if effectiveMaxForRAM > 0 {
-				ram += (effectiveLoadForRAM / 20000.0) * 10.0
-			} else {
-				ram += (effectiveLoadForRAM / 20000.0) * 10.0
-			}
-		}
 		compRAMUsage[id] = math.Min(100.0, ram) // RAM 使用率最高 100%
 
 		// OOM (Out of Memory) 判定
 		if !isGracePeriod && ram > 100.0 {
 			crashedNodes[id] = true
+			recorder.emit(evaluation.EventComponentOOM, evaluation.SeverityCritical, id, map[string]interface{}{"ram_usage": ram})
 			return // OOM 崩潰
 		}
 		// ------------------
 
 		visited[id] = true
-		compMaliciousLoads[id] += mal
-
-		// 截斷邏輯 (Capping)
-		actualRead := read
-		actualWrite := write
-		actualMalProcessed := mal
-
-		// WAF 過濾
-		if comp.Type == component.WAF {
-			actualMalProcessed = int64(float64(mal) * 0.1)
-			actualRead = int64(float64(read) * 0.98)
-			actualWrite = int64(float64(write) * 0.98)
-		}
-
-		// 外部 API 特定邏輯：模擬 SLA 丟包與按量計費
-		if comp.Type == component.ExternalAPI {
-			sla := 0.99
-			if v, ok := comp.Properties["sla"].(float64); ok {
-				sla = v / 100.0
-			}
-			actualRead = int64(float64(actualRead) * sla)
-			actualWrite = int64(float64(actualWrite) * sla)
 
-			// 額外計費：模擬第三方服務按量收費 (例如每 1000 請求 $0.1)
-			totalOperationalCost += float64(actualRead+actualWrite) * 0.0001
-		}
+		// 組件過濾 (如 WAF、外部 API 的 SLA 丟包)
+		actual := behavior.Filter(ctx, in)
+		filteredQPS += in.Total() - actual.Total()
+		costs.charge(id, costModel.Requests(comp, traits, actual))
 
 		// 資源放大效應：寫入操作通常比讀取消耗多 3-5 倍 CPU
-		effectiveResourceLoad := float64(actualRead) + float64(actualWrite)*4.0
+		effectiveResourceLoad := float64(actual.Read) + float64(actual.Write)*4.0
 
 		// 重新計算 CPU (考慮寫入加權)
-		if currentMaxQPS > 0 {
-			compCPUUsage[id] = math.Min(100.0, 10.0+(effectiveResourceLoad/float64(currentMaxQPS))*90.0)
+		if ctx.MaxQPS > 0 {
+			compCPUUsage[id] = math.Min(100.0, 10.0+(effectiveResourceLoad/float64(ctx.MaxQPS))*90.0)
 		}
 
 		// 安全判定
-		if (comp.Type == component.Database || comp.Type == component.NoSQL) && actualMalProcessed > 0 {
+		if traits.Sensitive && actual.Malicious > 0 {
 			isProtected := false
 			for vid := range visited {
-				if compMap[vid].Type == component.APIGateway {
+				if BehaviorFor(compMap[vid].Type).Traits().Gateway {
 					isProtected = true
 					break
 				}
 			}
-			threat := float64(actualMalProcessed)
+			threat := float64(actual.Malicious)
 			if isProtected {
 				threat *= 0.5
 			}
 			securityIncidents += threat
 		}
 
-		var queuingDelay float64
-		var actualProcessed int64
-
-		// Message Queue 特殊邏輯... (略)
-		if comp.Type == component.MessageQueue {
-			// 1. MQ 自身的 I/O 吞吐量限制
-			mqIoLimit := currentMaxQPS
-
-			// 2. 下游消費者的總處理能力 (Consumer-Driven)
-			downstreamCapacity := int64(0)
-			for _, edge := range adj[id] {
-				if dsComp, ok := compMap[edge.ToID]; ok {
-					// 如果下游已經掛了，則不提供處理能力
-					if crashedNodes[edge.ToID] {
-						continue
-					}
-					// 這裡拿的是下游的基礎或有效容量
-					// 簡化模型：拿下游的 getCompMaxQPS (如果是 WebServer，通常是單機或 Cluster 的容量)
-					downstreamCapacity += getCompMaxQPS(dsComp)
-				}
-			}
-
-			// 實際處理速度受限於「MQ 吞吐量」與「消費者能力」的最小值
-			effectiveProcessingRate := mqIoLimit
-			if downstreamCapacity > 0 && downstreamCapacity < mqIoLimit {
-				effectiveProcessingRate = downstreamCapacity
-			} else if downstreamCapacity == 0 {
-				// 如果下游完全沒有可用的消費者，則處理能力為 0
-				effectiveProcessingRate = 0
-			}
-
-			// 從 Properties 獲取上一次的積壓量
-			prevBacklog := int64(0)
-			if v, ok := comp.Properties["backlog"].(float64); ok {
-				prevBacklog = int64(v)
-			} else if v, ok := comp.Properties["backlog"].(int64); ok {
-				prevBacklog = v
-			}
-
-			// 嘗試處理
-			attemptLoad := read + write + prevBacklog
-			if effectiveProcessingRate > 0 && attemptLoad > effectiveProcessingRate {
-				actualProcessed = effectiveProcessingRate
-				compBacklogs[id] = attemptLoad - effectiveProcessingRate
-			} else {
-				actualProcessed = attemptLoad
-				compBacklogs[id] = 0
+		// 下游消費者的總處理能力 (如果下游已經掛了，則不提供處理能力)
+		for _, edge := range adj[id] {
+			if ds, ok := compMap[edge.ToID]; ok && !crashedNodes[edge.ToID] && !interruptedNodes[edge.ToID] {
+				ctx.DownstreamCapacity += BehaviorFor(ds.Type).MaxQPS(ds)
 			}
-			// MQ 比例縮減讀寫
-			ratio := 1.0
-			if attemptLoad > 0 {
-				ratio = float64(actualProcessed) / float64(attemptLoad)
-			}
-			actualRead = int64(float64(read) * ratio)
-			actualWrite = int64(float64(write) * ratio)
-
-			// MQ 延遲代價
-			if effectiveProcessingRate > 0 {
-				queuingDelay = (float64(compBacklogs[id]) / float64(effectiveProcessingRate)) * 1000.0
-			}
-
-			// 更新顯示用的有效 MaxQPS
-			compEffectiveMaxQPS[id] = effectiveProcessingRate
-		} else {
-			if currentMaxQPS > 0 && potentialTotalLoad > currentMaxQPS {
-				factor := float64(currentMaxQPS) / float64(potentialTotalLoad)
-				actualRead = int64(float64(read) * factor)
-				actualWrite = int64(float64(write) * factor)
-			}
-			actualProcessed = actualRead + actualWrite
 		}
-		totalBaseLatency += queuingDelay
 
-		// componentProcessedQPS[id] += actualProcessed // 如果需要統計實際處理量
+		// 截斷或排隊
+		actual = behavior.Process(ctx, actual)
+		costs.charge(id, costModel.Egress(comp, actual, sizes))
 
 		// 計算「成功取得資料」
-		fulfilledRead, fulfilledWrite := int64(0), int64(0)
-		if comp.Type == component.Cache || comp.Type == component.CDN {
-			fulfilledRead = int64(float64(actualRead) * 0.8) // 80% 命中
-		} else if comp.Type == component.Database || comp.Type == component.NoSQL || comp.Type == component.ObjectStorage || comp.Type == component.SearchEngine {
-			// Slave DB 限制：只能處理讀取
-			isSlave := false
-			if mode, ok := comp.Properties["replication_mode"].(string); ok && mode == "SLAVE" {
-				isSlave = true
-			}
-
-			fulfilledRead = actualRead
-			if !isSlave {
-				fulfilledWrite = actualWrite
-			} else if actualWrite > 0 {
-				// 寫到 Slave 會降低一致性分數
-				consistencyScore -= 1.0
-				// 記錄警告訊息
-				warnings = append(warnings, fmt.Sprintf("[架構警告] Slave DB '%s' 收到 %d QPS 寫入流量！Slave 僅能處理讀取請求，請將寫入流量導向 Master。", comp.Name, actualWrite))
-			}
-		}
+		fulfilledRead, fulfilledWrite := behavior.Fulfill(ctx, actual)
 		totalReadFulfilled += fulfilledRead
 		totalWriteFulfilled += fulfilledWrite
 		totalFulfilledQPS = totalReadFulfilled + totalWriteFulfilled
 
-		downstreamEdges := adj[id]
-		if len(downstreamEdges) > 0 {
-			outRead, outWrite := actualRead, actualWrite
-
-			// 快閃命中：扣除已被快取攔截的「讀取」流量
-			if comp.Type == component.Cache || comp.Type == component.CDN {
-				outRead = int64(float64(actualRead) * 0.2)
-			}
-
-			malOutput := int64(float64(actualMalProcessed) * 1.0)
-			if comp.Type == component.WAF {
-				malOutput = int64(float64(actualMalProcessed) * 0.1)
-			}
-
-			// 計算接受讀取和寫入的連線數量
-			readTargets := 0
-			writeTargets := 0
-			for _, edge := range downstreamEdges {
-				if edge.TrafficType == "all" || edge.TrafficType == "read" {
-					readTargets++
+		if edges := adj[id]; len(edges) > 0 {
+			out := behavior.Output(actual)
+			for i, f := range splitFlow(edges, out) {
+				if to, ok := compMap[edges[i].ToID]; ok {
+					f = behavior.Deliver(ctx, to, f)
 				}
-				if edge.TrafficType == "all" || edge.TrafficType == "write" {
-					writeTargets++
-				}
-			}
-
-			for _, edge := range downstreamEdges {
-				var rSplit, wSplit, mSplit int64
-				if (edge.TrafficType == "all" || edge.TrafficType == "read") && readTargets > 0 {
-					rSplit = outRead / int64(readTargets)
-				}
-				if (edge.TrafficType == "all" || edge.TrafficType == "write") && writeTargets > 0 {
-					wSplit = outWrite / int64(writeTargets)
-				}
-				mSplit = malOutput / int64(len(downstreamEdges))
-
-				// 特殊邏輯：MQ PULL 模式
-				if comp.Type == component.MessageQueue {
-					if mode, ok := comp.Properties["delivery_mode"].(string); ok && mode == "PULL" {
-						downstreamComp, exists := compMap[edge.ToID]
-						if exists {
-							dsMaxCap := getMaxPotentialCapacity(downstreamComp)
-							if dsMaxCap > 0 && (rSplit+wSplit) > dsMaxCap {
-								totalSplit := rSplit + wSplit
-								if totalSplit > 0 {
-									ratio := float64(dsMaxCap) / float64(totalSplit)
-									rSplit = int64(float64(rSplit) * ratio)
-									wSplit = int64(float64(wSplit) * ratio)
-								}
-							}
-						}
-					}
-				}
-
-				propagateFlow(edge.ToID, rSplit, wSplit, mSplit, newPathVisited)
+				propagateFlow(edges[i].ToID, f, newPathVisited)
 			}
 		}
 	}
@@ -750,54 +541,29 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 		compWriteLoads[root] = currentWriteQPS
 		visited[root] = true
 
-		downstreamEdges := adj[root]
-		if len(downstreamEdges) > 0 {
-			// 同樣需要計算分流
-			readTargets := 0
-			writeTargets := 0
-			for _, edge := range downstreamEdges {
-				if edge.TrafficType == "all" || edge.TrafficType == "read" {
-					readTargets++
-				}
-				if edge.TrafficType == "all" || edge.TrafficType == "write" {
-					writeTargets++
-				}
-			}
-
-			for _, edge := range downstreamEdges {
-				var rSplit, wSplit, mSplit int64
-				if (edge.TrafficType == "all" || edge.TrafficType == "read") && readTargets > 0 {
-					rSplit = currentReadQPS / int64(readTargets)
-				}
-				if (edge.TrafficType == "all" || edge.TrafficType == "write") && writeTargets > 0 {
-					wSplit = currentWriteQPS / int64(writeTargets)
-				}
-				mSplit = currentMaliciousQPS / int64(len(downstreamEdges))
-
-				propagateFlow(edge.ToID, rSplit, wSplit, mSplit, make(map[string]bool))
-			}
+		edges := adj[root]
+		for i, f := range splitFlow(edges, Flow{Read: currentReadQPS, Write: currentWriteQPS, Malicious: currentMaliciousQPS}) {
+			propagateFlow(edges[i].ToID, f, make(map[string]bool))
 		}
 	}
+	// 預留容量即使這個 tick 沒有運作也要付費
+	costs.chargeCommitted(d.Components)
+	totalOperationalCost := costs.total
 
 	// 5. 綜合評估 (以資料獲取成功率為核心)
-	successRate := 0.0
+	successRate, errorRate := 0.0, 0.0
 	if currentQPS > 0 {
 		successRate = float64(totalFulfilledQPS) / float64(currentQPS)
 		if successRate > 1.0 {
 			successRate = 1.0
 		}
+		errorRate = 1.0 - successRate
 	}
 
 	// 綜合可靠性維度 (考慮冗餘設計)
 	reliabilityScore := 100.0 - (float64(len(crashedNodes)) * 10.0)
 	for _, comp := range compMap {
-		if comp.Type == component.Database {
-			if mode, ok := comp.Properties["replication_mode"].(string); ok && mode == "MASTER_SLAVE" {
-				if v, ok := comp.Properties["slave_count"].(float64); ok && v > 0 {
-					reliabilityScore += 10.0 // 有 MS 備援加分
-				}
-			}
-		}
+		reliabilityScore += BehaviorFor(comp.Type).ReliabilityBonus(comp) // 如 DB 有主從備援加分
 	}
 	if reliabilityScore > 100 {
 		reliabilityScore = 100
@@ -831,7 +597,7 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 	for id, load := range compLoads {
 		maxQPS := compEffectiveMaxQPS[id]
 		if maxQPS == 0 {
-			maxQPS = getCompMaxQPS(compMap[id])
+			maxQPS = BehaviorFor(compMap[id].Type).MaxQPS(compMap[id])
 		}
 		if maxQPS > 0 {
 			utilization := float64(load) / float64(maxQPS)
@@ -849,12 +615,7 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 	}
 
 	// 成本評估：從關卡讀取預算限制
-	budget := 50.0
-	for _, c := range s.Constraints {
-		if c.Type == "budget" {
-			budget = float64(c.Value)
-		}
-	}
+	budget := scenarioBudget(s)
 
 	costScore := 100.0
 	if totalOperationalCost > budget {
@@ -866,20 +627,21 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 		consistencyScore = 0
 	}
 
-	comment := "系統穩定運行中。"
+	healthMessage := "score.health.stable"
 	if successRate < 0.1 {
-		comment = "警告：使用者幾乎拿不到資料，請檢查伺服器與資料庫連線！"
+		healthMessage = "score.health.critical"
 	} else if successRate < 0.95 {
-		comment = "提示：部分請求失敗，建議優化架構或增加容量。"
+		healthMessage = "score.health.degraded"
 	}
 
+	// 評分說明只記錄訊息 ID 與參數，文字由 Localize 依語系產生
 	scores := []evaluation.Score{
-		{Dimension: "System Health", Value: totalScore, Comment: comment},
-		{Dimension: "Performance", Value: math.Max(0, 100-(avgLatency-100)/20), Comment: fmt.Sprintf("平均延遲: %.1f ms", avgLatency)},
-		{Dimension: "Reliability", Value: reliabilityScore, Comment: "基於冗餘設計與崩潰頻率的可靠性評分。"},
-		{Dimension: "Security", Value: securityScore, Comment: "抵達核心節點的惡意流量會降低安全性。"},
-		{Dimension: "Cost Efficiency", Value: costScore, Comment: fmt.Sprintf("每秒運維成本: $%.2f", totalOperationalCost)},
-		{Dimension: "Data Consistency", Value: consistencyScore, Comment: "快取或異步隊列會降低即時一致性。"},
+		{Dimension: "System Health", Value: totalScore, MessageID: healthMessage},
+		{Dimension: "Performance", Value: math.Max(0, 100-(avgLatency-100)/20), MessageID: "score.performance", Params: map[string]interface{}{"latency": avgLatency}},
+		{Dimension: "Reliability", Value: reliabilityScore, MessageID: "score.reliability"},
+		{Dimension: "Security", Value: securityScore, MessageID: "score.security"},
+		{Dimension: "Cost Efficiency", Value: costScore, MessageID: "score.cost", Params: map[string]interface{}{"cost": totalOperationalCost}},
+		{Dimension: "Data Consistency", Value: consistencyScore, MessageID: "score.consistency"},
 	}
 
 	activeIDs := make([]string, 0, len(visited))
@@ -890,17 +652,33 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 	for id := range crashedNodes {
 		crashedIDs = append(crashedIDs, id)
 	}
+	failedIDs := make([]string, 0, len(failedNodes))
+	for id := range failedNodes {
+		failedIDs = append(failedIDs, id)
+	}
+	interruptedIDs := make([]string, 0, len(interruptedNodes))
+	for id := range interruptedNodes {
+		interruptedIDs = append(interruptedIDs, id)
+	}
+	// 依 ID 排序，相同的輸入才會產生完全相同的結果 (重播時以雜湊比對)
+	sort.Strings(activeIDs)
+	sort.Strings(crashedIDs)
+	sort.Strings(failedIDs)
+	sort.Strings(interruptedIDs)
 
-	return &evaluation.Result{
+	res := &evaluation.Result{
 		DesignID:                 designID,
 		ScenarioID:               d.ScenarioID,
 		TotalScore:               totalScore,
 		Scores:                   scores,
 		Passed:                   totalScore >= 95.0,
 		AvgLatencyMS:             avgLatency,
+		ErrorRate:                errorRate,
 		TotalReadQPS:             currentReadQPS,
 		TotalWriteQPS:            currentWriteQPS,
-		CreatedAt:                elapsedSeconds,
+		CreatedAt:                second,
+		Elapsed:                  elapsed,
+		DT:                       dt,
 		ActiveComponentIDs:       activeIDs,
 		CrashedComponentIDs:      crashedIDs,
 		ComponentLoads:           compLoads,
@@ -920,45 +698,49 @@ func (e *SimpleEngine) Evaluate(designID string, elapsedSeconds int64) (*evaluat
 		ComponentRAMUsage:        compRAMUsage,
 		ComponentReadLoads:       compReadLoads,
 		ComponentWriteLoads:      compWriteLoads,
-		Warnings:                 warnings,
-	}, nil
+		Seed:                     seed,
+		Events:                   recorder.events,
+		FailedComponentIDs:       failedIDs,
+		InterruptedComponentIDs:  interruptedIDs,
+		ComponentCosts:           costs.byComponent,
+		TrafficCosts:             costs.byClass,
+		FilteredQPS:              filteredQPS,
+	}
+	res.Localize(i18n.DefaultLocale)
+	return res, nil
 }
 
-func getCompMaxQPS(comp component.Component) int64 {
-	base := int64(0)
-	if v, ok := comp.Properties["max_qps"].(int64); ok {
-		base = v
-	} else if v, ok := comp.Properties["max_qps"].(float64); ok {
-		base = int64(v)
+// failedDuring 判斷組件在 (prevSecond, second] 之間的任何一秒是否發生隨機故障
+func failedDuring(events *EventModel, prevSecond, second int64, id string, probability float64) bool {
+	for sec := prevSecond + 1; sec <= second; sec++ {
+		if events.Failure(sec, id, probability) {
+			return true
+		}
 	}
+	return false
+}
 
-	if comp.Type == component.Database {
-		if mode, ok := comp.Properties["replication_mode"].(string); ok && mode == "MASTER_SLAVE" {
-			slaves := 0
-			if v, ok := comp.Properties["slave_count"].(float64); ok {
-				slaves = int(v)
-			}
-			// 主從架構：Slaves 增加讀取 QPS 能力 (假設提升 100% per slave)
-			return base * int64(1+slaves)
+// splitFlow 將流量分給下游連線：讀取只分給 all/read 連線、寫入只分給 all/write 連線，惡意流量均分給所有連線
+func splitFlow(edges []edge, f Flow) []Flow {
+	readTargets, writeTargets := 0, 0
+	for _, e := range edges {
+		if e.TrafficType == "all" || e.TrafficType == "read" {
+			readTargets++
+		}
+		if e.TrafficType == "all" || e.TrafficType == "write" {
+			writeTargets++
 		}
-	} else if comp.Type == component.NoSQL || comp.Type == component.APIGateway {
-		return base
 	}
-	return base
-}
 
-func getMaxPotentialCapacity(comp component.Component) int64 {
-	base := getCompMaxQPS(comp)
-	if comp.Type == component.WebServer || comp.Type == component.AutoScalingGroup {
-		if auto, ok := comp.Properties["auto_scaling"].(bool); ok && auto {
-			maxReplicas := 5
-			if v, ok := comp.Properties["max_replicas"].(float64); ok {
-				maxReplicas = int(v)
-			} else if v, ok := comp.Properties["max_replicas"].(int64); ok {
-				maxReplicas = int(v)
-			}
-			return base * int64(maxReplicas)
+	out := make([]Flow, len(edges))
+	for i, e := range edges {
+		if (e.TrafficType == "all" || e.TrafficType == "read") && readTargets > 0 {
+			out[i].Read = f.Read / int64(readTargets)
+		}
+		if (e.TrafficType == "all" || e.TrafficType == "write") && writeTargets > 0 {
+			out[i].Write = f.Write / int64(writeTargets)
 		}
+		out[i].Malicious = f.Malicious / int64(len(edges))
 	}
-	return base
+	return out
 }
@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+	"time"
+)
+
+// seedMask 將種子限制在 53 bits 內，確保經過 JSON (float64) 往返後仍能精確還原
+const seedMask = (int64(1) << 53) - 1
+
+// 各類事件使用獨立的隨機序列，避免調整某一種事件的參數時連帶改變其他事件的時間點
+const (
+	streamBurst uint64 = iota + 1
+	streamDrop
+	streamAttack
+	streamAttackIntensity
+	streamFailure
+	streamSpot
+)
+
+// EventModel 是以種子驅動的隨機事件模型
+// 所有事件都以「時間窗口」為單位抽樣：同一個種子、同一個窗口永遠得到相同的結果，
+// 因此即使每個 tick 都是獨立呼叫 Evaluate，整段模擬仍然可以完整重現。
+type EventModel struct {
+	seed int64
+}
+
+// NewEventModel 建立新的隨機事件模型
+func NewEventModel(seed int64) *EventModel {
+	return &EventModel{seed: seed & seedMask}
+}
+
+// Seed 回傳此模型使用的種子
+func (m *EventModel) Seed() int64 {
+	return m.seed
+}
+
+// rng 取得指定事件種類與窗口的亂數產生器
+func (m *EventModel) rng(stream uint64, window int64, salt uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(m.seed)^(stream*0x9E3779B97F4A7C15), uint64(window)^salt))
+}
+
+// windowEvent 判斷在指定窗口中是否發生一段持續 duration 秒的事件，並回傳該窗口的亂數產生器供後續抽樣
+func (m *EventModel) windowEvent(stream uint64, elapsedSeconds, window, duration int64, probability float64, salt uint64) (bool, *rand.Rand) {
+	if elapsedSeconds < 0 || probability <= 0 {
+		return false, nil
+	}
+	idx := elapsedSeconds / window
+	r := m.rng(stream, idx, salt)
+	if r.Float64() >= probability {
+		return false, nil
+	}
+	offset := r.Int64N(window - duration + 1)
+	pos := elapsedSeconds % window
+	return pos >= offset && pos < offset+duration, r
+}
+
+// Burst 判斷當前是否處於突發流量，並回傳流量倍率
+// 每 10 秒為一個窗口，依機率發生一次持續 3 秒、3 ~ 7 倍的突發
+func (m *EventModel) Burst(elapsedSeconds int64, probability float64) (bool, float64) {
+	active, r := m.windowEvent(streamBurst, elapsedSeconds, 10, 3, probability, 0)
+	if !active {
+		return false, 1.0
+	}
+	return true, 3.0 + r.Float64()*4.0
+}
+
+// RandomDrop 判斷當前是否處於流量驟降
+// 每 15 秒為一個窗口，依機率發生一次持續 3 秒的 40% 驟降
+func (m *EventModel) RandomDrop(elapsedSeconds int64, probability float64) bool {
+	active, _ := m.windowEvent(streamDrop, elapsedSeconds, 15, 3, probability, 0)
+	return active
+}
+
+// Attack 判斷當前是否遭受 DDoS 攻擊，並回傳惡意流量 QPS
+// 開局 15 秒後，每 40 秒為一個窗口，依機率發動一次持續 5 秒的攻擊，強度每秒不同
+func (m *EventModel) Attack(elapsedSeconds int64, probability float64) (bool, int64) {
+	if elapsedSeconds <= 15 {
+		return false, 0
+	}
+	active, _ := m.windowEvent(streamAttack, elapsedSeconds, 40, 5, probability, 0)
+	if !active {
+		return false, 0
+	}
+	intensity := 3000.0 + m.rng(streamAttackIntensity, elapsedSeconds, 0).Float64()*5000.0
+	return true, int64(intensity)
+}
+
+// Failure 判斷指定組件是否在當前這一秒發生隨機故障 (如硬體損壞)
+// 每 60 秒為一個窗口，每個組件依機率在窗口內的某一秒故障
+func (m *EventModel) Failure(elapsedSeconds int64, componentID string, probability float64) bool {
+	active, _ := m.windowEvent(streamFailure, elapsedSeconds, 60, 1, probability, hashString(componentID))
+	return active
+}
+
+// SpotInterruption 判斷指定的 spot 組件當前是否被雲端回收而中斷
+// 每 60 秒為一個窗口，每個組件依機率在窗口內被回收一次，SpotInterruptionSeconds 秒後自動以新的節點恢復
+func (m *EventModel) SpotInterruption(elapsedSeconds int64, componentID string, probability float64) bool {
+	active, _ := m.windowEvent(streamSpot, elapsedSeconds, 60, SpotInterruptionSeconds, probability, hashString(componentID))
+	return active
+}
+
+// DailySeed 回傳指定日期 (UTC) 的每日挑戰種子，同一天的所有玩家都會遇到相同的事件序列
+func DailySeed(t time.Time) int64 {
+	return int64(hashString("daily:"+t.UTC().Format("2006-01-02"))) & seedMask
+}
+
+// LeaderboardSeed 回傳關卡排行榜固定使用的種子，所有玩家在同一個關卡遇到相同的事件序列，成績才能互相比較
+func LeaderboardSeed(scenarioID string) int64 {
+	return int64(hashString("leaderboard:"+scenarioID)) & seedMask
+}
+
+// randomSeed 產生一個新的隨機種子
+func randomSeed() int64 {
+	return rand.Int64() & seedMask
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// eventRecorder 收集單次評估產生的結構化事件
+// 同一個組件在同一個 tick 的同一種事件只記錄一次 (流量可能從多條路徑重複抵達同一組件)
+type eventRecorder struct {
+	tick   int64
+	comps  map[string]component.Component
+	seen   map[string]bool
+	events []evaluation.Event
+}
+
+func newEventRecorder(tick int64, comps map[string]component.Component) *eventRecorder {
+	return &eventRecorder{tick: tick, comps: comps, seen: make(map[string]bool), events: []evaluation.Event{}}
+}
+
+func (r *eventRecorder) emit(code evaluation.EventCode, severity evaluation.EventSeverity, componentID string, params map[string]interface{}) {
+	key := string(code) + "/" + componentID
+	if r.seen[key] {
+		return
+	}
+	r.seen[key] = true
+
+	// 附上組件名稱，讓客戶端不需查表即可組出訊息
+	if componentID != "" {
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		name := r.comps[componentID].Name
+		if name == "" {
+			name = componentID
+		}
+		params["component"] = name
+	}
+	r.events = append(r.events, evaluation.Event{
+		Code:        code,
+		Severity:    severity,
+		ComponentID: componentID,
+		Tick:        r.tick,
+		Params:      params,
+	})
+}
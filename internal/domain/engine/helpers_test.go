@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"math"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/scenario"
+	"testing"
+	"time"
+)
+
+// fixedClock 讓每日挑戰的日期在測試中固定
+func fixedClock() time.Time {
+	return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// newTestEngine 建立直接評估設計圖的引擎 (不需要 Repository)
+func newTestEngine() *SimpleEngine {
+	return NewSimpleEngine(nil, nil, nil, fixedClock)
+}
+
+// steadyScenario 建立固定 QPS 的關卡
+func steadyScenario(qps int64, seconds int) *scenario.Scenario {
+	return &scenario.Scenario{
+		ID:     "test",
+		Phases: []scenario.TrafficPhase{{Name: "steady", StartQPS: qps, EndQPS: qps, DurationSeconds: seconds}},
+	}
+}
+
+// newComponent 建立測試用的組件
+func newComponent(id string, t component.Type, props component.Metadata) component.Component {
+	if props == nil {
+		props = component.Metadata{}
+	}
+	return component.Component{ID: id, Name: id, Type: t, Properties: props}
+}
+
+// chainDesign 建立 src → comps[0] → comps[1] → ... 的設計圖，流量穩定 (沒有自然波動與驟降)、讀取比例為 readRatio (%)
+func chainDesign(readRatio float64, comps ...component.Component) *design.Design {
+	d := &design.Design{
+		ID:         "test-design",
+		ScenarioID: "test",
+		Properties: component.Metadata{"steady_traffic": true, "seed": float64(42)},
+		Components: []component.Component{newComponent("src", component.TrafficSource, component.Metadata{"read_ratio": readRatio})},
+	}
+	prev := "src"
+	for _, c := range comps {
+		d.Components = append(d.Components, c)
+		d.Connections = append(d.Connections, design.Connection{FromID: prev, ToID: c.ID})
+		prev = c.ID
+	}
+	return d
+}
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6*math.Max(1, math.Abs(want)) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
@@ -0,0 +1,285 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/scenario"
+)
+
+// z95 為 95% 信賴水準對應的常態分佈臨界值
+const z95 = 1.96
+
+// MaxMonteCarloRuns 是一次蒙地卡羅評估允許的最多模擬次數 (HTTP 與 Wasm 共用的上限)
+const MaxMonteCarloRuns = 1000
+
+// MonteCarloOptions 定義蒙地卡羅評估的參數
+type MonteCarloOptions struct {
+	Runs            int   // 模擬次數，預設 50
+	Seed            int64 // 基礎種子，0 代表隨機產生
+	Workers         int   // 平行執行的 goroutine 數，預設為 CPU 數
+	DurationSeconds int64 // 每次模擬的秒數，預設為關卡的總長度，最多 MaxRunLogTicks 秒
+}
+
+// MonteCarlo 讀取設計圖與關卡後執行蒙地卡羅評估
+func (e *SimpleEngine) MonteCarlo(ctx context.Context, designID string, opts MonteCarloOptions) (*evaluation.MonteCarloReport, error) {
+	d, s, err := e.load(designID)
+	if err != nil {
+		return nil, err
+	}
+	return e.MonteCarloDesign(ctx, d, s, opts)
+}
+
+// MonteCarloDesign 將同一個設計在同一個關卡中以不同種子重複模擬 N 次，
+// 每次的突發、驟降與攻擊時間點都不同，藉此評估設計的穩健度
+// 設計圖中儲存的模擬狀態 (崩潰、積壓、副本與留存率) 會先被清除，每次模擬都從全新的狀態開始
+// 每次模擬最多 MaxRunLogTicks 個 tick；ctx 被取消時 (例如 HTTP 連線中斷) 所有 worker 停止並回傳 ctx.Err()
+func (e *SimpleEngine) MonteCarloDesign(ctx context.Context, d *design.Design, s *scenario.Scenario, opts MonteCarloOptions) (*evaluation.MonteCarloReport, error) {
+	d = freshDesign(d)
+	if opts.Runs <= 0 {
+		opts.Runs = 50
+	}
+	if opts.Seed == 0 {
+		opts.Seed = randomSeed()
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.DurationSeconds <= 0 {
+		opts.DurationSeconds = ScenarioDuration(s)
+	}
+	if opts.DurationSeconds > MaxRunLogTicks {
+		opts.DurationSeconds = MaxRunLogTicks
+	}
+
+	summaries := make([]evaluation.RunSummary, opts.Runs)
+	errs := make([]error, opts.Runs)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				summaries[i], errs[i] = e.runOnce(ctx, d, s, deriveSeed(opts.Seed, i), opts.DurationSeconds)
+			}
+		}()
+	}
+dispatch:
+	for i := 0; i < opts.Runs; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scores := make([]float64, opts.Runs)
+	latencies := make([]float64, opts.Runs)
+	passed := 0
+	crashCounts := make(map[string]int)
+	for i, run := range summaries {
+		scores[i] = run.TotalScore
+		latencies[i] = run.P95LatencyMS
+		if run.Passed {
+			passed++
+		}
+		for _, id := range run.CrashedComponentIDs {
+			crashCounts[id]++
+		}
+	}
+
+	return &evaluation.MonteCarloReport{
+		DesignID:        d.ID,
+		ScenarioID:      s.ID,
+		Seed:            opts.Seed & seedMask,
+		Runs:            opts.Runs,
+		DurationSeconds: opts.DurationSeconds,
+		TotalScore:      distribution(scores),
+		P95LatencyMS:    distribution(latencies),
+		PassRate:        float64(passed) / float64(opts.Runs),
+		PassRateCI:      wilsonInterval(passed, opts.Runs),
+		CrashCounts:     crashCounts,
+		RunSummaries:    summaries,
+	}, nil
+}
+
+// runOnce 執行一整場模擬並彙整成摘要，ctx 被取消時中途停止
+func (e *SimpleEngine) runOnce(ctx context.Context, d *design.Design, s *scenario.Scenario, seed int64, duration int64) (evaluation.RunSummary, error) {
+	sim := e.NewSimulation(d, s, seed)
+	if err := sim.checkFunds(); err != nil {
+		return evaluation.RunSummary{}, err
+	}
+	stats := newRunStats(int(duration))
+	for t := int64(0); t < duration; t++ {
+		if err := ctx.Err(); err != nil {
+			return evaluation.RunSummary{}, err
+		}
+		res, err := sim.Step()
+		if err != nil {
+			return evaluation.RunSummary{}, err
+		}
+		stats.add(res, sim.design)
+	}
+	return stats.summary(seed), nil
+}
+
+// runStats 累計一場模擬每個 tick 的分數、延遲與崩潰的組件
+type runStats struct {
+	scoreSum     float64
+	costSum      float64
+	seconds      float64
+	latencies    []float64
+	crashed      map[string]bool
+	budgetFailed bool // 經濟模式下是否因持續超出預算而失敗
+	costs        *costTimeline
+}
+
+func newRunStats(ticks int) *runStats {
+	return &runStats{latencies: make([]float64, 0, ticks), crashed: make(map[string]bool), costs: newCostTimeline()}
+}
+
+// add 累計一個 tick 的結果，分數與成本依 tick 長度加權 (成本依 d 中的組件類型拆分)
+func (st *runStats) add(res *evaluation.Result, d *design.Design) {
+	st.scoreSum += res.TotalScore * res.DT
+	st.costSum += res.CostPerSec * res.DT
+	st.seconds += res.DT
+	st.latencies = append(st.latencies, res.AvgLatencyMS)
+	for _, id := range res.CrashedComponentIDs {
+		st.crashed[id] = true
+	}
+	if res.Budget != nil && res.Budget.Failed {
+		st.budgetFailed = true
+	}
+	st.costs.add(res.DT, res.CostPerSec, res.FulfilledQPS, typeCosts(d, res))
+}
+
+// summary 彙整成整場模擬的摘要：平均總分達 95 分 (且經濟模式下沒有因超出預算而失敗) 即為通過
+func (st *runStats) summary(seed int64) evaluation.RunSummary {
+	summary := evaluation.RunSummary{Seed: seed & seedMask, CrashedComponentIDs: make([]string, 0, len(st.crashed))}
+	if st.seconds > 0 {
+		summary.TotalScore = st.scoreSum / st.seconds
+		summary.CostPerSec = st.costSum / st.seconds
+	}
+	sort.Float64s(st.latencies)
+	summary.P95LatencyMS = percentile(st.latencies, 0.95)
+	summary.BudgetFailed = st.budgetFailed
+	summary.Passed = summary.TotalScore >= 95.0 && !st.budgetFailed
+	summary.Cost = st.costs.projection()
+	for id := range st.crashed {
+		summary.CrashedComponentIDs = append(summary.CrashedComponentIDs, id)
+	}
+	sort.Strings(summary.CrashedComponentIDs)
+	return summary
+}
+
+// CompareMonteCarlo 比較兩份蒙地卡羅報告，以平均分數差的信賴區間判斷差異是否顯著
+func CompareMonteCarlo(a, b *evaluation.MonteCarloReport) *evaluation.Comparison {
+	diff := a.TotalScore.Mean - b.TotalScore.Mean
+	se := math.Sqrt(a.TotalScore.StdDev*a.TotalScore.StdDev/float64(a.Runs) + b.TotalScore.StdDev*b.TotalScore.StdDev/float64(b.Runs))
+	ci := evaluation.ConfidenceInterval{Level: 0.95, Low: diff - z95*se, High: diff + z95*se}
+
+	cmp := &evaluation.Comparison{
+		DesignA:       a.DesignID,
+		DesignB:       b.DesignID,
+		ScoreDiff:     diff,
+		ScoreDiffCI:   ci,
+		PassRateDiff:  a.PassRate - b.PassRate,
+		LatencyDiffMS: a.P95LatencyMS.Mean - b.P95LatencyMS.Mean,
+		Significant:   ci.Low > 0 || ci.High < 0,
+	}
+	if cmp.Significant {
+		cmp.PreferredDesign = a.DesignID
+		if diff < 0 {
+			cmp.PreferredDesign = b.DesignID
+		}
+	}
+	return cmp
+}
+
+// deriveSeed 以 SplitMix64 從基礎種子衍生第 i 次模擬的種子
+func deriveSeed(base int64, i int) int64 {
+	z := uint64(base) + uint64(i+1)*0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return int64(z^(z>>31)) & seedMask
+}
+
+// distribution 計算樣本的分佈與平均值的 95% 信賴區間
+func distribution(values []float64) evaluation.Distribution {
+	n := len(values)
+	if n == 0 {
+		return evaluation.Distribution{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(n)
+
+	var sq float64
+	for _, v := range sorted {
+		sq += (v - mean) * (v - mean)
+	}
+	stdDev := 0.0
+	if n > 1 {
+		stdDev = math.Sqrt(sq / float64(n-1))
+	}
+	margin := z95 * stdDev / math.Sqrt(float64(n))
+
+	return evaluation.Distribution{
+		Mean:   mean,
+		StdDev: stdDev,
+		Min:    sorted[0],
+		Max:    sorted[n-1],
+		P5:     percentile(sorted, 0.05),
+		P50:    percentile(sorted, 0.50),
+		P95:    percentile(sorted, 0.95),
+		MeanCI: evaluation.ConfidenceInterval{Level: 0.95, Low: mean - margin, High: mean + margin},
+	}
+}
+
+// percentile 以線性插值計算已排序樣本的百分位數
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+// wilsonInterval 計算通過率的 Wilson 95% 信賴區間 (樣本少或比例接近 0/1 時仍然穩定)
+func wilsonInterval(successes, n int) evaluation.ConfidenceInterval {
+	if n == 0 {
+		return evaluation.ConfidenceInterval{Level: 0.95}
+	}
+	p := float64(successes) / float64(n)
+	nf := float64(n)
+	denom := 1 + z95*z95/nf
+	center := (p + z95*z95/(2*nf)) / denom
+	margin := z95 * math.Sqrt(p*(1-p)/nf+z95*z95/(4*nf*nf)) / denom
+	return evaluation.ConfidenceInterval{Level: 0.95, Low: math.Max(0, center-margin), High: math.Min(1, center+margin)}
+}
@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"math"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{"empty", nil, 0.5, 0},
+		{"single", []float64{7}, 0.95, 7},
+		{"median of odd", []float64{1, 2, 3, 4, 5}, 0.5, 3},
+		{"interpolated p95", []float64{1, 2, 3, 4, 5}, 0.95, 4.8},
+		{"min", []float64{1, 2, 3, 4, 5}, 0, 1},
+		{"max", []float64{1, 2, 3, 4, 5}, 1, 5},
+		{"interpolated quartile", []float64{10, 20}, 0.25, 12.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertClose(t, "percentile", percentile(tt.sorted, tt.p), tt.want)
+		})
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	if got := distribution(nil); got != (evaluation.Distribution{}) {
+		t.Fatalf("distribution(nil) = %+v, want zero value", got)
+	}
+
+	// 未排序的輸入：平均 5，樣本標準差 sqrt(32/7)
+	got := distribution([]float64{9, 2, 4, 5, 4, 7, 4, 5})
+	stdDev := math.Sqrt(32.0 / 7.0)
+	margin := 1.96 * stdDev / math.Sqrt(8)
+	assertClose(t, "mean", got.Mean, 5)
+	assertClose(t, "std_dev", got.StdDev, stdDev)
+	assertClose(t, "min", got.Min, 2)
+	assertClose(t, "max", got.Max, 9)
+	assertClose(t, "p5", got.P5, 2.7)
+	assertClose(t, "p50", got.P50, 4.5)
+	assertClose(t, "p95", got.P95, 8.3)
+	assertClose(t, "mean_ci.low", got.MeanCI.Low, 5-margin)
+	assertClose(t, "mean_ci.high", got.MeanCI.High, 5+margin)
+
+	// 只有一個樣本時沒有標準差，信賴區間退化為平均值
+	one := distribution([]float64{3})
+	if one.StdDev != 0 || one.MeanCI.Low != 3 || one.MeanCI.High != 3 {
+		t.Errorf("distribution([3]) = %+v", one)
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		name      string
+		successes int
+		n         int
+		low, high float64
+	}{
+		{"no samples", 0, 0, 0, 0},
+		{"half", 5, 10, 0.236593, 0.763407},
+		{"all passed", 10, 10, 0.722467, 1},
+		{"none passed", 0, 10, 0, 0.277533},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wilsonInterval(tt.successes, tt.n)
+			if got.Level != 0.95 {
+				t.Errorf("level = %v, want 0.95", got.Level)
+			}
+			if math.Abs(got.Low-tt.low) > 1e-5 || math.Abs(got.High-tt.high) > 1e-5 {
+				t.Errorf("wilsonInterval(%d, %d) = [%v, %v], want [%v, %v]", tt.successes, tt.n, got.Low, got.High, tt.low, tt.high)
+			}
+		})
+	}
+}
+
+func TestCompareMonteCarlo(t *testing.T) {
+	report := func(id string, mean, stdDev, passRate, latency float64) *evaluation.MonteCarloReport {
+		return &evaluation.MonteCarloReport{
+			DesignID:     id,
+			Runs:         25,
+			TotalScore:   evaluation.Distribution{Mean: mean, StdDev: stdDev},
+			P95LatencyMS: evaluation.Distribution{Mean: latency},
+			PassRate:     passRate,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		a, b        *evaluation.MonteCarloReport
+		significant bool
+		preferred   string
+	}{
+		// se = sqrt(25/25 + 25/25) = sqrt(2)，差 10 分的信賴區間為 10 ± 1.96·sqrt(2)
+		{"a clearly better", report("a", 90, 5, 0.8, 100), report("b", 80, 5, 0.4, 150), true, "a"},
+		{"b clearly better", report("a", 80, 5, 0.4, 150), report("b", 90, 5, 0.8, 100), true, "b"},
+		{"within noise", report("a", 81, 5, 0.5, 100), report("b", 80, 5, 0.5, 100), false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareMonteCarlo(tt.a, tt.b)
+			diff := tt.a.TotalScore.Mean - tt.b.TotalScore.Mean
+			assertClose(t, "score_diff", got.ScoreDiff, diff)
+			assertClose(t, "score_diff_ci.low", got.ScoreDiffCI.Low, diff-1.96*math.Sqrt(2))
+			assertClose(t, "score_diff_ci.high", got.ScoreDiffCI.High, diff+1.96*math.Sqrt(2))
+			assertClose(t, "pass_rate_diff", got.PassRateDiff, tt.a.PassRate-tt.b.PassRate)
+			assertClose(t, "latency_diff_ms", got.LatencyDiffMS, tt.a.P95LatencyMS.Mean-tt.b.P95LatencyMS.Mean)
+			if got.Significant != tt.significant || got.PreferredDesign != tt.preferred {
+				t.Errorf("significant = %v, preferred = %q; want %v, %q", got.Significant, got.PreferredDesign, tt.significant, tt.preferred)
+			}
+		})
+	}
+}
+
+// 儲存自一場進行中模擬的設計圖帶有崩潰、重啟時間與留存率等狀態，蒙地卡羅的每次模擬都不應該延續這些狀態
+func TestMonteCarloIgnoresStoredState(t *testing.T) {
+	e := newTestEngine()
+	s := steadyScenario(1000, 20)
+	clean := chainDesign(80,
+		newComponent("web", component.WebServer, component.Metadata{"max_qps": 5000}),
+		newComponent("db", component.Database, component.Metadata{"max_qps": 5000}),
+	)
+
+	stale := clean.Clone()
+	stale.Components[1].Properties["crashed"] = true
+	stale.Components[1].Properties["restartedAt"] = 3.0
+	stale.Properties["retention_rate"] = 0.2
+
+	opts := MonteCarloOptions{Runs: 4, Seed: 7, Workers: 2}
+	want, err := e.MonteCarloDesign(context.Background(), clean, s, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := e.MonteCarloDesign(context.Background(), stale, s, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want.PassRate != 1 {
+		t.Fatalf("clean design should pass every run, got pass rate %v", want.PassRate)
+	}
+	if got.TotalScore != want.TotalScore || got.PassRate != want.PassRate || len(got.CrashCounts) != 0 {
+		t.Errorf("stale design: score %+v pass %v crashes %v; want score %+v pass %v and no crashes",
+			got.TotalScore, got.PassRate, got.CrashCounts, want.TotalScore, want.PassRate)
+	}
+	if !stale.Components[1].Properties.Enabled("crashed") {
+		t.Error("MonteCarloDesign must not modify the caller's design")
+	}
+}
+
+// 關卡長度超過上限時，每次模擬最多執行 MaxRunLogTicks 個 tick，不會依關卡長度預先配置過大的記憶體
+func TestMonteCarloCapsRunLength(t *testing.T) {
+	e := newTestEngine()
+	d := chainDesign(80, newComponent("web", component.WebServer, component.Metadata{"max_qps": 5000}))
+
+	report, err := e.MonteCarloDesign(context.Background(), d, steadyScenario(100, 1<<40), MonteCarloOptions{Runs: 1, Seed: 7, Workers: 1, DurationSeconds: MaxRunLogTicks * 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.DurationSeconds != MaxRunLogTicks {
+		t.Errorf("duration = %d, want %d", report.DurationSeconds, MaxRunLogTicks)
+	}
+}
+
+// 請求被取消後 worker 停止模擬並回傳 context 的錯誤
+func TestMonteCarloStopsWhenCanceled(t *testing.T) {
+	e := newTestEngine()
+	d := chainDesign(80, newComponent("web", component.WebServer, component.Metadata{"max_qps": 5000}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := e.MonteCarloDesign(ctx, d, steadyScenario(100, 60), MonteCarloOptions{Runs: MaxMonteCarloRuns, Seed: 7, Workers: 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
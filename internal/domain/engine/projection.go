@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"sort"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+)
+
+// 推算長期成本使用的時間換算
+const (
+	HoursPerMonth = 730.0  // 一年 8760 小時平均分成 12 個月
+	HoursPerYear  = 8760.0 // 365 天
+)
+
+// costTimeline 累計一場模擬每個 tick 的成本與成功請求數 (依 tick 長度加權)，用於推算長期成本
+type costTimeline struct {
+	seconds   float64
+	cost      float64
+	fulfilled float64
+	typeCosts map[string]float64
+}
+
+func newCostTimeline() *costTimeline {
+	return &costTimeline{typeCosts: make(map[string]float64)}
+}
+
+// add 累計一個 tick 的每秒成本、成功取得資料的 QPS 與依組件類型拆分的每秒成本
+func (t *costTimeline) add(dt, costPerSec float64, fulfilledQPS int64, typeCosts map[string]float64) {
+	t.seconds += dt
+	t.cost += costPerSec * dt
+	t.fulfilled += float64(fulfilledQPS) * dt
+	for typ, v := range typeCosts {
+		t.typeCosts[typ] += v * dt
+	}
+}
+
+// projection 以模擬期間的平均每秒成本推算每小時、每月與每年的成本
+func (t *costTimeline) projection() *evaluation.CostProjection {
+	p := &evaluation.CostProjection{
+		DurationSeconds:   t.seconds,
+		FulfilledRequests: t.fulfilled,
+		ByComponentType:   make([]evaluation.TypeCost, 0, len(t.typeCosts)),
+	}
+	if t.seconds <= 0 {
+		return p
+	}
+	p.AvgCostPerSec = t.cost / t.seconds
+	p.Hourly, p.Monthly, p.Yearly = projectRate(p.AvgCostPerSec)
+	if t.fulfilled > 0 {
+		p.CostPerMillionRequests = t.cost / t.fulfilled * 1e6
+	}
+	for typ, v := range t.typeCosts {
+		if v == 0 {
+			continue // 沒有產生成本的類型 (如流量來源)
+		}
+		tc := evaluation.TypeCost{Type: typ, AvgCostPerSec: v / t.seconds}
+		tc.Hourly, tc.Monthly, tc.Yearly = projectRate(tc.AvgCostPerSec)
+		if t.cost > 0 {
+			tc.Share = v / t.cost
+		}
+		p.ByComponentType = append(p.ByComponentType, tc)
+	}
+	sort.Slice(p.ByComponentType, func(i, j int) bool {
+		a, b := p.ByComponentType[i], p.ByComponentType[j]
+		if a.AvgCostPerSec != b.AvgCostPerSec {
+			return a.AvgCostPerSec > b.AvgCostPerSec
+		}
+		return a.Type < b.Type
+	})
+	return p
+}
+
+// projectRate 將每秒成本換算為每小時、每月與每年的成本
+func projectRate(perSec float64) (hourly, monthly, yearly float64) {
+	hourly = perSec * 3600
+	return hourly, hourly * HoursPerMonth, hourly * HoursPerYear
+}
+
+// typeCosts 將評估結果中每個組件的成本依設計圖中的組件類型加總
+func typeCosts(d *design.Design, res *evaluation.Result) map[string]float64 {
+	out := make(map[string]float64)
+	for _, c := range d.Components {
+		if v, ok := res.ComponentCosts[c.ID]; ok {
+			out[string(c.Type)] += v
+		}
+	}
+	return out
+}
+
+// ProjectCosts 依執行紀錄中每個 tick 的成本推算長期成本與每百萬個成功請求的成本
+func ProjectCosts(log *RunLog) *evaluation.CostProjection {
+	t := newCostTimeline()
+	for _, rec := range log.Ticks {
+		t.add(rec.DT, rec.CostPerSec, rec.FulfilledQPS, rec.TypeCosts)
+	}
+	return t.projection()
+}
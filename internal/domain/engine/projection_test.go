@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+	"testing"
+)
+
+// 依手動建立的執行紀錄推算長期成本：平均每秒成本與成功請求數依 tick 長度加權
+func TestProjectCosts(t *testing.T) {
+	web, db, cache, src := string(component.WebServer), string(component.Database), string(component.Cache), string(component.TrafficSource)
+	log := &RunLog{Ticks: []evaluation.TickRecord{
+		{DT: 1, CostPerSec: 2, FulfilledQPS: 1000, TypeCosts: map[string]float64{web: 1.5, db: 0.5, src: 0}},
+		{DT: 0.5, CostPerSec: 4, FulfilledQPS: 2000, TypeCosts: map[string]float64{web: 1, db: 3}},
+		{DT: 2, CostPerSec: 2, FulfilledQPS: 0, TypeCosts: map[string]float64{web: 1, cache: 1}},
+	}}
+	p := ProjectCosts(log)
+
+	// 總成本 2×1 + 4×0.5 + 2×2 = 8，共 3.5 秒；成功請求 1000 + 1000 + 0 = 2000
+	avg := 8 / 3.5
+	assertClose(t, "duration", p.DurationSeconds, 3.5)
+	assertClose(t, "avg cost per sec", p.AvgCostPerSec, avg)
+	assertClose(t, "hourly", p.Hourly, avg*3600)
+	assertClose(t, "monthly", p.Monthly, avg*3600*730)
+	assertClose(t, "yearly", p.Yearly, avg*3600*8760)
+	assertClose(t, "fulfilled requests", p.FulfilledRequests, 2000)
+	assertClose(t, "cost per million requests", p.CostPerMillionRequests, 8.0/2000*1e6)
+
+	// web 4、cache 2、db 2 (同成本依類型名稱排序)，流量來源沒有成本不列出
+	want := []struct {
+		typ   string
+		total float64
+		share float64
+	}{{web, 4, 0.5}, {cache, 2, 0.25}, {db, 2, 0.25}}
+	if len(p.ByComponentType) != len(want) {
+		t.Fatalf("by component type = %+v, want %d types", p.ByComponentType, len(want))
+	}
+	for i, w := range want {
+		got := p.ByComponentType[i]
+		if got.Type != w.typ {
+			t.Errorf("type %d = %s, want %s", i, got.Type, w.typ)
+			continue
+		}
+		assertClose(t, w.typ+" avg cost per sec", got.AvgCostPerSec, w.total/3.5)
+		assertClose(t, w.typ+" monthly", got.Monthly, w.total/3.5*3600*HoursPerMonth)
+		assertClose(t, w.typ+" yearly", got.Yearly, w.total/3.5*3600*HoursPerYear)
+		assertClose(t, w.typ+" share", got.Share, w.share)
+	}
+}
+
+// 沒有 tick 或沒有成功請求時不推算 (不會除以 0)
+func TestProjectCostsWithoutRequests(t *testing.T) {
+	empty := ProjectCosts(&RunLog{})
+	if empty.AvgCostPerSec != 0 || empty.Yearly != 0 || len(empty.ByComponentType) != 0 {
+		t.Errorf("empty log projection = %+v, want zero", empty)
+	}
+
+	p := ProjectCosts(&RunLog{Ticks: []evaluation.TickRecord{{DT: 1, CostPerSec: 3}}})
+	assertClose(t, "avg cost per sec", p.AvgCostPerSec, 3)
+	if p.CostPerMillionRequests != 0 {
+		t.Errorf("cost per million requests = %v, want 0 without fulfilled requests", p.CostPerMillionRequests)
+	}
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"errors"
+	"system-design-game/internal/i18n"
+)
+
+// ReasonError 為驗證、重播與經濟模式的錯誤附上原因的訊息 ID，handler 依語系產生原因文字回傳給玩家
+// Err 是錯誤類別 (如 ErrInvalidVerification)，可用 errors.Is 判斷
+type ReasonError struct {
+	Err       error
+	MessageID string
+	Params    map[string]interface{}
+}
+
+func (e *ReasonError) Error() string {
+	return e.Err.Error() + ": " + e.Message(i18n.DefaultLocale)
+}
+
+func (e *ReasonError) Unwrap() error {
+	return e.Err
+}
+
+// Message 依語系產生原因文字
+func (e *ReasonError) Message(l i18n.Locale) string {
+	return i18n.T(l, e.MessageID, e.Params)
+}
+
+// reason 建立指定類別與原因的錯誤
+func reason(err error, messageID string, params map[string]interface{}) error {
+	return &ReasonError{Err: err, MessageID: messageID, Params: params}
+}
+
+// reclassify 將帶有原因的錯誤改歸到另一個類別 (如起始資金不足的執行紀錄屬於 ErrInvalidRunLog)，保留原本的原因
+func reclassify(class error, err error) error {
+	var re *ReasonError
+	if errors.As(err, &re) {
+		return reason(class, re.MessageID, re.Params)
+	}
+	return reason(class, "reason.unknown", nil)
+}
+
+// invalidTick 是 tick 長度超出範圍的錯誤
+func invalidTick(class error, dt float64) error {
+	return reason(class, "reason.invalid_tick", map[string]interface{}{"dt": dt, "min": MinTickSeconds, "max": MaxTickSeconds})
+}
+
+// actionFailed 是某個 tick 的玩家操作無法套用的錯誤
+func actionFailed(class error, tick int, a RunAction) error {
+	return reason(class, "reason.action_failed", map[string]interface{}{"tick": tick, "action": string(a.Type)})
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/scenario"
+)
+
+// 使用者留存的參數 (流失與成長速度可用設計圖全域屬性 churn_rate、growth_rate、max_retention 調整)
+const (
+	ExperienceWindowSeconds = 10.0  // 以最近幾秒的 p95 延遲與平均錯誤率判斷使用者體驗
+	MinRetention            = 0.1   // 留存率下限 (總會留下一些忠實使用者)
+	DefaultChurnRate        = 0.005 // 體驗最差時每秒流失 0.5%
+	DefaultGrowthRate       = 0.002 // 體驗良好時每秒成長 0.2%
+	DefaultMaxRetention     = 1.5   // 自然成長最多到關卡流量的 1.5 倍
+	defaultLatencySLOMS     = 500.0 // 關卡沒有延遲目標時可接受的延遲
+	defaultErrorSLO         = 0.01  // 關卡沒有可用性目標時可接受的錯誤率
+)
+
+// experience 將這個 tick 的延遲與錯誤率加入最近的紀錄，依關卡目標評估使用者體驗並計算下一個 tick 的留存率：
+//   - 最近的 p95 延遲或平均錯誤率超出目標時，使用者依超出的程度 (severity，最多 1) 以 churn_rate 流失
+//   - 兩者都在目標內時，口碑帶來自然成長 (growth_rate)，直到 max_retention
+//   - 組件刻意過濾的請求 (WAF 誤擋、外部 API 依 SLA 丟包) 是設計上的取捨，不算在錯誤率中，
+//     否則使用這些組件的健康設計會永遠超出目標而持續流失使用者
+//
+// 最近的紀錄會寫回設計圖屬性 (recent_latencies、recent_error_rates)，讓下一個 tick 延續
+func experience(props component.Metadata, s *scenario.Scenario, res *evaluation.Result) *evaluation.Experience {
+	window := int(math.Ceil(ExperienceWindowSeconds / res.DT))
+	latencies := recent(props, "recent_latencies", res.AvgLatencyMS, window)
+	errorRates := recent(props, "recent_error_rates", unexpectedErrorRate(res), window)
+
+	exp := &evaluation.Experience{
+		P95LatencyMS:     percentile(sortedValues(latencies), 0.95),
+		ErrorRate:        mean(errorRates),
+		LatencySLOMS:     defaultLatencySLOMS,
+		ErrorSLO:         defaultErrorSLO,
+		RecentLatencies:  values(latencies),
+		RecentErrorRates: values(errorRates),
+	}
+	if s.Goal.MaxLatencyMS > 0 {
+		exp.LatencySLOMS = float64(s.Goal.MaxLatencyMS)
+	}
+	if s.Goal.Availability > 0 && s.Goal.Availability < 100 {
+		exp.ErrorSLO = 1 - s.Goal.Availability/100
+	}
+	// 超出目標的比例：延遲為目標的 2 倍、或錯誤率超出允許值 1 倍以上時為最差的體驗
+	exp.Severity = math.Min(1, math.Max(0, math.Max(exp.P95LatencyMS/exp.LatencySLOMS-1, exp.ErrorRate/exp.ErrorSLO-1)))
+
+	retention := res.RetentionRate
+	maxRetention := props.FloatOr("max_retention", DefaultMaxRetention)
+	if exp.Severity > 0 {
+		retention -= props.FloatOr("churn_rate", DefaultChurnRate) * exp.Severity * res.DT
+	} else if retention < maxRetention {
+		retention = math.Min(maxRetention, retention+props.FloatOr("growth_rate", DefaultGrowthRate)*res.DT)
+	}
+	exp.NextRetentionRate = math.Max(MinRetention, retention)
+	return exp
+}
+
+// unexpectedErrorRate 回傳扣除組件刻意過濾的請求後的錯誤率：成功取得資料的比例以「沒有被過濾的請求」為分母
+func unexpectedErrorRate(res *evaluation.Result) float64 {
+	expected := res.TotalQPS - res.FilteredQPS
+	if res.FilteredQPS <= 0 || expected <= 0 {
+		return res.ErrorRate
+	}
+	return math.Max(0, 1-float64(res.FulfilledQPS)/float64(expected))
+}
+
+// recent 將數值加入屬性中的最近紀錄 (只保留最後 window 筆) 並寫回屬性
+func recent(props component.Metadata, key string, v float64, window int) []interface{} {
+	list, _ := props.List(key)
+	list = append(append([]interface{}(nil), list...), v)
+	if len(list) > window {
+		list = list[len(list)-window:]
+	}
+	props[key] = list
+	return list
+}
+
+// values 依原本的順序取出清單中的數值
+func values(list []interface{}) []float64 {
+	out := make([]float64, 0, len(list))
+	for _, v := range list {
+		if f, ok := v.(float64); ok {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func sortedValues(list []interface{}) []float64 {
+	out := values(list)
+	sort.Float64s(out)
+	return out
+}
+
+func mean(list []interface{}) float64 {
+	values := sortedValues(list)
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
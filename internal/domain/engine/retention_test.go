@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/evaluation"
+	"testing"
+)
+
+// 組件刻意過濾的請求 (WAF 誤擋、外部 API 依 SLA 丟包) 不算在錯誤率中，健康的設計應自然成長而不是持續流失
+func TestRetentionIgnoresIntentionalFiltering(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter component.Component
+	}{
+		{"waf", newComponent("filter", component.WAF, component.Metadata{"max_qps": 100000})},
+		{"external api", newComponent("filter", component.ExternalAPI, component.Metadata{"max_qps": 100000, "sla": 95.0})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := chainDesign(80, tt.filter, newComponent("db", component.Database, component.Metadata{"max_qps": 100000}))
+			sim := newTestEngine().NewSimulation(d, steadyScenario(1000, 60), 7)
+			var res *evaluation.Result
+			for i := 0; i < 20; i++ {
+				var err error
+				if res, err = sim.Step(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if res.FilteredQPS == 0 || res.ErrorRate <= res.Experience.ErrorSLO {
+				t.Fatalf("filtered %d qps with error rate %v; the test needs filtering above the %v SLO", res.FilteredQPS, res.ErrorRate, res.Experience.ErrorSLO)
+			}
+			if res.Experience.ErrorRate != 0 || res.Experience.Severity != 0 {
+				t.Errorf("experience error rate = %v, severity = %v; want 0", res.Experience.ErrorRate, res.Experience.Severity)
+			}
+			if res.Experience.NextRetentionRate <= 1 {
+				t.Errorf("next retention = %v, want growth above 1", res.Experience.NextRetentionRate)
+			}
+		})
+	}
+}
+
+// 無狀態的 EvaluateDesign 也回傳使用者體驗，客戶端把留存率與最近的紀錄帶回設計圖後，之後的流量隨之改變
+func TestEvaluateDesignRetentionFeedback(t *testing.T) {
+	e := newTestEngine()
+	s := steadyScenario(1000, 60)
+	d := chainDesign(80, newComponent("db", component.Database, component.Metadata{"max_qps": 600}))
+
+	var first, last *evaluation.Result
+	for elapsed := int64(0); elapsed < 10; elapsed++ {
+		res, err := e.EvaluateDesign(d, s, elapsed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Experience == nil {
+			t.Fatal("EvaluateDesign should report the user experience")
+		}
+		if first == nil {
+			if _, ok := d.Properties["recent_latencies"]; ok {
+				t.Fatal("EvaluateDesign must not modify the caller's design")
+			}
+			first = res
+		}
+		last = res
+
+		// 與前端相同：將下一個 tick 的留存率與最近的紀錄寫回設計圖
+		d.Properties["retention_rate"] = res.Experience.NextRetentionRate
+		d.Properties["recent_latencies"] = floatList(res.Experience.RecentLatencies)
+		d.Properties["recent_error_rates"] = floatList(res.Experience.RecentErrorRates)
+	}
+
+	if len(last.Experience.RecentErrorRates) != 10 {
+		t.Errorf("recent error rates = %v, want the last 10 ticks", last.Experience.RecentErrorRates)
+	}
+	if last.RetentionRate >= first.RetentionRate || last.TotalQPS >= first.TotalQPS {
+		t.Errorf("retention %v -> %v, qps %d -> %d; overloaded design should lose users and traffic",
+			first.RetentionRate, last.RetentionRate, first.TotalQPS, last.TotalQPS)
+	}
+}
+
+func floatList(values []float64) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
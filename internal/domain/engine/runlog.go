@@ -0,0 +1,218 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/scenario"
+	"system-design-game/internal/domain/world"
+)
+
+// MaxRunLogTicks 是一份執行紀錄最多記錄的 tick 數 (1 秒一個 tick 約 6 小時)，超過後停止記錄並標記 Truncated
+const MaxRunLogTicks = 21600
+
+// 玩家操作類型 (與模擬場次的控制指令相同)
+const (
+	ActionRestart      = "restart"       // 重啟已崩潰的組件
+	ActionSetProperty  = "set_property"  // 修改組件 (或設計圖全域) 屬性
+	ActionConnect      = "connect"       // 新增連線
+	ActionAddComponent = "add_component" // 新增 (購買) 組件
+	ActionTickSize     = "dt"            // 修改每個 tick 的秒數
+)
+
+// ErrInvalidRunLog 表示執行紀錄缺少必要資料，或其中的操作無法套用
+var ErrInvalidRunLog = errors.New("invalid run log")
+
+// RunAction 是執行紀錄中的一筆玩家操作，在第 Tick 個 tick (從 0 起算) 評估之前套用
+type RunAction struct {
+	Tick        int                  `json:"tick"`
+	Type        string               `json:"type"`
+	ComponentID string               `json:"component_id,omitempty"`
+	Property    string               `json:"property,omitempty"`
+	Value       interface{}          `json:"value,omitempty"` // set_property 的新值，未設定代表移除
+	Connection  *design.Connection   `json:"connection,omitempty"`
+	Component   *component.Component `json:"component,omitempty"`
+	DT          float64              `json:"dt,omitempty"`
+}
+
+// RunLog 是一場模擬的執行紀錄：開始時的設計圖 (含延續狀態與種子)、關卡、玩家操作與每個 tick 的摘要
+// 以 Replay 依序套用操作並重新推進，即可重現完全相同的評估結果
+type RunLog struct {
+	DesignID    string                  `json:"design_id"`
+	ScenarioID  string                  `json:"scenario_id"`
+	Seed        int64                   `json:"seed"`
+	Start       float64                 `json:"start"` // 開始記錄時的模擬秒數
+	TickSeconds float64                 `json:"dt"`    // 開始記錄時每個 tick 的秒數
+	Design      *design.Design          `json:"design"`
+	Scenario    *scenario.Scenario      `json:"scenario"`
+	Actions     []RunAction             `json:"actions"`
+	GameState   *world.GameState        `json:"game_state,omitempty"` // 無盡模式開始記錄時玩家的遊戲狀態 (金幣、使用者數與運行秒數)
+	Ticks       []evaluation.TickRecord `json:"ticks"`
+	Truncated   bool                    `json:"truncated,omitempty"`
+}
+
+// Snapshot 複製一份目前的執行紀錄 (已記錄的項目不會再變動，只複製清單本身)
+func (l *RunLog) Snapshot() *RunLog {
+	out := *l
+	out.Actions = append([]RunAction(nil), l.Actions...)
+	out.Ticks = append([]evaluation.TickRecord(nil), l.Ticks...)
+	return &out
+}
+
+// Record 從目前的狀態開始記錄這場模擬，回傳的紀錄會隨之後的 Step 與玩家操作持續更新
+func (sim *Simulation) Record() *RunLog {
+	seed, _ := sim.design.Properties.Int("seed")
+	sim.log = &RunLog{
+		DesignID:    sim.design.ID,
+		ScenarioID:  sim.scenario.ID,
+		Seed:        seed,
+		Start:       sim.elapsed,
+		TickSeconds: sim.dt,
+		Design:      sim.design.Clone(),
+		Scenario:    sim.scenario,
+		Actions:     []RunAction{},
+		Ticks:       []evaluation.TickRecord{},
+	}
+	if sim.game != nil {
+		start := *sim.game
+		sim.log.GameState = &start
+	}
+	return sim.log
+}
+
+// recordAction 記錄一筆已成功套用的玩家操作
+func (sim *Simulation) recordAction(a RunAction) {
+	if sim.log == nil || sim.log.Truncated {
+		return
+	}
+	a.Tick = len(sim.log.Ticks)
+	sim.log.Actions = append(sim.log.Actions, a)
+}
+
+// recordTick 記錄一個 tick 的摘要
+func (sim *Simulation) recordTick(res *evaluation.Result) {
+	if sim.log == nil || sim.log.Truncated {
+		return
+	}
+	if len(sim.log.Ticks) >= MaxRunLogTicks {
+		sim.log.Truncated = true
+		return
+	}
+	rec := tickRecord(res)
+	rec.TypeCosts = typeCosts(sim.design, res)
+	sim.log.Ticks = append(sim.log.Ticks, rec)
+}
+
+func tickRecord(res *evaluation.Result) evaluation.TickRecord {
+	rec := evaluation.TickRecord{
+		Elapsed:             res.Elapsed,
+		DT:                  res.DT,
+		TotalScore:          res.TotalScore,
+		TotalQPS:            res.TotalQPS,
+		FulfilledQPS:        res.FulfilledQPS,
+		AvgLatencyMS:        res.AvgLatencyMS,
+		ErrorRate:           res.ErrorRate,
+		RetentionRate:       res.RetentionRate,
+		CostPerSec:          res.CostPerSec,
+		CrashedComponentIDs: res.CrashedComponentIDs,
+		Digest:              resultDigest(res),
+	}
+	for _, ev := range res.Events {
+		rec.Events = append(rec.Events, ev.Code)
+	}
+	return rec
+}
+
+// resultDigest 以完整評估結果的 JSON 計算雜湊 (結果已依預設語系產生文字，因此與觀看者的語系無關)
+func resultDigest(res *evaluation.Result) string {
+	data, _ := json.Marshal(res)
+	h := fnv.New64a()
+	h.Write(data)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// apply 在模擬上套用一筆紀錄中的玩家操作
+func (sim *Simulation) apply(a RunAction) error {
+	switch a.Type {
+	case ActionRestart:
+		return sim.RestartComponent(a.ComponentID)
+	case ActionSetProperty:
+		return sim.SetProperty(a.ComponentID, a.Property, a.Value)
+	case ActionConnect:
+		if a.Connection == nil {
+			return fmt.Errorf("%w: connect 缺少 connection", ErrInvalidRunLog)
+		}
+		return sim.AddConnection(*a.Connection)
+	case ActionAddComponent:
+		if a.Component == nil {
+			return fmt.Errorf("%w: add_component 缺少 component", ErrInvalidRunLog)
+		}
+		return sim.AddComponent(*a.Component)
+	case ActionTickSize:
+		return sim.SetTickSeconds(a.DT)
+	}
+	return fmt.Errorf("%w: 未知的操作 %s", ErrInvalidRunLog, a.Type)
+}
+
+// Replay 依執行紀錄重新推進模擬，並與紀錄中每個 tick 的雜湊比對；超過 MaxRunLogTicks 個 tick 的紀錄在模擬前即拒絕
+func (e *SimpleEngine) Replay(log *RunLog) (*evaluation.ReplayReport, error) {
+	if log == nil || log.Design == nil || log.Scenario == nil {
+		return nil, reason(ErrInvalidRunLog, "reason.missing_design_or_scenario", nil)
+	}
+	if len(log.Ticks) > MaxRunLogTicks {
+		return nil, reason(ErrInvalidRunLog, "reason.too_many_ticks", map[string]interface{}{"max": MaxRunLogTicks})
+	}
+	sim := e.NewSimulation(log.Design, log.Scenario, log.Seed)
+	if err := sim.checkFunds(); err != nil {
+		return nil, reclassify(ErrInvalidRunLog, err)
+	}
+	if log.GameState != nil {
+		game := *log.GameState
+		sim.UseGameState(&game)
+	}
+	sim.elapsed = log.Start
+	if log.TickSeconds != 0 {
+		if err := sim.SetTickSeconds(log.TickSeconds); err != nil {
+			return nil, invalidTick(ErrInvalidRunLog, log.TickSeconds)
+		}
+	}
+
+	report := &evaluation.ReplayReport{
+		DesignID:   log.Design.ID,
+		ScenarioID: log.Scenario.ID,
+		Seed:       log.Seed,
+		Ticks:      len(log.Ticks),
+		Matched:    true,
+		DivergedAt: -1,
+		Results:    make([]*evaluation.Result, 0, len(log.Ticks)),
+	}
+	// 依 tick 排序 (同一個 tick 內維持原本的順序)
+	actions := append([]RunAction(nil), log.Actions...)
+	costs := newCostTimeline()
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].Tick < actions[j].Tick })
+	for i, rec := range log.Ticks {
+		for len(actions) > 0 && actions[0].Tick <= i {
+			if err := sim.apply(actions[0]); err != nil {
+				return nil, actionFailed(ErrInvalidRunLog, i, actions[0])
+			}
+			actions = actions[1:]
+		}
+		res, err := sim.Step()
+		if err != nil {
+			return nil, err
+		}
+		if report.Matched && resultDigest(res) != rec.Digest {
+			report.Matched = false
+			report.DivergedAt = i
+		}
+		report.Results = append(report.Results, res)
+		costs.add(res.DT, res.CostPerSec, res.FulfilledQPS, typeCosts(sim.design, res))
+	}
+	report.Cost = costs.projection()
+	return report, nil
+}
@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+	"testing"
+)
+
+// recordRun 從頭記錄一場含玩家操作的模擬，並像 POST /replay 一樣經過一次 JSON 編碼
+func recordRun(t *testing.T) *RunLog {
+	t.Helper()
+	d := chainDesign(80,
+		newComponent("web", component.WebServer, component.Metadata{"max_qps": 1000}),
+		newComponent("db", component.Database, component.Metadata{"max_qps": 5000}),
+	)
+	sim := newTestEngine().NewSimulation(d, steadyScenario(1200, 40), 7)
+	log := sim.Record()
+	for tick := 0; tick < 30; tick++ {
+		switch tick {
+		case 5:
+			if err := sim.SetProperty("web", "auto_scaling", true); err != nil {
+				t.Fatal(err)
+			}
+		case 10:
+			if err := sim.AddComponent(newComponent("cache", component.Cache, component.Metadata{"max_qps": 5000})); err != nil {
+				t.Fatal(err)
+			}
+			if err := sim.AddConnection(design.Connection{FromID: "web", ToID: "cache"}); err != nil {
+				t.Fatal(err)
+			}
+		case 20:
+			if err := sim.SetTickSeconds(0.5); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := sim.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out RunLog
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return &out
+}
+
+// 重播一份記錄下來的執行紀錄，每個 tick 的雜湊都應與紀錄相同
+func TestReplayMatchesRecordedDigests(t *testing.T) {
+	log := recordRun(t)
+	if len(log.Ticks) != 30 || len(log.Actions) != 4 {
+		t.Fatalf("recorded %d ticks and %d actions, want 30 and 4", len(log.Ticks), len(log.Actions))
+	}
+
+	report, err := newTestEngine().Replay(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Matched || report.DivergedAt != -1 {
+		t.Fatalf("replay diverged at tick %d", report.DivergedAt)
+	}
+	for i, res := range report.Results {
+		if got := resultDigest(res); got != log.Ticks[i].Digest {
+			t.Errorf("tick %d: digest %s, want %s", i, got, log.Ticks[i].Digest)
+		}
+	}
+	assertClose(t, "replayed cost", report.Cost.AvgCostPerSec, ProjectCosts(log).AvgCostPerSec)
+}
+
+// 竄改過的紀錄在第一個不一致的 tick 被標出
+func TestReplayDetectsTampering(t *testing.T) {
+	log := recordRun(t)
+	log.Ticks[12].Digest = "0000000000000000"
+
+	report, err := newTestEngine().Replay(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Matched || report.DivergedAt != 12 {
+		t.Errorf("matched = %v, diverged at %d; want false at 12", report.Matched, report.DivergedAt)
+	}
+}
+
+// 超過 MaxRunLogTicks 的紀錄在模擬前就被拒絕
+func TestReplayRejectsOversizedLog(t *testing.T) {
+	log := &RunLog{
+		Design:   chainDesign(80, newComponent("db", component.Database, nil)),
+		Scenario: steadyScenario(100, 10),
+		Ticks:    make([]evaluation.TickRecord, MaxRunLogTicks+1),
+	}
+	if _, err := newTestEngine().Replay(log); !errors.Is(err, ErrInvalidRunLog) {
+		t.Errorf("err = %v, want ErrInvalidRunLog", err)
+	}
+}
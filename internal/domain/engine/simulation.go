@@ -0,0 +1,278 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+	"system-design-game/internal/domain/scenario"
+	"system-design-game/internal/domain/world"
+	"system-design-game/internal/i18n"
+)
+
+// Simulation 在伺服器端逐 tick 推進一場完整的模擬 (預設每個 tick 1 秒)
+// 每個 tick 之間會像前端一樣延續組件狀態：崩潰、MQ 積壓、ASG 副本啟動時間與使用者留存率
+type Simulation struct {
+	engine   *SimpleEngine
+	design   *design.Design
+	scenario *scenario.Scenario
+	elapsed  float64
+	dt       float64
+	log      *RunLog          // 呼叫 Record 後才會記錄
+	ledger   *ledger          // 經濟模式的資金與預算 (未啟用時為 nil)
+	game     *world.GameState // 無盡模式玩家的遊戲狀態 (UseGameState 之後才有)
+}
+
+// tick 長度的允許範圍 (秒)
+const (
+	MinTickSeconds = 0.1
+	MaxTickSeconds = 60.0
+)
+
+// ErrInvalidTick 表示 tick 長度超出允許範圍
+var ErrInvalidTick = errors.New("invalid tick size")
+
+// NewSimulation 以指定種子建立一場模擬，設計圖會被複製一份，不影響原始資料
+func (e *SimpleEngine) NewSimulation(d *design.Design, s *scenario.Scenario, seed int64) *Simulation {
+	sd := d.Clone()
+	if sd.Properties == nil {
+		sd.Properties = component.Metadata{}
+	}
+	sd.Properties["seed"] = float64(seed & seedMask)
+	if _, ok := sd.Properties.Float("retention_rate"); !ok {
+		sd.Properties["retention_rate"] = 1.0
+	}
+	return &Simulation{engine: e, design: sd, scenario: s, dt: 1, ledger: newLedger(sd, s)}
+}
+
+// Simulate 以已儲存的設計圖建立一場模擬 (種子依設計的 seed / daily_challenge 決定)，
+// 經濟模式下起始資金不足以購買所有組件時回傳 ErrInsufficientFunds
+func (e *SimpleEngine) Simulate(designID string) (*Simulation, error) {
+	d, s, err := e.load(designID)
+	if err != nil {
+		return nil, err
+	}
+	sim := e.NewSimulation(d, s, e.resolveSeed(d))
+	if err := sim.checkFunds(); err != nil {
+		return nil, err
+	}
+	return sim, nil
+}
+
+// Scenario 回傳模擬使用的關卡
+func (sim *Simulation) Scenario() *scenario.Scenario {
+	return sim.scenario
+}
+
+// Elapsed 回傳模擬目前推進到的秒數
+func (sim *Simulation) Elapsed() float64 {
+	return sim.elapsed
+}
+
+// TickSeconds 回傳每個 tick 的長度 (秒)
+func (sim *Simulation) TickSeconds() float64 {
+	return sim.dt
+}
+
+// SetTickSeconds 設定之後每個 tick 的長度，必須介於 MinTickSeconds 與 MaxTickSeconds 之間
+func (sim *Simulation) SetTickSeconds(dt float64) error {
+	if dt < MinTickSeconds || dt > MaxTickSeconds {
+		return fmt.Errorf("%w: %g", ErrInvalidTick, dt)
+	}
+	sim.dt = dt
+	sim.recordAction(RunAction{Type: ActionTickSize, DT: dt})
+	return nil
+}
+
+// Design 回傳模擬中的設計圖 (含延續下來的狀態)
+func (sim *Simulation) Design() *design.Design {
+	return sim.design
+}
+
+// Step 評估當前這一秒，並將結果延續到下一個 tick
+func (sim *Simulation) Step() (*evaluation.Result, error) {
+	res, err := sim.engine.EvaluateTick(sim.design, sim.scenario, sim.elapsed, sim.dt)
+	if err != nil {
+		return nil, err
+	}
+	if sim.game != nil {
+		res.RevenuePerSec = sim.game.UpdateMetrics(res.AvgLatencyMS, res.ErrorRate, res.FulfilledQPS, res.DT)
+		sim.game.DeductCost(res.CostPerSec, res.DT)
+	}
+	if sim.ledger != nil {
+		sim.ledger.settle(res)
+		res.Localize(i18n.DefaultLocale)
+	}
+	res.Experience = experience(sim.design.Properties, sim.scenario, res)
+	sim.recordTick(res)
+	sim.carryOver(res)
+	sim.elapsed += sim.dt
+	return res, nil
+}
+
+// ErrComponentNotFound 表示控制指令指定的組件不在設計圖中
+var ErrComponentNotFound = errors.New("component not found")
+
+// ErrComponentNotCrashed 表示要重啟的組件並未崩潰
+var ErrComponentNotCrashed = errors.New("component not crashed")
+
+// RestartComponent 手動重啟已崩潰的組件，規則與前端的重啟按鈕相同：
+// 清除崩潰狀態並記錄重啟時間，重啟後 5 秒內不會再因過載崩潰；組件未崩潰時回傳 ErrComponentNotCrashed
+func (sim *Simulation) RestartComponent(id string) error {
+	comp := sim.component(id)
+	if comp == nil {
+		return fmt.Errorf("%w: %s", ErrComponentNotFound, id)
+	}
+	if !comp.Properties.Enabled("crashed") {
+		return fmt.Errorf("%w: %s", ErrComponentNotCrashed, id)
+	}
+	comp.Properties["crashed"] = false
+	comp.Properties["restartedAt"] = sim.elapsed
+	sim.recordAction(RunAction{Type: ActionRestart, ComponentID: id})
+	return nil
+}
+
+// SetProperty 在模擬進行中修改組件 (componentID 為空時為設計圖全域) 的屬性，
+// 值必須符合屬性定義，否則回傳 *component.ValidationError；value 為 nil 代表移除設定
+func (sim *Simulation) SetProperty(componentID, key string, value interface{}) error {
+	props := &sim.design.Properties
+	validate := func() []component.PropertyError {
+		return design.PropertySchema.Validate(component.Metadata{key: value})
+	}
+	if componentID != "" {
+		comp := sim.component(componentID)
+		if comp == nil {
+			return fmt.Errorf("%w: %s", ErrComponentNotFound, componentID)
+		}
+		props = &comp.Properties
+		validate = func() []component.PropertyError {
+			probe := *comp
+			probe.Properties = component.Metadata{key: value}
+			return probe.ValidateProperties()
+		}
+	}
+
+	if value == nil {
+		delete(*props, key)
+		sim.recordAction(RunAction{Type: ActionSetProperty, ComponentID: componentID, Property: key})
+		return nil
+	}
+	if errs := validate(); len(errs) > 0 {
+		ve := &component.ValidationError{Errors: errs}
+		ve.Localize(i18n.DefaultLocale)
+		return ve
+	}
+	if *props == nil {
+		*props = component.Metadata{}
+	}
+	(*props)[key] = value
+	sim.recordAction(RunAction{Type: ActionSetProperty, ComponentID: componentID, Property: key, Value: value})
+	return nil
+}
+
+// ErrInvalidConnection 表示新增的連線不合法 (連到自己、重複或流量類型錯誤)
+var ErrInvalidConnection = errors.New("invalid connection")
+
+// AddConnection 在模擬進行中新增一條連線，兩端的組件必須存在且不可重複連線
+func (sim *Simulation) AddConnection(conn design.Connection) error {
+	for _, id := range []string{conn.FromID, conn.ToID} {
+		if sim.component(id) == nil {
+			return fmt.Errorf("%w: %s", ErrComponentNotFound, id)
+		}
+	}
+	if conn.FromID == conn.ToID {
+		return fmt.Errorf("%w: %s 不能連到自己", ErrInvalidConnection, conn.FromID)
+	}
+	switch conn.TrafficType {
+	case "", "all", "read", "write":
+	default:
+		return fmt.Errorf("%w: traffic_type 必須是 all、read 或 write", ErrInvalidConnection)
+	}
+	for _, c := range sim.design.Connections {
+		if c.FromID == conn.FromID && c.ToID == conn.ToID {
+			return fmt.Errorf("%w: %s -> %s 已存在", ErrInvalidConnection, conn.FromID, conn.ToID)
+		}
+	}
+	sim.design.Connections = append(sim.design.Connections, conn)
+	sim.recordAction(RunAction{Type: ActionConnect, Connection: &conn})
+	return nil
+}
+
+func (sim *Simulation) component(id string) *component.Component {
+	for i := range sim.design.Components {
+		if sim.design.Components[i].ID == id {
+			return &sim.design.Components[i]
+		}
+	}
+	return nil
+}
+
+// carryOver 將本次評估的結果寫回組件屬性 (組件狀態的規則與前端 handleEvaluate 相同) 並更新留存率
+func (sim *Simulation) carryOver(res *evaluation.Result) {
+	crashed := make(map[string]bool, len(res.CrashedComponentIDs))
+	for _, id := range res.CrashedComponentIDs {
+		crashed[id] = true
+	}
+
+	for i := range sim.design.Components {
+		comp := &sim.design.Components[i]
+		if comp.Properties == nil {
+			comp.Properties = component.Metadata{}
+		}
+		if crashed[comp.ID] {
+			comp.Properties["crashed"] = true
+		}
+
+		switch comp.Type {
+		case component.MessageQueue:
+			comp.Properties["backlog"] = float64(res.ComponentBacklogs[comp.ID])
+		case component.AutoScalingGroup:
+			if comp.Properties.Enabled("auto_scaling") {
+				comp.Properties["replica_start_times"] = nextReplicaStartTimes(*comp, res.ComponentLoads[comp.ID], res.Elapsed)
+			}
+		}
+	}
+
+	// 使用者留存率依最近的使用者體驗流失或成長 (見 experience)，下一個 tick 的流量隨之改變
+	sim.design.Properties["retention_rate"] = res.Experience.NextRetentionRate
+}
+
+// nextReplicaStartTimes 依負載計算 ASG 的目標副本數，並記錄新副本的啟動時間 (扣除第 1 台基礎機器)
+func nextReplicaStartTimes(comp component.Component, load int64, now float64) []interface{} {
+	threshold := comp.Properties.FloatOr("scale_up_threshold", 70.0) / 100.0
+	baseCap := BehaviorFor(comp.Type).MaxQPS(comp)
+	if baseCap == 0 {
+		baseCap = 1000
+	}
+	maxReplicas := int(comp.Properties.IntOr("max_replicas", 5))
+
+	target := int(math.Ceil(float64(load) / (float64(baseCap) * threshold)))
+	if target < 1 {
+		target = 1
+	}
+	if target > maxReplicas {
+		target = maxReplicas
+	}
+
+	startTimes, _ := comp.Properties.List("replica_start_times")
+	if target > 1 && len(startTimes) < target-1 {
+		return append(append([]interface{}(nil), startTimes...), now)
+	} else if target < 1+len(startTimes) {
+		return append([]interface{}(nil), startTimes[:target-1]...)
+	}
+	return startTimes
+}
+
+// ScenarioDuration 回傳關卡的總秒數 (所有流量階段的總和，若未定義則使用目標持續時間)
+func ScenarioDuration(s *scenario.Scenario) int64 {
+	var total int64
+	for _, phase := range s.Phases {
+		total += int64(phase.DurationSeconds)
+	}
+	if total == 0 {
+		total = int64(s.Goal.Duration)
+	}
+	return total
+}
@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"errors"
+	"system-design-game/internal/domain/component"
+	"testing"
+)
+
+// 只能重啟已崩潰的組件，否則反覆重啟就能一直停留在免於崩潰的保護期
+func TestRestartRequiresCrash(t *testing.T) {
+	d := chainDesign(80,
+		newComponent("web", component.WebServer, component.Metadata{"max_qps": 1000}),
+		newComponent("db", component.Database, component.Metadata{"max_qps": 100000}),
+	)
+	sim := newTestEngine().NewSimulation(d, steadyScenario(2000, 60), 7)
+
+	if err := sim.RestartComponent("web"); !errors.Is(err, ErrComponentNotCrashed) {
+		t.Fatalf("restart before crash: err = %v, want ErrComponentNotCrashed", err)
+	}
+	if err := sim.RestartComponent("missing"); !errors.Is(err, ErrComponentNotFound) {
+		t.Fatalf("restart unknown component: err = %v, want ErrComponentNotFound", err)
+	}
+	if _, err := sim.applyStrict(RunAction{Type: ActionRestart, ComponentID: "web"}); !errors.Is(err, ErrComponentNotCrashed) {
+		t.Fatalf("strict restart before crash: err = %v, want ErrComponentNotCrashed", err)
+	}
+
+	if _, err := sim.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if !sim.component("web").Properties.Enabled("crashed") {
+		t.Fatal("web should crash at twice its capacity")
+	}
+	if err := sim.RestartComponent("web"); err != nil {
+		t.Fatalf("restart crashed component: %v", err)
+	}
+	if got, _ := sim.component("web").Properties.Float("restartedAt"); got != 1 {
+		t.Errorf("restartedAt = %v, want 1", got)
+	}
+	if err := sim.RestartComponent("web"); !errors.Is(err, ErrComponentNotCrashed) {
+		t.Errorf("second restart: err = %v, want ErrComponentNotCrashed", err)
+	}
+}
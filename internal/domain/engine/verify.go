@@ -0,0 +1,254 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/evaluation"
+)
+
+// 比對客戶端回報成績時允許的誤差
+const (
+	ScoreTolerance   = 0.01 // 平均總分
+	LatencyTolerance = 0.5  // p95 延遲 (ms)
+)
+
+// ErrInvalidVerification 表示驗證請求缺少必要資料，或其中的操作不允許
+var ErrInvalidVerification = errors.New("invalid verification request")
+
+// ReportedScore 是客戶端 (瀏覽器中的 Wasm) 自行計算的成績，未回報的欄位不比對
+type ReportedScore struct {
+	TotalScore          *float64 `json:"total_score"`
+	Passed              *bool    `json:"passed,omitempty"`
+	P95LatencyMS        *float64 `json:"p95_latency_ms,omitempty"`
+	CrashedComponentIDs []string `json:"crashed_component_ids,omitempty"`
+}
+
+// VerificationRequest 是客戶端提交成績時附上的重現資料：設計圖、種子、玩家操作與自行計算的成績
+type VerificationRequest struct {
+	Design          *design.Design `json:"design"`
+	Seed            int64          `json:"seed"`                       // 0 代表使用設計圖的 seed 或每日挑戰種子
+	TickSeconds     float64        `json:"dt,omitempty"`               // 每個 tick 的秒數，預設 1
+	DurationSeconds float64        `json:"duration_seconds,omitempty"` // 模擬的秒數，預設為關卡的總長度
+	Actions         []RunAction    `json:"actions"`
+	Reported        ReportedScore  `json:"reported"`
+}
+
+// Verify 以伺服器的引擎與目錄 (嚴格模式) 重新模擬客戶端提交的一場遊戲，回傳權威成績並標記與回報不一致的欄位
+//   - 關卡一律使用伺服器端的版本，設計圖中的模擬狀態 (崩潰、積壓、副本與留存率) 會被清除
+//   - 組件規格在開始時與每次玩家操作後都依目錄重建，不允許直接修改模擬狀態或設計圖全域屬性
+func (e *SimpleEngine) Verify(req VerificationRequest) (*evaluation.VerificationReport, error) {
+	if req.Design == nil {
+		return nil, reason(ErrInvalidVerification, "reason.missing_design", nil)
+	}
+	if req.Reported.TotalScore == nil {
+		return nil, reason(ErrInvalidVerification, "reason.missing_reported_score", nil)
+	}
+	s, err := e.scenarioRepo.GetByID(req.Design.ScenarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := req.Seed
+	if seed == 0 {
+		if _, ok := req.Design.Properties.Int("seed"); !ok && !req.Design.Properties.Enabled("daily_challenge") {
+			return nil, reason(ErrInvalidVerification, "reason.missing_seed", nil)
+		}
+		seed = e.resolveSeed(req.Design)
+	}
+
+	enforced, discarded, err := e.enforce(freshDesign(req.Design))
+	if err != nil {
+		return nil, err
+	}
+	sim := e.NewSimulation(enforced, s, seed)
+	if err := sim.checkFunds(); err != nil {
+		return nil, err
+	}
+	if req.TickSeconds != 0 {
+		if err := sim.SetTickSeconds(req.TickSeconds); err != nil {
+			return nil, invalidTick(ErrInvalidVerification, req.TickSeconds)
+		}
+	}
+	duration := req.DurationSeconds
+	if duration <= 0 {
+		duration = float64(ScenarioDuration(s))
+	}
+	// 在配置統計之前拒絕過長的模擬 (NaN 或無限大的秒數也在此拒絕)
+	if !(duration/sim.dt <= MaxRunLogTicks) {
+		return nil, reason(ErrInvalidVerification, "reason.too_many_ticks", map[string]interface{}{"max": MaxRunLogTicks})
+	}
+
+	actions := append([]RunAction(nil), req.Actions...)
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].Tick < actions[j].Tick })
+	seen := make(map[string]bool)
+	for _, dp := range discarded {
+		seen[discardKey(dp)] = true
+	}
+	discard := func(more []evaluation.DiscardedProperty) {
+		for _, dp := range more {
+			if key := discardKey(dp); !seen[key] {
+				seen[key] = true
+				discarded = append(discarded, dp)
+			}
+		}
+	}
+
+	stats := newRunStats(int(duration / sim.dt))
+	ticks := 0
+	for ; sim.elapsed < duration-1e-9; ticks++ {
+		if ticks >= MaxRunLogTicks {
+			return nil, reason(ErrInvalidVerification, "reason.too_many_ticks", map[string]interface{}{"max": MaxRunLogTicks})
+		}
+		applied := false
+		for len(actions) > 0 && actions[0].Tick <= ticks {
+			more, err := sim.applyStrict(actions[0])
+			if err != nil {
+				return nil, actionFailed(ErrInvalidVerification, ticks, actions[0])
+			}
+			discard(more)
+			applied = actions[0].Type == ActionSetProperty || applied
+			actions = actions[1:]
+		}
+		if applied {
+			// 修改屬性後依目錄重建，越權的設定會被忽略並列在報告中 (模擬至今的狀態保留)
+			d, more, err := e.enforceKeepingState(sim.design)
+			if err != nil {
+				return nil, err
+			}
+			sim.design = d
+			discard(more)
+		}
+
+		res, err := sim.Step()
+		if err != nil {
+			return nil, err
+		}
+		stats.add(res, sim.design)
+	}
+
+	score := stats.summary(seed)
+	mismatches := compareReported(req.Reported, score)
+	return &evaluation.VerificationReport{
+		DesignID:            req.Design.ID,
+		ScenarioID:          s.ID,
+		Seed:                score.Seed,
+		Mode:                evaluation.ModeStrict,
+		Ticks:               ticks,
+		DurationSeconds:     sim.elapsed,
+		ComponentCount:      componentCount(sim.design),
+		Score:               score,
+		Verified:            len(mismatches) == 0,
+		Mismatches:          mismatches,
+		DiscardedProperties: discarded,
+	}, nil
+}
+
+// applyStrict 套用一筆玩家操作，但不允許修改模擬狀態 (如 crashed、backlog) 或設計圖全域屬性，
+// 也不允許重啟未崩潰的組件 (避免反覆重啟取得免於崩潰的保護期)；新增的組件先依目錄重建 (經濟模式以目錄的 setup_cost 購買)，回傳被忽略的設定
+func (sim *Simulation) applyStrict(a RunAction) ([]evaluation.DiscardedProperty, error) {
+	var discarded []evaluation.DiscardedProperty
+	switch a.Type {
+	case ActionSetProperty:
+		if a.ComponentID == "" {
+			return nil, fmt.Errorf("不允許修改設計圖全域屬性 %s", a.Property)
+		}
+		if comp := sim.component(a.ComponentID); comp != nil {
+			if spec, ok := component.SchemaFor(comp.Type).Lookup(a.Property); ok && spec.State {
+				return nil, fmt.Errorf("不允許修改模擬狀態 %s", a.Property)
+			}
+		}
+	case ActionAddComponent:
+		if a.Component == nil {
+			break
+		}
+		enforced, more, err := sim.engine.enforce(&design.Design{Components: []component.Component{*a.Component}})
+		if err != nil {
+			return nil, err
+		}
+		a.Component, discarded = &enforced.Components[0], more
+	}
+	if err := sim.apply(a); err != nil {
+		return nil, err
+	}
+	return discarded, nil
+}
+
+// enforceKeepingState 以目錄重建模擬中的設計圖，並保留引擎自己產生的模擬狀態 (目錄重建會清除所有模擬狀態)
+func (e *SimpleEngine) enforceKeepingState(d *design.Design) (*design.Design, []evaluation.DiscardedProperty, error) {
+	enforced, discarded, err := e.enforce(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range enforced.Components {
+		c := &enforced.Components[i]
+		prev := d.Components[i]
+		for _, spec := range component.SchemaFor(c.Type).Properties {
+			if v, ok := prev.Properties[spec.Name]; ok && spec.State {
+				c.Properties[spec.Name] = v
+			}
+		}
+	}
+	for _, spec := range design.PropertySchema.Properties {
+		if v, ok := d.Properties[spec.Name]; ok && spec.State {
+			enforced.Properties[spec.Name] = v
+		}
+	}
+	return enforced, discarded, nil
+}
+
+// freshDesign 複製設計圖並清除模擬狀態，讓重新模擬從頭開始
+func freshDesign(d *design.Design) *design.Design {
+	out := d.Clone()
+	for i := range out.Components {
+		clearState(&out.Components[i])
+	}
+	for _, spec := range design.PropertySchema.Properties {
+		if spec.State {
+			delete(out.Properties, spec.Name)
+		}
+	}
+	return out
+}
+
+// componentCount 計算設計圖中的組件數量 (流量來源不算在內)
+func componentCount(d *design.Design) int {
+	n := 0
+	for _, c := range d.Components {
+		if c.Type != component.TrafficSource {
+			n++
+		}
+	}
+	return n
+}
+
+// discardKey 用來避免同一筆被忽略的設定重複列出 (同一個屬性改成不同的值仍會列出)
+func discardKey(dp evaluation.DiscardedProperty) string {
+	return fmt.Sprintf("%s/%s/%v", dp.ComponentID, dp.Property, dp.Value)
+}
+
+// compareReported 比對客戶端回報的成績與伺服器的結果
+func compareReported(r ReportedScore, actual evaluation.RunSummary) []evaluation.ScoreMismatch {
+	mismatches := []evaluation.ScoreMismatch{}
+	if math.Abs(*r.TotalScore-actual.TotalScore) > ScoreTolerance {
+		mismatches = append(mismatches, evaluation.ScoreMismatch{Field: "total_score", Reported: *r.TotalScore, Actual: actual.TotalScore})
+	}
+	if r.Passed != nil && *r.Passed != actual.Passed {
+		mismatches = append(mismatches, evaluation.ScoreMismatch{Field: "passed", Reported: *r.Passed, Actual: actual.Passed})
+	}
+	if r.P95LatencyMS != nil && math.Abs(*r.P95LatencyMS-actual.P95LatencyMS) > LatencyTolerance {
+		mismatches = append(mismatches, evaluation.ScoreMismatch{Field: "p95_latency_ms", Reported: *r.P95LatencyMS, Actual: actual.P95LatencyMS})
+	}
+	if r.CrashedComponentIDs != nil {
+		reported := append([]string(nil), r.CrashedComponentIDs...)
+		sort.Strings(reported)
+		if !slices.Equal(reported, actual.CrashedComponentIDs) {
+			mismatches = append(mismatches, evaluation.ScoreMismatch{Field: "crashed_component_ids", Reported: reported, Actual: actual.CrashedComponentIDs})
+		}
+	}
+	return mismatches
+}
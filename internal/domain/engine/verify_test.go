@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"errors"
+	"math"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/infrastructure/persistence"
+	"testing"
+)
+
+// 過長的模擬在配置統計之前就被拒絕，極大、無限大或 NaN 的秒數不會讓配置 panic
+func TestVerifyRejectsTooManyTicksBeforeAllocating(t *testing.T) {
+	scenarios := persistence.NewInMemScenarioRepository()
+	if err := scenarios.Save(steadyScenario(100, 60)); err != nil {
+		t.Fatal(err)
+	}
+	e := NewSimpleEngine(nil, scenarios, persistence.NewInMemCatalogRepository(), fixedClock)
+	d := chainDesign(80, newComponent("web", component.WebServer, component.Metadata{"max_qps": float64(1000)}))
+	score := 100.0
+
+	cases := []struct {
+		name     string
+		duration float64
+		dt       float64
+	}{
+		{"huge duration", 1e19, 0},
+		{"infinite duration", math.Inf(1), 0},
+		{"NaN duration", math.NaN(), 0},
+		{"one tick over the limit", MaxRunLogTicks + 1, 0},
+		{"short ticks", MaxRunLogTicks, MinTickSeconds},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := e.Verify(VerificationRequest{Design: d, DurationSeconds: tc.duration, TickSeconds: tc.dt, Reported: ReportedScore{TotalScore: &score}})
+			var re *ReasonError
+			if !errors.Is(err, ErrInvalidVerification) || !errors.As(err, &re) || re.MessageID != "reason.too_many_ticks" {
+				t.Errorf("Verify = %v, want reason.too_many_ticks", err)
+			}
+		})
+	}
+
+	report, err := e.Verify(VerificationRequest{Design: d, DurationSeconds: 30, Reported: ReportedScore{TotalScore: &score}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "simulated seconds", report.DurationSeconds, 30)
+}
@@ -2,9 +2,11 @@ package evaluation
 
 // Score 代表某個維度的評分
 type Score struct {
-	Dimension string  `json:"dimension"` // 如：Availability, Scalability, Cost, Performance
-	Value     float64 `json:"value"`     // 0-100
-	Comment   string  `json:"comment"`   // 針對該維度的具體建議
+	Dimension string                 `json:"dimension"`            // 如：Availability, Scalability, Cost, Performance
+	Value     float64                `json:"value"`                // 0-100
+	Comment   string                 `json:"comment"`              // 針對該維度的具體建議 (依語系產生)
+	MessageID string                 `json:"message_id,omitempty"` // 建議文字的訊息 ID
+	Params    map[string]interface{} `json:"params,omitempty"`     // 建議文字的參數
 }
 
 // Result 是針對系統設計的效能與經濟評估輸出
@@ -24,7 +26,9 @@ type Result struct {
 	CostPerSec    float64 `json:"cost_per_sec"`
 	RevenuePerSec float64 `json:"revenue_per_sec"`
 
-	CreatedAt                int64              `json:"created_at"`
+	CreatedAt                int64              `json:"created_at"`                  // 所在的秒數 (整數)
+	Elapsed                  float64            `json:"elapsed"`                     // 精確的模擬時間 (秒)
+	DT                       float64            `json:"dt"`                          // 這個 tick 代表的秒數
 	ActiveComponentIDs       []string           `json:"active_component_ids"`        // 實際有接收到流量的組件 ID
 	CrashedComponentIDs      []string           `json:"crashed_component_ids"`       // 已經掛掉的組件 ID
 	ComponentLoads           map[string]int64   `json:"component_loads"`             // 每個組件具體承擔的 QPS
@@ -32,7 +36,7 @@ type Result struct {
 	IsBurstActive            bool               `json:"is_burst_active"`             // 當前是否處於突發流量狀態
 	IsAttackActive           bool               `json:"is_attack_active"`            // 當前是否處於遭受惡意攻擊狀態
 	ComponentReplicas        map[string]int     `json:"component_replicas"`          // 每個組件當前的副本數
-	RetentionRate            float64            `json:"retention_rate"`              // 當前使用者留存率 (0.0 - 1.0)
+	RetentionRate            float64            `json:"retention_rate"`              // 當前使用者留存率 (相對關卡流量的倍數，體驗良好時可超過 1)
 	IsRandomDrop             bool               `json:"is_random_drop"`              // 是否處於隨機驟降狀態
 	FulfilledQPS             int64              `json:"fulfilled_qps"`               // 成功取得資料的 QPS
 	ComponentBacklogs        map[string]int64   `json:"component_backlogs"`          // 每個組件當前的訊息積壓量 (MQ 適用)
@@ -42,7 +46,248 @@ type Result struct {
 	ComponentRAMUsage        map[string]float64 `json:"component_ram_usage"`         // 每個組件的 RAM 使用率 (0-100)
 	ComponentReadLoads       map[string]int64   `json:"component_read_loads"`        // 每個組件的讀取 QPS
 	ComponentWriteLoads      map[string]int64   `json:"component_write_loads"`       // 每個組件的寫入 QPS
-	Warnings                 []string           `json:"warnings"`                    // 架構警告訊息（如：Slave 收到寫入流量）
+	Warnings                 []string           `json:"warnings"`                    // 架構警告訊息（如：Slave 收到寫入流量），保留給舊版前端，新客戶端請改用 Events
+	Events                   []Event            `json:"events"`                      // 本 tick 發生的結構化事件 (崩潰、擴展、攻擊、突發、積壓、架構警告)
+	Seed                     int64              `json:"seed"`                        // 本次模擬使用的隨機種子，可用於重現同一場模擬
+	FailedComponentIDs       []string           `json:"failed_component_ids"`        // 因隨機故障 (非過載) 而掛掉的組件 ID
+	InterruptedComponentIDs  []string           `json:"interrupted_component_ids"`   // 被回收而暫時中斷的 spot 組件 ID
+	ComponentCosts           map[string]float64 `json:"component_costs"`             // 每個組件的每秒成本
+	TrafficCosts             CostBreakdown      `json:"traffic_costs"`               // 依流量類別拆分的每秒成本
+	FilteredQPS              int64              `json:"filtered_qps"`                // 被組件刻意過濾的正常請求 QPS (WAF 誤擋、外部 API 依 SLA 丟包)
+
+	Mode                Mode                `json:"mode,omitempty"`                 // 評估模式，嚴格模式下組件規格由伺服器端目錄決定
+	DiscardedProperties []DiscardedProperty `json:"discarded_properties,omitempty"` // 嚴格模式下被忽略的客戶端設定
+	Economy             *Economy            `json:"economy,omitempty"`              // 無盡模式的玩家經濟狀態 (伺服器端模擬場次才有)
+	Budget              *BudgetStatus       `json:"budget,omitempty"`               // 經濟模式的資金與預算狀態 (逐 tick 模擬才有)
+	Experience          *Experience         `json:"experience,omitempty"`           // 使用者體驗與下一個 tick 的留存率
+}
+
+// CostBreakdown 是依流量類別拆分的每秒成本，各項加總即為 CostPerSec
+type CostBreakdown struct {
+	Capacity  float64 `json:"capacity"`  // 與流量無關的節點成本 (含 Auto Scaling 副本)
+	Read      float64 `json:"read"`      // 讀取請求產生的按量計費與 egress 成本
+	Write     float64 `json:"write"`     // 寫入請求產生的按量計費與 egress 成本
+	Malicious float64 `json:"malicious"` // 惡意流量產生的 egress 成本
+}
+
+// Total 回傳各項成本的總和
+func (c CostBreakdown) Total() float64 {
+	return c.Capacity + c.Read + c.Write + c.Malicious
+}
+
+// Add 累加另一筆成本
+func (c *CostBreakdown) Add(o CostBreakdown) {
+	c.Capacity += o.Capacity
+	c.Read += o.Read
+	c.Write += o.Write
+	c.Malicious += o.Malicious
+}
+
+// Experience 是最近一段時間的使用者體驗，決定使用者流失或自然成長
+// 無狀態的評估 (Evaluate) 不保存設計圖，客戶端需將 NextRetentionRate 與最近的紀錄寫回設計圖屬性
+// (retention_rate、recent_latencies、recent_error_rates)，下一次評估的流量才會隨之改變
+type Experience struct {
+	P95LatencyMS      float64   `json:"p95_latency_ms"`      // 最近的 p95 延遲
+	ErrorRate         float64   `json:"error_rate"`          // 最近的平均錯誤率 (不含組件刻意過濾的請求)
+	LatencySLOMS      float64   `json:"latency_slo_ms"`      // 可接受的延遲 (關卡目標)
+	ErrorSLO          float64   `json:"error_slo"`           // 可接受的錯誤率 (關卡目標可用性)
+	Severity          float64   `json:"severity"`            // 體驗不佳的程度 (0 為良好，1 為最差)
+	NextRetentionRate float64   `json:"next_retention_rate"` // 下一個 tick 的留存率
+	RecentLatencies   []float64 `json:"recent_latencies"`    // 最近幾個 tick 的延遲 (含這個 tick)
+	RecentErrorRates  []float64 `json:"recent_error_rates"`  // 最近幾個 tick 的錯誤率 (含這個 tick)
+}
+
+// BudgetStatus 是經濟模式下這個 tick 結束時的資金與預算狀態
+type BudgetStatus struct {
+	Balance           float64 `json:"balance"`             // 購買組件後剩餘的資金
+	BudgetPerSec      float64 `json:"budget_per_sec"`      // 關卡的每秒運作成本預算
+	OverBudgetSeconds float64 `json:"over_budget_seconds"` // 連續超出預算的秒數
+	GraceSeconds      float64 `json:"grace_seconds"`       // 連續超出預算多久後模擬失敗
+	Failed            bool    `json:"failed"`              // 已持續超出預算，本場模擬失敗
+}
+
+// Economy 是無盡模式中玩家在這個 tick 結束時的經濟狀態
+type Economy struct {
+	PlayerID     string  `json:"player_id,omitempty"`
+	Balance      float64 `json:"balance"`       // 目前的金幣
+	SystemHealth float64 `json:"system_health"` // 0-100，根據延遲與錯誤率計算
+	TotalUsers   int64   `json:"total_users"`   // 累積獲得的使用者
+	Uptime       float64 `json:"uptime"`        // 系統總運行秒數
+	Bankrupt     bool    `json:"bankrupt"`      // 金幣小於 0，本次遊戲結束
+}
+
+// Mode 是評估模式
+type Mode string
+
+const (
+	// ModeSandbox 信任設計圖中的成本與容量 (自由遊玩)
+	ModeSandbox Mode = "sandbox"
+	// ModeStrict 以伺服器端目錄決定成本與容量，只接受允許的升級選項 (排行榜與評分)
+	ModeStrict Mode = "strict"
+)
+
+// DiscardedProperty 是嚴格模式下被忽略的一筆客戶端設定
+type DiscardedProperty struct {
+	ComponentID string                 `json:"component_id"`
+	Property    string                 `json:"property"`
+	Value       interface{}            `json:"value"`             // 客戶端傳入的值
+	Applied     interface{}            `json:"applied,omitempty"` // 實際採用的目錄值 (未設定代表使用類型預設值)
+	MessageID   string                 `json:"message_id"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Message     string                 `json:"message"`
+}
+
+// ConfidenceInterval 代表某個統計量的信賴區間
+type ConfidenceInterval struct {
+	Level float64 `json:"level"` // 信賴水準，如 0.95
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+}
+
+// Distribution 描述多次模擬中某個指標的分佈
+type Distribution struct {
+	Mean   float64            `json:"mean"`
+	StdDev float64            `json:"std_dev"`
+	Min    float64            `json:"min"`
+	Max    float64            `json:"max"`
+	P5     float64            `json:"p5"`
+	P50    float64            `json:"p50"`
+	P95    float64            `json:"p95"`
+	MeanCI ConfidenceInterval `json:"mean_ci"` // 平均值的信賴區間
+}
+
+// RunSummary 是蒙地卡羅評估中單次模擬的摘要
+type RunSummary struct {
+	Seed                int64           `json:"seed"`
+	TotalScore          float64         `json:"total_score"`    // 整場模擬的平均總分
+	P95LatencyMS        float64         `json:"p95_latency_ms"` // 整場模擬的 p95 延遲
+	CostPerSec          float64         `json:"cost_per_sec"`   // 整場模擬的平均每秒成本
+	Passed              bool            `json:"passed"`
+	BudgetFailed        bool            `json:"budget_failed,omitempty"` // 經濟模式下連續超出預算而失敗
+	CrashedComponentIDs []string        `json:"crashed_component_ids"`   // 整場模擬中曾經崩潰的組件
+	Cost                *CostProjection `json:"cost,omitempty"`          // 依整場模擬推算的長期成本
+}
+
+// CostProjection 是依一場模擬的成本紀錄推算的長期成本 (以模擬期間的平均每秒成本持續運作)
+type CostProjection struct {
+	DurationSeconds        float64    `json:"duration_seconds"`          // 推算依據的模擬秒數
+	AvgCostPerSec          float64    `json:"avg_cost_per_sec"`          // 依 tick 長度加權的平均每秒成本
+	Hourly                 float64    `json:"hourly"`                    // 每小時成本
+	Monthly                float64    `json:"monthly"`                   // 每月成本 (730 小時)
+	Yearly                 float64    `json:"yearly"`                    // 每年成本 (8760 小時)
+	FulfilledRequests      float64    `json:"fulfilled_requests"`        // 模擬期間成功取得資料的請求數
+	CostPerMillionRequests float64    `json:"cost_per_million_requests"` // 每百萬個成功請求的成本，沒有成功請求時為 0
+	ByComponentType        []TypeCost `json:"by_component_type"`         // 依組件類型拆分 (成本高者在前，不含沒有產生成本的類型)
+}
+
+// TypeCost 是某一類組件的長期成本
+type TypeCost struct {
+	Type          string  `json:"type"`
+	AvgCostPerSec float64 `json:"avg_cost_per_sec"`
+	Hourly        float64 `json:"hourly"`
+	Monthly       float64 `json:"monthly"`
+	Yearly        float64 `json:"yearly"`
+	Share         float64 `json:"share"` // 佔總成本的比例 (0-1)
+}
+
+// MonteCarloReport 是同一個設計在同一個關卡中多次隨機模擬的統計報告
+type MonteCarloReport struct {
+	DesignID        string             `json:"design_id"`
+	ScenarioID      string             `json:"scenario_id"`
+	Seed            int64              `json:"seed"` // 基礎種子，每次模擬的種子皆由此衍生
+	Runs            int                `json:"runs"`
+	DurationSeconds int64              `json:"duration_seconds"`
+	TotalScore      Distribution       `json:"total_score"`
+	P95LatencyMS    Distribution       `json:"p95_latency_ms"`
+	PassRate        float64            `json:"pass_rate"`
+	PassRateCI      ConfidenceInterval `json:"pass_rate_ci"`
+	CrashCounts     map[string]int     `json:"crash_counts"` // 每個組件在幾次模擬中崩潰過
+	RunSummaries    []RunSummary       `json:"run_summaries"`
+}
+
+// Comparison 是兩個設計的蒙地卡羅報告比較結果 (A - B)
+type Comparison struct {
+	DesignA         string             `json:"design_a"`
+	DesignB         string             `json:"design_b"`
+	ScoreDiff       float64            `json:"score_diff"`
+	ScoreDiffCI     ConfidenceInterval `json:"score_diff_ci"`
+	PassRateDiff    float64            `json:"pass_rate_diff"`
+	LatencyDiffMS   float64            `json:"latency_diff_ms"`
+	Significant     bool               `json:"significant"` // 分數差異的信賴區間是否不包含 0
+	PreferredDesign string             `json:"preferred_design,omitempty"`
+}
+
+// ComponentHeadroom 描述單一組件在最大可持續流量下的剩餘容量
+type ComponentHeadroom struct {
+	ComponentID     string  `json:"component_id"`
+	Name            string  `json:"name"`
+	Load            int64   `json:"load"`              // 最大可持續流量下承擔的 QPS
+	EffectiveMaxQPS int64   `json:"effective_max_qps"` // 當下的有效最大 QPS (含 Auto Scaling)
+	Utilization     float64 `json:"utilization"`       // 使用率 (0.0 - 1.0+)
+	HeadroomQPS     int64   `json:"headroom_qps"`      // 尚可承擔的 QPS
+}
+
+// CapacityReport 是容量極限搜尋的結果
+type CapacityReport struct {
+	DesignID          string              `json:"design_id"`
+	ScenarioID        string              `json:"scenario_id"`
+	MinFulfillment    float64             `json:"min_fulfillment"`     // 判定為「可持續」的最低資料獲取率
+	MaxSustainableQPS int64               `json:"max_sustainable_qps"` // 不崩潰且資料獲取率達標的最大輸入 QPS
+	BreakingQPS       int64               `json:"breaking_qps"`        // 系統開始失效的輸入 QPS (0 代表在搜尋上限內未失效)
+	BreakingReason    string              `json:"breaking_reason"`     // "crash" 或 "fulfillment"
+	FirstSaturatedID  string              `json:"first_saturated_id"`  // 最先飽和 (崩潰或使用率最高) 的組件
+	Headroom          []ComponentHeadroom `json:"headroom"`            // 依使用率由高到低排序
+}
+
+// TickRecord 是執行紀錄中單一 tick 的精簡摘要，Digest 是完整評估結果的雜湊，重播時用來比對結果是否一致
+type TickRecord struct {
+	Elapsed             float64            `json:"elapsed"`
+	DT                  float64            `json:"dt"`
+	TotalScore          float64            `json:"total_score"`
+	TotalQPS            int64              `json:"total_qps"`
+	FulfilledQPS        int64              `json:"fulfilled_qps"`
+	AvgLatencyMS        float64            `json:"avg_latency_ms"`
+	ErrorRate           float64            `json:"error_rate"`
+	RetentionRate       float64            `json:"retention_rate"`
+	CostPerSec          float64            `json:"cost_per_sec"`
+	TypeCosts           map[string]float64 `json:"type_costs,omitempty"` // 依組件類型拆分的每秒成本，用於推算長期成本
+	CrashedComponentIDs []string           `json:"crashed_component_ids,omitempty"`
+	Events              []EventCode        `json:"events,omitempty"`
+	Digest              string             `json:"digest"`
+}
+
+// ReplayReport 是依執行紀錄重播一場模擬的結果
+type ReplayReport struct {
+	DesignID   string          `json:"design_id"`
+	ScenarioID string          `json:"scenario_id"`
+	Seed       int64           `json:"seed"`
+	Ticks      int             `json:"ticks"`
+	Matched    bool            `json:"matched"`     // 每個 tick 的結果是否都與紀錄一致
+	DivergedAt int             `json:"diverged_at"` // 第一個不一致的 tick (從 0 起算)，一致時為 -1
+	Results    []*Result       `json:"results"`     // 重播產生的每個 tick 評估結果
+	Cost       *CostProjection `json:"cost"`        // 依重播結果推算的長期成本
+}
+
+// ScoreMismatch 是客戶端回報的成績與伺服器重新模擬結果不一致的欄位
+type ScoreMismatch struct {
+	Field    string      `json:"field"`
+	Reported interface{} `json:"reported"`
+	Actual   interface{} `json:"actual"`
+}
+
+// VerificationReport 是伺服器以自己的引擎與目錄重新模擬後的權威成績
+type VerificationReport struct {
+	DesignID            string              `json:"design_id"`
+	ScenarioID          string              `json:"scenario_id"`
+	Seed                int64               `json:"seed"`
+	Mode                Mode                `json:"mode"`
+	Ticks               int                 `json:"ticks"`
+	DurationSeconds     float64             `json:"duration_seconds"`
+	ComponentCount      int                 `json:"component_count"` // 模擬結束時的組件數量 (不含流量來源，含玩家操作新增的組件)
+	Score               RunSummary          `json:"score"`           // 伺服器計算的成績 (以此為準)
+	Verified            bool                `json:"verified"`        // 客戶端回報的成績是否與伺服器一致
+	Mismatches          []ScoreMismatch     `json:"mismatches"`      // 不一致的欄位
+	DiscardedProperties []DiscardedProperty `json:"discarded_properties,omitempty"`
 }
 
 // Engine 定義評估引擎的介面
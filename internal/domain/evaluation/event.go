@@ -0,0 +1,41 @@
+package evaluation
+
+// EventCode 定義模擬事件的種類
+type EventCode string
+
+const (
+	EventComponentCrash   EventCode = "COMPONENT_CRASH"   // 過載崩潰
+	EventComponentOOM     EventCode = "COMPONENT_OOM"     // 記憶體耗盡崩潰
+	EventComponentFailure EventCode = "COMPONENT_FAILURE" // 隨機硬體故障
+	EventScaleUp          EventCode = "SCALE_UP"          // Auto Scaling 擴展
+	EventScaleDown        EventCode = "SCALE_DOWN"        // Auto Scaling 縮減
+	EventAttackStart      EventCode = "ATTACK_START"      // DDoS 攻擊開始
+	EventAttackStop       EventCode = "ATTACK_STOP"       // DDoS 攻擊停止
+	EventBurstStart       EventCode = "BURST_START"       // 突發流量開始
+	EventRandomDrop       EventCode = "RANDOM_DROP"       // 流量驟降開始
+	EventBacklogThreshold EventCode = "BACKLOG_THRESHOLD" // MQ 積壓超過警戒值
+	EventSlaveWrite       EventCode = "SLAVE_WRITE"       // 架構警告：Slave DB 收到寫入流量
+	EventOverBudget       EventCode = "OVER_BUDGET"       // 經濟模式：每秒成本開始超出預算
+	EventBudgetFailed     EventCode = "BUDGET_FAILED"     // 經濟模式：連續超出預算過久，模擬失敗
+	EventSpotInterrupted  EventCode = "SPOT_INTERRUPTED"  // spot 組件被回收而暫時中斷
+)
+
+// EventSeverity 定義事件的嚴重程度
+type EventSeverity string
+
+const (
+	SeverityInfo     EventSeverity = "info"
+	SeverityWarning  EventSeverity = "warning"
+	SeverityCritical EventSeverity = "critical"
+)
+
+// Event 是模擬過程中發生的結構化事件
+// 文字訊息由客戶端依 Code 與 Params 自行組合 (可翻譯、可依組件高亮)
+type Event struct {
+	Code        EventCode              `json:"code"`
+	Severity    EventSeverity          `json:"severity"`
+	ComponentID string                 `json:"component_id,omitempty"` // 事件相關的組件 (全域事件為空)
+	Tick        int64                  `json:"tick"`                   // 事件發生的秒數
+	Params      map[string]interface{} `json:"params,omitempty"`       // 事件參數，如負載、副本數
+	Message     string                 `json:"message,omitempty"`      // 依語系產生的訊息文字
+}
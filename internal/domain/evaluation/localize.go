@@ -0,0 +1,41 @@
+package evaluation
+
+import "system-design-game/internal/i18n"
+
+// Localize 依語系產生評分說明、事件訊息與架構警告文字
+func (r *Result) Localize(l i18n.Locale) {
+	for i := range r.Scores {
+		if r.Scores[i].MessageID != "" {
+			r.Scores[i].Comment = i18n.T(l, r.Scores[i].MessageID, r.Scores[i].Params)
+		}
+	}
+
+	r.Warnings = nil
+	for i := range r.Events {
+		ev := &r.Events[i]
+		ev.Message = i18n.T(l, "event."+string(ev.Code), ev.Params)
+		if ev.Code == EventSlaveWrite {
+			r.Warnings = append(r.Warnings, ev.Message)
+		}
+	}
+
+	for i := range r.DiscardedProperties {
+		dp := &r.DiscardedProperties[i]
+		dp.Message = i18n.T(l, dp.MessageID, dp.Params)
+	}
+}
+
+// Localize 依語系產生重播中每個 tick 結果的文字
+func (r *ReplayReport) Localize(l i18n.Locale) {
+	for _, res := range r.Results {
+		res.Localize(l)
+	}
+}
+
+// Localize 依語系產生驗證報告中被忽略設定的說明
+func (r *VerificationReport) Localize(l i18n.Locale) {
+	for i := range r.DiscardedProperties {
+		dp := &r.DiscardedProperties[i]
+		dp.Message = i18n.T(l, dp.MessageID, dp.Params)
+	}
+}
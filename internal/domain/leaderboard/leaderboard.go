@@ -0,0 +1,102 @@
+package leaderboard
+
+import (
+	"errors"
+	"sort"
+)
+
+// Entry 是一位玩家在某個關卡的最佳成績 (由伺服器重新模擬驗證過)
+type Entry struct {
+	ScenarioID     string    `json:"scenario_id"`
+	PlayerID       string    `json:"player_id"`
+	DesignID       string    `json:"design_id"`
+	TotalScore     float64   `json:"total_score"`     // 整場模擬的平均總分
+	Passed         bool      `json:"passed"`          // 平均總分是否達到 95 分
+	CostPerSec     float64   `json:"cost_per_sec"`    // 整場模擬的平均每秒成本
+	P95LatencyMS   float64   `json:"p95_latency_ms"`  // 整場模擬的 p95 延遲
+	ComponentCount int       `json:"component_count"` // 組件數量 (不含流量來源)
+	Seed           int64     `json:"seed"`
+	Settings       *Settings `json:"settings,omitempty"` // 模擬時的難度設定 (較早的成績沒有記錄)
+	SubmittedAt    int64     `json:"submitted_at"`
+}
+
+// Better 判斷 e 是否優於 other：總分較高者優先，其次為成本較低、延遲較低、組件較少
+func (e *Entry) Better(other *Entry) bool {
+	switch {
+	case e.TotalScore != other.TotalScore:
+		return e.TotalScore > other.TotalScore
+	case e.CostPerSec != other.CostPerSec:
+		return e.CostPerSec < other.CostPerSec
+	case e.P95LatencyMS != other.P95LatencyMS:
+		return e.P95LatencyMS < other.P95LatencyMS
+	}
+	return e.ComponentCount < other.ComponentCount
+}
+
+// SortKey 是排行榜的排序指標
+type SortKey string
+
+const (
+	SortScore      SortKey = "score"      // 總分由高到低
+	SortCost       SortKey = "cost"       // 每秒成本由低到高
+	SortLatency    SortKey = "latency"    // p95 延遲由低到高
+	SortComponents SortKey = "components" // 組件數量由少到多
+)
+
+// ErrInvalidSort 表示不支援的排序指標
+var ErrInvalidSort = errors.New("invalid leaderboard sort")
+
+// Standing 是排行榜中的一筆排名
+type Standing struct {
+	Rank int `json:"rank"`
+	*Entry
+}
+
+// Rank 依指標排序並編上名次 (從 1 起算)：通過關卡的成績一律排在未通過的前面，
+// 避免未完成的極簡設計以低成本排在前面；指標相同時依 Better 的順序，再以較早提交者優先
+func Rank(entries []*Entry, key SortKey) ([]Standing, error) {
+	var metric func(e *Entry) float64
+	switch key {
+	case SortScore, "":
+		metric = func(e *Entry) float64 { return -e.TotalScore }
+	case SortCost:
+		metric = func(e *Entry) float64 { return e.CostPerSec }
+	case SortLatency:
+		metric = func(e *Entry) float64 { return e.P95LatencyMS }
+	case SortComponents:
+		metric = func(e *Entry) float64 { return float64(e.ComponentCount) }
+	default:
+		return nil, ErrInvalidSort
+	}
+
+	ranked := append([]*Entry(nil), entries...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Passed != b.Passed {
+			return a.Passed
+		}
+		if ma, mb := metric(a), metric(b); ma != mb {
+			return ma < mb
+		}
+		if a.Better(b) != b.Better(a) {
+			return a.Better(b)
+		}
+		return a.SubmittedAt < b.SubmittedAt
+	})
+
+	standings := make([]Standing, len(ranked))
+	for i, e := range ranked {
+		standings[i] = Standing{Rank: i + 1, Entry: e}
+	}
+	return standings, nil
+}
+
+// ErrNotFound 表示玩家在該關卡還沒有成績，Repository 回傳的錯誤可用 errors.Is 判斷
+var ErrNotFound = errors.New("leaderboard entry not found")
+
+// Repository 定義排行榜的持久化介面，每位玩家在每個關卡只保留一筆 (最佳) 成績
+type Repository interface {
+	Save(entry *Entry) error
+	Get(scenarioID, playerID string) (*Entry, error)
+	ListByScenario(scenarioID string) ([]*Entry, error)
+}
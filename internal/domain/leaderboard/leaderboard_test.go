@@ -0,0 +1,110 @@
+package leaderboard
+
+import (
+	"errors"
+	"reflect"
+	"system-design-game/internal/domain/component"
+	"testing"
+)
+
+func TestBetter(t *testing.T) {
+	base := Entry{TotalScore: 90, CostPerSec: 5, P95LatencyMS: 40, ComponentCount: 4}
+	cases := []struct {
+		name   string
+		change func(e *Entry)
+	}{
+		{"higher score", func(e *Entry) { e.TotalScore = 91; e.CostPerSec = 50 }},
+		{"same score, lower cost", func(e *Entry) { e.CostPerSec = 4; e.P95LatencyMS = 400 }},
+		{"same score and cost, lower latency", func(e *Entry) { e.P95LatencyMS = 30; e.ComponentCount = 40 }},
+		{"only fewer components", func(e *Entry) { e.ComponentCount = 3 }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			better, worse := base, base
+			tc.change(&better)
+			if !better.Better(&worse) || worse.Better(&better) {
+				t.Errorf("%+v should be better than %+v", better, worse)
+			}
+		})
+	}
+	if same := base; same.Better(&base) {
+		t.Error("an entry is not better than an equal one")
+	}
+}
+
+func TestRank(t *testing.T) {
+	entries := []*Entry{
+		{PlayerID: "cheap-fail", TotalScore: 50, CostPerSec: 1, P95LatencyMS: 300, ComponentCount: 1, SubmittedAt: 1},
+		{PlayerID: "fast", Passed: true, TotalScore: 96, CostPerSec: 8, P95LatencyMS: 20, ComponentCount: 6, SubmittedAt: 2},
+		{PlayerID: "cheap", Passed: true, TotalScore: 97, CostPerSec: 3, P95LatencyMS: 45, ComponentCount: 4, SubmittedAt: 3},
+		{PlayerID: "late-twin", Passed: true, TotalScore: 97, CostPerSec: 3, P95LatencyMS: 45, ComponentCount: 4, SubmittedAt: 5},
+		{PlayerID: "small", Passed: true, TotalScore: 96, CostPerSec: 5, P95LatencyMS: 60, ComponentCount: 3, SubmittedAt: 4},
+	}
+	cases := []struct {
+		key  SortKey
+		want []string
+	}{
+		// 總分相同時依 Better (成本)，完全相同時較早提交者優先；未通過的一律排在後面
+		{SortScore, []string{"cheap", "late-twin", "small", "fast", "cheap-fail"}},
+		{"", []string{"cheap", "late-twin", "small", "fast", "cheap-fail"}},
+		{SortCost, []string{"cheap", "late-twin", "small", "fast", "cheap-fail"}},
+		{SortLatency, []string{"fast", "cheap", "late-twin", "small", "cheap-fail"}},
+		// 組件數相同 (4) 時依 Better：總分較高者優先
+		{SortComponents, []string{"small", "cheap", "late-twin", "fast", "cheap-fail"}},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.key), func(t *testing.T) {
+			standings, err := Rank(entries, tc.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for i, s := range standings {
+				if s.Rank != i+1 {
+					t.Errorf("standing %d has rank %d", i, s.Rank)
+				}
+				got = append(got, s.PlayerID)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("order = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if entries[0].PlayerID != "cheap-fail" {
+		t.Error("Rank should not reorder the caller's slice")
+	}
+	if _, err := Rank(entries, "fastest"); !errors.Is(err, ErrInvalidSort) {
+		t.Errorf("Rank(fastest) = %v, want ErrInvalidSort", err)
+	}
+}
+
+func TestDeviation(t *testing.T) {
+	standard := StandardSettings()
+	if v := standard.Design["churn_rate"]; v != 0.005 {
+		t.Errorf("standard churn_rate = %v, want the schema default 0.005", v)
+	}
+	cases := []struct {
+		name     string
+		props    component.Metadata
+		standard component.Metadata
+		want     string
+	}{
+		{"unset", component.Metadata{}, standard.Source, ""},
+		{"explicit defaults", component.Metadata{"start_qps": float64(0), "enable_attacks": false, "read_ratio": float64(95)}, standard.Source, ""},
+		{"attacks disabled is the default", component.Metadata{"enable_attacks": false, "enable_failures": true}, standard.Source, "enable_failures"},
+		{"extra traffic", component.Metadata{"start_qps": float64(-500)}, standard.Source, "start_qps"},
+		{"wrong type", component.Metadata{"burst_traffic": "no"}, standard.Source, "burst_traffic"},
+		{"steady traffic", component.Metadata{"steady_traffic": true}, standard.Design, "steady_traffic"},
+		{"no random drops", component.Metadata{"random_drop_probability": float64(0)}, standard.Design, "random_drop_probability"},
+		{"slower churn", component.Metadata{"churn_rate": 0.005, "growth_rate": 0.1}, standard.Design, "growth_rate"},
+		{"economy", component.Metadata{"economy": true, "seed": float64(7)}, standard.Design, "economy"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Deviation(tc.props, tc.standard); got != tc.want {
+				t.Errorf("Deviation = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
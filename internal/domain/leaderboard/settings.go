@@ -0,0 +1,65 @@
+package leaderboard
+
+import (
+	"sort"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+)
+
+// 影響關卡難度的屬性：排行榜的成績一律在關卡的標準設定 (屬性定義的預設值) 下計算，
+// 玩家不能自行關閉隨機事件、加上額外流量或調整留存率來換取較高的分數
+var (
+	sourceSettings = []string{"start_qps", "burst_traffic", "enable_attacks", "enable_failures"}
+	designSettings = []string{"steady_traffic", "random_drop_probability", "churn_rate", "growth_rate", "max_retention", "economy"}
+)
+
+// Settings 是一筆成績模擬時的難度設定
+type Settings struct {
+	Source component.Metadata `json:"source"` // 流量來源：額外流量與突發、攻擊、故障的開關
+	Design component.Metadata `json:"design"` // 設計圖全域：流量波動、驟降機率、留存率與經濟模式
+}
+
+// StandardSettings 回傳排行榜使用的標準難度設定
+func StandardSettings() Settings {
+	return Settings{
+		Source: defaults(component.SchemaFor(component.TrafficSource), sourceSettings),
+		Design: defaults(design.PropertySchema, designSettings),
+	}
+}
+
+func defaults(schema component.Schema, names []string) component.Metadata {
+	m := component.Metadata{}
+	for _, name := range names {
+		spec, _ := schema.Lookup(name)
+		m[name] = spec.Default
+	}
+	return m
+}
+
+// Deviation 回傳 props 中第一個 (依名稱排序) 與標準設定 standard 不同的屬性名稱，全部符合時回傳空字串；
+// 未設定的屬性使用預設值，視為符合
+func Deviation(props, standard component.Metadata) string {
+	names := make([]string, 0, len(standard))
+	for name := range standard {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if v, set := props[name]; set && v != nil && !sameValue(v, standard[name]) {
+			return name
+		}
+	}
+	return ""
+}
+
+// sameValue 比較布林或數值屬性 (JSON 解碼後的數字為 float64，預設值可能為 int)
+func sameValue(v, want interface{}) bool {
+	m := component.Metadata{"v": v, "want": want}
+	if b, ok := m.Bool("want"); ok {
+		got, ok := m.Bool("v")
+		return ok && got == b
+	}
+	f, _ := m.Float("want")
+	got, ok := m.Float("v")
+	return ok && got == f
+}
@@ -0,0 +1,174 @@
+package lint
+
+import (
+	"sort"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/i18n"
+)
+
+// Severity 定義規則違反的嚴重程度
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Issue 是一筆架構建議
+type Issue struct {
+	RuleID       string                 `json:"rule_id"`
+	Severity     Severity               `json:"severity"`
+	Message      string                 `json:"message"`
+	ComponentIDs []string               `json:"component_ids"`    // 受影響的組件
+	Params       map[string]interface{} `json:"params,omitempty"` // 訊息參數，文字由 Localize 依語系產生
+}
+
+// Rule 是一條靜態架構規則，只檢查拓撲，不執行模擬
+type Rule struct {
+	ID          string   `json:"id"`
+	Severity    Severity `json:"severity"`
+	Description string   `json:"description"`
+	check       func(r Rule, g *graph) []Issue
+}
+
+// Linter 依序執行一組規則
+type Linter struct {
+	rules []Rule
+}
+
+// NewLinter 以指定規則建立 Linter，未指定時使用 DefaultRules
+func NewLinter(rules ...Rule) *Linter {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	return &Linter{rules: rules}
+}
+
+// Rules 回傳此 Linter 使用的規則
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// Lint 對設計圖執行所有規則，結果依嚴重程度排序
+func (l *Linter) Lint(d *design.Design) []Issue {
+	g := newGraph(d)
+	issues := []Issue{}
+	for _, r := range l.rules {
+		issues = append(issues, r.check(r, g)...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return severityRank(issues[i].Severity) < severityRank(issues[j].Severity)
+	})
+	Localize(issues, i18n.DefaultLocale)
+	return issues
+}
+
+// Localize 依語系產生每筆建議的訊息文字
+// 自訂規則若沒有對應的翻譯，保留規則本身產生的訊息
+func Localize(issues []Issue, l i18n.Locale) {
+	for i := range issues {
+		issues[i].Message = i18n.TOr(l, "lint."+issues[i].RuleID+".message", issues[i].Message, issues[i].Params)
+	}
+}
+
+// LocalizeRules 回傳依語系翻譯說明後的規則
+func LocalizeRules(rules []Rule, l i18n.Locale) []Rule {
+	out := make([]Rule, len(rules))
+	for i, r := range rules {
+		r.Description = i18n.TOr(l, "lint."+r.ID+".description", r.Description, nil)
+		out[i] = r
+	}
+	return out
+}
+
+// Lint 以預設規則檢查設計圖
+func Lint(d *design.Design) []Issue {
+	return NewLinter().Lint(d)
+}
+
+// graph 是設計圖的鄰接表表示，供各規則共用
+type graph struct {
+	comps   []component.Component
+	byID    map[string]component.Component
+	adj     map[string][]string
+	reverse map[string][]string
+}
+
+func newGraph(d *design.Design) *graph {
+	g := &graph{
+		comps:   d.Components,
+		byID:    make(map[string]component.Component),
+		adj:     make(map[string][]string),
+		reverse: make(map[string][]string),
+	}
+	for _, c := range d.Components {
+		g.byID[c.ID] = c
+	}
+	for _, conn := range d.Connections {
+		if _, ok := g.byID[conn.FromID]; !ok {
+			continue
+		}
+		if _, ok := g.byID[conn.ToID]; !ok {
+			continue
+		}
+		g.adj[conn.FromID] = append(g.adj[conn.FromID], conn.ToID)
+		g.reverse[conn.ToID] = append(g.reverse[conn.ToID], conn.FromID)
+	}
+	return g
+}
+
+// sources 回傳所有流量來源
+func (g *graph) sources() []string {
+	var out []string
+	for _, c := range g.comps {
+		if c.Type == component.TrafficSource {
+			out = append(out, c.ID)
+		}
+	}
+	return out
+}
+
+// reachable 回傳從 start 出發 (不經過 blocked) 可抵達的組件；stop 回傳 true 的組件不再往下走
+func (g *graph) reachable(start []string, blocked string, stop func(component.Component) bool) map[string]bool {
+	seen := make(map[string]bool)
+	queue := append([]string(nil), start...)
+	for _, id := range start {
+		seen[id] = true
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if stop != nil && stop(g.byID[cur]) {
+			continue
+		}
+		for _, next := range g.adj[cur] {
+			if next == blocked || seen[next] {
+				continue
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	return seen
+}
+
+func severityRank(s Severity) int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// displayName 回傳組件的顯示名稱，未命名時使用 ID
+func displayName(c component.Component) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.ID
+}
@@ -0,0 +1,217 @@
+package lint
+
+import (
+	"sort"
+	"system-design-game/internal/domain/component"
+)
+
+// DefaultRules 回傳內建的架構規則
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID:          "single-point-of-failure",
+			Severity:    SeverityWarning,
+			Description: "所有流量路徑都必須經過、且本身沒有備援的組件",
+			check:       checkSinglePointOfFailure,
+		},
+		{
+			ID:          "db-exposed-to-traffic",
+			Severity:    SeverityError,
+			Description: "資料庫直接連接流量來源",
+			check:       checkDatabaseExposed,
+		},
+		{
+			ID:          "unprotected-entry-point",
+			Severity:    SeverityWarning,
+			Description: "公開入口沒有經過 WAF 或 API Gateway 就抵達後端",
+			check:       checkUnprotectedEntry,
+		},
+		{
+			ID:          "cache-without-backing-store",
+			Severity:    SeverityWarning,
+			Description: "快取後方沒有任何資料來源，未命中的請求無處可去",
+			check:       checkCacheWithoutBackend,
+		},
+		{
+			ID:          "mq-without-consumer",
+			Severity:    SeverityError,
+			Description: "訊息隊列沒有任何消費者，訊息只會無限積壓",
+			check:       checkQueueWithoutConsumer,
+		},
+		{
+			ID:          "asg-without-load-balancer",
+			Severity:    SeverityWarning,
+			Description: "Auto Scaling Group 前方沒有 Load Balancer，新副本分不到流量",
+			check:       checkASGWithoutLB,
+		},
+	}
+}
+
+// spofCandidates 是需要自行維運、可能成為單點故障的組件類型
+// LB、CDN、WAF 等託管型基礎設施本身即具備高可用性，不列入檢查
+var spofCandidates = map[component.Type]bool{
+	component.WebServer:        true,
+	component.AutoScalingGroup: true,
+	component.Database:         true,
+	component.Cache:            true,
+	component.NoSQL:            true,
+	component.SearchEngine:     true,
+	component.Worker:           true,
+	component.VideoTranscoding: true,
+}
+
+// hasRedundancy 判斷組件本身是否具備備援 (Auto Scaling 或主從架構)
+func hasRedundancy(c component.Component) bool {
+	if c.Properties.Enabled("auto_scaling") {
+		return true
+	}
+	return c.Properties.TextOr("replication_mode", "SINGLE") == "MASTER_SLAVE" && c.Properties.IntOr("slave_count", 0) > 0
+}
+
+func checkSinglePointOfFailure(r Rule, g *graph) []Issue {
+	sources := g.sources()
+	if len(sources) == 0 {
+		return nil
+	}
+
+	// 末端組件：可由流量來源抵達、且沒有下游的組件 (資料最終的落點)
+	reach := g.reachable(sources, "", nil)
+	isSink := func(id string) bool {
+		return reach[id] && len(g.adj[id]) == 0 && g.byID[id].Type != component.TrafficSource
+	}
+
+	var issues []Issue
+	for _, c := range g.comps {
+		if !reach[c.ID] || !spofCandidates[c.Type] || hasRedundancy(c) {
+			continue
+		}
+		without := g.reachable(sources, c.ID, nil)
+		survived := false
+		for id := range without {
+			if id != c.ID && isSink(id) {
+				survived = true
+				break
+			}
+		}
+		if !survived {
+			issues = append(issues, Issue{
+				RuleID:       r.ID,
+				Severity:     r.Severity,
+				Params:       map[string]interface{}{"component": displayName(c)},
+				ComponentIDs: []string{c.ID},
+			})
+		}
+	}
+	return issues
+}
+
+func checkDatabaseExposed(r Rule, g *graph) []Issue {
+	var issues []Issue
+	for _, src := range g.sources() {
+		for _, next := range g.adj[src] {
+			c := g.byID[next]
+			if c.Type == component.Database || c.Type == component.NoSQL {
+				issues = append(issues, Issue{
+					RuleID:       r.ID,
+					Severity:     r.Severity,
+					Params:       map[string]interface{}{"component": displayName(c)},
+					ComponentIDs: []string{src, next},
+				})
+			}
+		}
+	}
+	return issues
+}
+
+func checkUnprotectedEntry(r Rule, g *graph) []Issue {
+	// 經過 WAF 或 API Gateway 後視為已受保護，不再往下檢查
+	protectedStop := func(c component.Component) bool {
+		return c.Type == component.WAF || c.Type == component.APIGateway
+	}
+
+	var issues []Issue
+	for _, src := range g.sources() {
+		if len(g.adj[src]) == 0 {
+			continue
+		}
+		exposed := []string{}
+		for id := range g.reachable([]string{src}, "", protectedStop) {
+			switch g.byID[id].Type {
+			case component.TrafficSource, component.WAF, component.APIGateway, component.LoadBalancer, component.CDN:
+				continue
+			}
+			exposed = append(exposed, id)
+		}
+		if len(exposed) == 0 {
+			continue
+		}
+		issues = append(issues, Issue{
+			RuleID:       r.ID,
+			Severity:     r.Severity,
+			Params:       map[string]interface{}{"component": displayName(g.byID[src]), "count": len(exposed)},
+			ComponentIDs: append([]string{src}, sortedIDs(exposed)...),
+		})
+	}
+	return issues
+}
+
+func checkCacheWithoutBackend(r Rule, g *graph) []Issue {
+	var issues []Issue
+	for _, c := range g.comps {
+		if (c.Type == component.Cache || c.Type == component.CDN) && len(g.adj[c.ID]) == 0 {
+			issues = append(issues, Issue{
+				RuleID:       r.ID,
+				Severity:     r.Severity,
+				Params:       map[string]interface{}{"component": displayName(c)},
+				ComponentIDs: []string{c.ID},
+			})
+		}
+	}
+	return issues
+}
+
+func checkQueueWithoutConsumer(r Rule, g *graph) []Issue {
+	var issues []Issue
+	for _, c := range g.comps {
+		if c.Type == component.MessageQueue && len(g.adj[c.ID]) == 0 {
+			issues = append(issues, Issue{
+				RuleID:       r.ID,
+				Severity:     r.Severity,
+				Params:       map[string]interface{}{"component": displayName(c)},
+				ComponentIDs: []string{c.ID},
+			})
+		}
+	}
+	return issues
+}
+
+func checkASGWithoutLB(r Rule, g *graph) []Issue {
+	var issues []Issue
+	for _, c := range g.comps {
+		if c.Type != component.AutoScalingGroup {
+			continue
+		}
+		hasLB := false
+		for _, prev := range g.reverse[c.ID] {
+			if g.byID[prev].Type == component.LoadBalancer {
+				hasLB = true
+				break
+			}
+		}
+		if !hasLB {
+			issues = append(issues, Issue{
+				RuleID:       r.ID,
+				Severity:     r.Severity,
+				Params:       map[string]interface{}{"component": displayName(c)},
+				ComponentIDs: []string{c.ID},
+			})
+		}
+	}
+	return issues
+}
+
+func sortedIDs(ids []string) []string {
+	out := append([]string(nil), ids...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"reflect"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"testing"
+)
+
+// node 描述一個測試用的組件
+type node struct {
+	id    string
+	t     component.Type
+	props component.Metadata
+}
+
+// build 以組件與 {from, to} 連線建立設計圖
+func build(nodes []node, conns ...[2]string) *design.Design {
+	d := &design.Design{ID: "test"}
+	for _, n := range nodes {
+		props := n.props
+		if props == nil {
+			props = component.Metadata{}
+		}
+		d.Components = append(d.Components, component.Component{ID: n.id, Name: n.id, Type: n.t, Properties: props})
+	}
+	for _, c := range conns {
+		d.Connections = append(d.Connections, design.Connection{FromID: c[0], ToID: c[1]})
+	}
+	return d
+}
+
+func ruleByID(t *testing.T, id string) Rule {
+	t.Helper()
+	for _, r := range DefaultRules() {
+		if r.ID == id {
+			return r
+		}
+	}
+	t.Fatalf("rule %s not found", id)
+	return Rule{}
+}
+
+func TestDefaultRules(t *testing.T) {
+	src := node{"src", component.TrafficSource, nil}
+	lb := node{"lb", component.LoadBalancer, nil}
+	waf := node{"waf", component.WAF, nil}
+	web := node{"web", component.WebServer, nil}
+	web2 := node{"web2", component.WebServer, nil}
+	db := node{"db", component.Database, nil}
+	replicatedDB := node{"db", component.Database, component.Metadata{"replication_mode": "MASTER_SLAVE", "slave_count": 1}}
+	scaledWeb := node{"web", component.WebServer, component.Metadata{"auto_scaling": true}}
+
+	tests := []struct {
+		name   string
+		rule   string
+		design *design.Design
+		want   [][]string // 每筆建議的 ComponentIDs，nil 代表沒有建議
+	}{
+		{
+			name:   "single point of failure",
+			rule:   "single-point-of-failure",
+			design: build([]node{src, waf, scaledWeb, db}, [2]string{"src", "waf"}, [2]string{"waf", "web"}, [2]string{"web", "db"}),
+			want:   [][]string{{"db"}},
+		},
+		{
+			name:   "redundant path and replicated database",
+			rule:   "single-point-of-failure",
+			design: build([]node{src, lb, web, web2, replicatedDB}, [2]string{"src", "lb"}, [2]string{"lb", "web"}, [2]string{"lb", "web2"}, [2]string{"web", "db"}, [2]string{"web2", "db"}),
+		},
+		{
+			name:   "database reachable from traffic source",
+			rule:   "db-exposed-to-traffic",
+			design: build([]node{src, db}, [2]string{"src", "db"}),
+			want:   [][]string{{"src", "db"}},
+		},
+		{
+			name:   "database behind a server",
+			rule:   "db-exposed-to-traffic",
+			design: build([]node{src, web, db}, [2]string{"src", "web"}, [2]string{"web", "db"}),
+		},
+		{
+			name:   "no WAF or gateway",
+			rule:   "unprotected-entry-point",
+			design: build([]node{src, lb, web, db}, [2]string{"src", "lb"}, [2]string{"lb", "web"}, [2]string{"web", "db"}),
+			want:   [][]string{{"src", "db", "web"}},
+		},
+		{
+			name:   "behind a WAF",
+			rule:   "unprotected-entry-point",
+			design: build([]node{src, waf, web, db}, [2]string{"src", "waf"}, [2]string{"waf", "web"}, [2]string{"web", "db"}),
+		},
+		{
+			name:   "cache with nothing behind it",
+			rule:   "cache-without-backing-store",
+			design: build([]node{src, {"cache", component.Cache, nil}}, [2]string{"src", "cache"}),
+			want:   [][]string{{"cache"}},
+		},
+		{
+			name:   "cache in front of a database",
+			rule:   "cache-without-backing-store",
+			design: build([]node{src, {"cache", component.Cache, nil}, db}, [2]string{"src", "cache"}, [2]string{"cache", "db"}),
+		},
+		{
+			name:   "queue without consumer",
+			rule:   "mq-without-consumer",
+			design: build([]node{src, web, {"mq", component.MessageQueue, nil}}, [2]string{"src", "web"}, [2]string{"web", "mq"}),
+			want:   [][]string{{"mq"}},
+		},
+		{
+			name:   "queue with a worker",
+			rule:   "mq-without-consumer",
+			design: build([]node{src, web, {"mq", component.MessageQueue, nil}, {"worker", component.Worker, nil}}, [2]string{"src", "web"}, [2]string{"web", "mq"}, [2]string{"mq", "worker"}),
+		},
+		{
+			name:   "auto scaling group without load balancer",
+			rule:   "asg-without-load-balancer",
+			design: build([]node{src, {"asg", component.AutoScalingGroup, nil}}, [2]string{"src", "asg"}),
+			want:   [][]string{{"asg"}},
+		},
+		{
+			name:   "auto scaling group behind a load balancer",
+			rule:   "asg-without-load-balancer",
+			design: build([]node{src, lb, {"asg", component.AutoScalingGroup, nil}}, [2]string{"src", "lb"}, [2]string{"lb", "asg"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := ruleByID(t, tt.rule)
+			issues := NewLinter(r).Lint(tt.design)
+
+			var got [][]string
+			for _, issue := range issues {
+				if issue.RuleID != tt.rule || issue.Severity != r.Severity || issue.Message == "" {
+					t.Errorf("issue = %+v, want a localized %s issue with severity %s", issue, tt.rule, r.Severity)
+				}
+				got = append(got, issue.ComponentIDs)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("component ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package scenario
 
+import (
+	"strconv"
+	"system-design-game/internal/i18n"
+)
+
 // Scenario 代表一個遊戲關卡或挑戰情境
 type Scenario struct {
 	ID          string         `json:"id"`
@@ -34,6 +39,22 @@ type Constraint struct {
 
 // Repository 定義 Scenario 的持久化介面
 type Repository interface {
+	Save(s *Scenario) error
 	GetByID(id string) (*Scenario, error)
 	ListAll() ([]*Scenario, error)
 }
+
+// Localized 回傳依語系翻譯標題、說明與階段名稱後的副本
+// 沒有對應翻譯的欄位 (如講師自訂的關卡) 保留原文
+func (s *Scenario) Localized(l i18n.Locale) *Scenario {
+	out := *s
+	prefix := "scenario." + s.ID + "."
+	out.Title = i18n.TOr(l, prefix+"title", s.Title, nil)
+	out.Description = i18n.TOr(l, prefix+"description", s.Description, nil)
+	out.Phases = make([]TrafficPhase, len(s.Phases))
+	for i, p := range s.Phases {
+		p.Name = i18n.TOr(l, prefix+"phase."+strconv.Itoa(i), p.Name, nil)
+		out.Phases[i] = p
+	}
+	return &out
+}
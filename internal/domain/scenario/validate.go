@@ -0,0 +1,144 @@
+package scenario
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"system-design-game/internal/i18n"
+)
+
+// 限制條件類型
+const (
+	ConstraintBudget          = "budget"           // 每秒運作成本的預算上限 ($/s)
+	ConstraintStartingBalance = "starting_balance" // 經濟模式購買組件的起始資金 ($)
+)
+
+// knownConstraints 是引擎認得的限制條件類型
+var knownConstraints = map[string]bool{
+	ConstraintBudget:          true,
+	ConstraintStartingBalance: true,
+}
+
+// MaxDurationSeconds 是關卡總長度的上限 (6 小時)，與引擎一場模擬最多推進的 tick 數相同
+const MaxDurationSeconds = 21600
+
+// ErrNotFound 表示關卡不存在，Repository 回傳的錯誤可用 errors.Is 判斷
+var ErrNotFound = errors.New("scenario not found")
+
+// ErrAlreadyExists 表示新增的關卡 ID 已被使用
+var ErrAlreadyExists = errors.New("scenario already exists")
+
+// FieldError 是一筆關卡驗證錯誤，Field 為 JSON 路徑 (如 phases[1].duration_seconds)
+type FieldError struct {
+	Field     string                 `json:"field"`
+	MessageID string                 `json:"message_id"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Message   string                 `json:"message"`
+}
+
+// ValidationError 彙整關卡的所有驗證錯誤
+type ValidationError struct {
+	ScenarioID string       `json:"scenario_id,omitempty"`
+	Errors     []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Message
+	}
+	return "關卡 " + e.ScenarioID + " 驗證失敗: " + strings.Join(msgs, "; ")
+}
+
+// Localize 依語系產生每筆錯誤的訊息
+func (e *ValidationError) Localize(l i18n.Locale) {
+	for i, fe := range e.Errors {
+		params := map[string]interface{}{"field": fe.Field}
+		for k, v := range fe.Params {
+			params[k] = v
+		}
+		e.Errors[i].Message = i18n.T(l, fe.MessageID, params)
+	}
+}
+
+// Validate 檢查關卡定義：ID 與標題必填、至少一個流量階段、持續時間為正數且總長不超過 MaxDurationSeconds、
+// QPS 與目標不可為負、限制條件必須是引擎認得的類型
+// 錯誤訊息以預設語系產生，需要其他語系時呼叫 Localize
+func (s *Scenario) Validate() error {
+	var errs []FieldError
+	add := func(field, messageID string, params map[string]interface{}) {
+		errs = append(errs, FieldError{Field: field, MessageID: messageID, Params: params})
+	}
+
+	if strings.TrimSpace(s.ID) == "" {
+		add("id", "scenario.required", nil)
+	}
+	if strings.TrimSpace(s.Title) == "" {
+		add("title", "scenario.required", nil)
+	}
+
+	if s.Goal.Duration <= 0 {
+		add("goal.duration", "scenario.positive", nil)
+	} else if s.Goal.Duration > MaxDurationSeconds {
+		add("goal.duration", "scenario.too_long", map[string]interface{}{"max": MaxDurationSeconds})
+	}
+	if s.Goal.MinQPS < 0 {
+		add("goal.min_qps", "scenario.non_negative", nil)
+	}
+	if s.Goal.MaxLatencyMS < 0 {
+		add("goal.max_latency_ms", "scenario.non_negative", nil)
+	}
+	if s.Goal.Availability < 0 || s.Goal.Availability > 100 {
+		add("goal.availability", "scenario.percentage", nil)
+	}
+
+	if len(s.Phases) == 0 {
+		add("phases", "scenario.no_phases", nil)
+	}
+	var total int64
+	for i, p := range s.Phases {
+		prefix := "phases[" + strconv.Itoa(i) + "]."
+		if p.DurationSeconds <= 0 {
+			add(prefix+"duration_seconds", "scenario.positive", nil)
+		} else {
+			total += int64(p.DurationSeconds)
+		}
+		if p.StartQPS < 0 {
+			add(prefix+"start_qps", "scenario.non_negative", nil)
+		}
+		if p.EndQPS < 0 {
+			add(prefix+"end_qps", "scenario.non_negative", nil)
+		}
+	}
+	if total > MaxDurationSeconds {
+		add("phases", "scenario.too_long", map[string]interface{}{"max": MaxDurationSeconds})
+	}
+
+	for i, c := range s.Constraints {
+		prefix := "constraints[" + strconv.Itoa(i) + "]."
+		if !knownConstraints[c.Type] {
+			add(prefix+"type", "scenario.unknown_constraint", map[string]interface{}{"type": c.Type, "known": knownConstraintList()})
+		}
+		if c.Value < 0 {
+			add(prefix+"value", "scenario.non_negative", nil)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	ve := &ValidationError{ScenarioID: s.ID, Errors: errs}
+	ve.Localize(i18n.DefaultLocale)
+	return ve
+}
+
+// knownConstraintList 以逗號分隔列出認得的限制條件類型
+func knownConstraintList() string {
+	types := make([]string, 0, len(knownConstraints))
+	for t := range knownConstraints {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return strings.Join(types, ", ")
+}
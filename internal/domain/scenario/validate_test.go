@@ -0,0 +1,59 @@
+package scenario
+
+import (
+	"errors"
+	"testing"
+)
+
+func validScenario() *Scenario {
+	return &Scenario{
+		ID:     "test",
+		Title:  "Test",
+		Goal:   Goal{Duration: 60},
+		Phases: []TrafficPhase{{Name: "steady", StartQPS: 100, EndQPS: 100, DurationSeconds: 60}},
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *Scenario)
+		fields []string
+	}{
+		{"valid", func(s *Scenario) {}, nil},
+		{"at the limit", func(s *Scenario) {
+			s.Goal.Duration = MaxDurationSeconds
+			s.Phases[0].DurationSeconds = MaxDurationSeconds
+		}, nil},
+		{"goal too long", func(s *Scenario) { s.Goal.Duration = MaxDurationSeconds + 1 }, []string{"goal.duration"}},
+		{"phases too long in total", func(s *Scenario) {
+			s.Phases = append(s.Phases, TrafficPhase{Name: "long", DurationSeconds: MaxDurationSeconds})
+		}, []string{"phases"}},
+		{"non-positive phase", func(s *Scenario) { s.Phases[0].DurationSeconds = 0 }, []string{"phases[0].duration_seconds"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validScenario()
+			tt.modify(s)
+			err := s.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("err = %v, want *ValidationError", err)
+			}
+			if len(ve.Errors) != len(tt.fields) {
+				t.Fatalf("errors = %+v, want fields %v", ve.Errors, tt.fields)
+			}
+			for i, f := range tt.fields {
+				if ve.Errors[i].Field != f {
+					t.Errorf("field = %q, want %q", ve.Errors[i].Field, f)
+				}
+			}
+		})
+	}
+}
@@ -1,17 +1,30 @@
 package world
 
+import "errors"
+
+// 無盡模式的經濟參數
+const (
+	StartingBalance   = 1000.0 // 新遊戲 (或破產後重新開始) 的初始金幣
+	RevenuePerRequest = 0.01   // 每個成功取得資料的請求帶來的收益 (每 100 個請求 1 元)
+)
+
 // GameState 代表玩家目前的遊戲狀態（無盡模式核心）
 type GameState struct {
 	PlayerID     string  `json:"player_id"`
 	Balance      float64 `json:"balance"`       // 目前的金幣/預算
 	TotalUsers   int64   `json:"total_users"`   // 累積獲得的使用者
 	SystemHealth float64 `json:"system_health"` // 0-100，根據延遲與錯誤率計算
-	Uptime       int64   `json:"uptime"`        // 系統總運行秒數
+	Uptime       float64 `json:"uptime"`        // 系統總運行秒數
 	LastTick     int64   `json:"last_tick"`     // 上次計算的時間戳
 }
 
-// UpdateMetrics 根據當前系統表現更新遊戲狀態
-func (s *GameState) UpdateMetrics(avgLatency float64, errorRate float64, qps int64) {
+// NewGameState 建立玩家的新遊戲狀態
+func NewGameState(playerID string) *GameState {
+	return &GameState{PlayerID: playerID, Balance: StartingBalance, SystemHealth: 100}
+}
+
+// UpdateMetrics 根據這段時間 (seconds 秒) 的系統表現更新遊戲狀態，回傳每秒收益
+func (s *GameState) UpdateMetrics(avgLatency float64, errorRate float64, fulfilledQPS int64, seconds float64) float64 {
 	// 健康度計算邏輯：延遲越高、錯誤越多，健康度下降
 	health := 100.0
 	if avgLatency > 500 {
@@ -24,18 +37,27 @@ func (s *GameState) UpdateMetrics(avgLatency float64, errorRate float64, qps int
 	}
 	s.SystemHealth = health
 
-	// 根據成功的 QPS 增加使用者與收益
-	successQPS := float64(qps) * (1.0 - errorRate)
-	s.Balance += successQPS * 0.01 // 假設每 100 個成功請求賺 1 元
-	s.TotalUsers += int64(successQPS)
+	// 根據成功取得資料的 QPS 增加使用者與收益
+	revenue := float64(fulfilledQPS) * RevenuePerRequest
+	s.Balance += revenue * seconds
+	s.TotalUsers += int64(float64(fulfilledQPS) * seconds)
+	return revenue
 }
 
 // DeductCost 扣除運作成本
-func (s *GameState) DeductCost(costPerSecond float64, durationSeconds int64) {
-	s.Balance -= costPerSecond * float64(durationSeconds)
-	s.Uptime += durationSeconds
+func (s *GameState) DeductCost(costPerSecond float64, seconds float64) {
+	s.Balance -= costPerSecond * seconds
+	s.Uptime += seconds
 }
 
+// Bankrupt 判斷玩家是否已經破產 (金幣小於 0)，破產後本次遊戲結束
+func (s *GameState) Bankrupt() bool {
+	return s.Balance < 0
+}
+
+// ErrNotFound 表示玩家還沒有遊戲狀態，Repository 回傳的錯誤可用 errors.Is 判斷
+var ErrNotFound = errors.New("game state not found")
+
 // Repository 定義狀態存取介面
 type Repository interface {
 	Save(state *GameState) error
@@ -0,0 +1,71 @@
+package world
+
+import (
+	"math"
+	"testing"
+)
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestUpdateMetrics(t *testing.T) {
+	cases := []struct {
+		name       string
+		latency    float64
+		errorRate  float64
+		qps        int64
+		seconds    float64
+		wantHealth float64
+		wantUsers  int64
+	}{
+		{"healthy second", 100, 0, 1000, 1, 100, 1000},
+		{"slow and failing", 800, 0.2, 1000, 1, 100 - 30 - 20, 1000},
+		{"health floors at 0", 5000, 1, 1000, 1, 0, 1000},
+		// tick 長度縮放收益與使用者，每秒收益不變
+		{"half-second tick", 100, 0, 1000, 0.5, 100, 500},
+		{"ten-second tick", 100, 0, 1000, 10, 100, 10000},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewGameState("p")
+			perSec := s.UpdateMetrics(tc.latency, tc.errorRate, tc.qps, tc.seconds)
+			assertClose(t, "revenue per second", perSec, float64(tc.qps)*RevenuePerRequest)
+			assertClose(t, "balance", s.Balance, StartingBalance+perSec*tc.seconds)
+			assertClose(t, "system health", s.SystemHealth, tc.wantHealth)
+			if s.TotalUsers != tc.wantUsers {
+				t.Errorf("TotalUsers = %d, want %d", s.TotalUsers, tc.wantUsers)
+			}
+			if s.Uptime != 0 {
+				t.Errorf("UpdateMetrics should not change Uptime, got %v", s.Uptime)
+			}
+		})
+	}
+}
+
+// 成本與運行秒數依 tick 長度累計，短於 1 秒的 tick 也會被計入 (Uptime 為 float64)
+func TestDeductCostAndBankruptcy(t *testing.T) {
+	s := NewGameState("p")
+	for i := 0; i < 10; i++ {
+		s.DeductCost(50, 0.1)
+	}
+	assertClose(t, "uptime", s.Uptime, 1)
+	assertClose(t, "balance", s.Balance, StartingBalance-50)
+	if s.Bankrupt() {
+		t.Fatal("a positive balance is not bankrupt")
+	}
+
+	s.DeductCost(950, 1)
+	assertClose(t, "balance", s.Balance, 0)
+	if s.Bankrupt() {
+		t.Error("a zero balance is not bankrupt")
+	}
+	s.DeductCost(1, 0.5)
+	assertClose(t, "uptime", s.Uptime, 2.5)
+	if !s.Bankrupt() {
+		t.Errorf("balance %v should be bankrupt", s.Balance)
+	}
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"net/http"
+	"system-design-game/internal/application/usecase"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/lint"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalysisHandler 處理設計分析相關的 HTTP 請求
+type AnalysisHandler struct {
+	analysisUC *usecase.AnalysisUseCase
+}
+
+// NewAnalysisHandler 建立新的 AnalysisHandler
+func NewAnalysisHandler(auc *usecase.AnalysisUseCase) *AnalysisHandler {
+	return &AnalysisHandler{
+		analysisUC: auc,
+	}
+}
+
+// Analyze 產生指定秒數的瓶頸與根因分析報告 (?elapsed=30)
+func (h *AnalysisHandler) Analyze(c *gin.Context) {
+	elapsed, ok := parseElapsed(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.analysisUC.Analyze(c.Param("design_id"), elapsed)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	report.Localize(Locale(c))
+	c.JSON(http.StatusOK, report)
+}
+
+// Lint 以靜態架構規則檢查已儲存的設計圖
+func (h *AnalysisHandler) Lint(c *gin.Context) {
+	issues, err := h.analysisUC.Lint(c.Param("design_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	lint.Localize(issues, Locale(c))
+	c.JSON(http.StatusOK, gin.H{"issues": issues})
+}
+
+// LintDesign 以靜態架構規則檢查請求中的設計圖 (不需事先儲存)
+func (h *AnalysisHandler) LintDesign(c *gin.Context) {
+	var d design.Design
+	if err := c.ShouldBindJSON(&d); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_design")})
+		return
+	}
+	issues := h.analysisUC.LintDesign(&d)
+	lint.Localize(issues, Locale(c))
+	c.JSON(http.StatusOK, gin.H{"issues": issues})
+}
+
+// LintRules 列出所有架構規則
+func (h *AnalysisHandler) LintRules(c *gin.Context) {
+	c.JSON(http.StatusOK, lint.LocalizeRules(h.analysisUC.LintRules(), Locale(c)))
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+	"system-design-game/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogHandler 處理組件目錄相關的 HTTP 請求
+type CatalogHandler struct {
+	catalogUC *usecase.CatalogUseCase
+}
+
+// NewCatalogHandler 建立新的 CatalogHandler
+func NewCatalogHandler(cuc *usecase.CatalogUseCase) *CatalogHandler {
+	return &CatalogHandler{
+		catalogUC: cuc,
+	}
+}
+
+// List 列出所有可購買的組件規格 (SKU)
+func (h *CatalogHandler) List(c *gin.Context) {
+	skus, err := h.catalogUC.ListComponents()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	l := Locale(c)
+	for i, s := range skus {
+		skus[i] = s.Localized(l)
+	}
+	c.JSON(http.StatusOK, skus)
+}
@@ -1,9 +1,14 @@
 package http
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"system-design-game/internal/application/usecase"
+	"system-design-game/internal/domain/component"
 	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/evaluation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,30 +27,218 @@ func NewDesignHandler(duc *usecase.DesignUseCase, euc *usecase.EvaluationUseCase
 	}
 }
 
-// Evaluate 評估設計圖
+// Evaluate 評估設計圖在第 elapsed 秒的狀態 (?elapsed=30；?mode=strict 以伺服器端目錄決定組件規格)
 func (h *DesignHandler) Evaluate(c *gin.Context) {
 	id := c.Param("design_id")
-	// 這裡可以傳入時間軸參數，目前先預設為 0
-	res, err := h.evalUC.Evaluate(id, 0)
+	evaluate := h.evalUC.Evaluate
+	switch evaluation.Mode(c.DefaultQuery("mode", string(evaluation.ModeSandbox))) {
+	case evaluation.ModeSandbox:
+	case evaluation.ModeStrict:
+		evaluate = h.evalUC.EvaluateStrict
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_mode")})
+		return
+	}
+
+	elapsed, ok := parseElapsed(c)
+	if !ok {
+		return
+	}
+
+	res, err := evaluate(id, elapsed)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
+	res.Localize(Locale(c))
 	c.JSON(http.StatusOK, res)
 }
 
+// MonteCarlo 以多次隨機模擬評估設計圖 (?runs=50&seed=123)
+func (h *DesignHandler) MonteCarlo(c *gin.Context) {
+	opts, ok := parseMonteCarloOptions(c)
+	if !ok {
+		return
+	}
+	report, err := h.evalUC.MonteCarlo(c.Request.Context(), c.Param("design_id"), opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Compare 比較兩個設計圖的蒙地卡羅評估結果 (?a=design1&b=design2&runs=50)
+func (h *DesignHandler) Compare(c *gin.Context) {
+	a, b := c.Query("a"), c.Query("b")
+	if a == "" || b == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.compare_ids")})
+		return
+	}
+	opts, ok := parseMonteCarloOptions(c)
+	if !ok {
+		return
+	}
+	cmp, err := h.evalUC.Compare(c.Request.Context(), a, b, opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cmp)
+}
+
+// CapacityLimit 搜尋設計圖的容量極限 (?min_fulfillment=0.95&max_qps=1000000)
+func (h *DesignHandler) CapacityLimit(c *gin.Context) {
+	var opts engine.CapacityOptions
+	if v := c.Query("min_fulfillment"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 || f > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_min_fulfillment")})
+			return
+		}
+		opts.MinFulfillment = f
+	}
+	if v := c.Query("max_qps"); v != "" {
+		q, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || q <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_max_qps")})
+			return
+		}
+		opts.MaxQPS = q
+	}
+
+	report, err := h.evalUC.CapacityLimit(c.Param("design_id"), opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Verify 以伺服器重新模擬客戶端提交的成績 (設計圖、種子與玩家操作)，回傳權威成績並標記不一致的欄位
+func (h *DesignHandler) Verify(c *gin.Context) {
+	var req engine.VerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_verification")})
+		return
+	}
+	report, err := h.evalUC.Verify(req)
+	if err != nil {
+		if errors.Is(err, engine.ErrInvalidVerification) {
+			c.JSON(http.StatusBadRequest, errorBody(c, "error.invalid_verification", err))
+			return
+		}
+		respondError(c, err)
+		return
+	}
+	report.Localize(Locale(c))
+	c.JSON(http.StatusOK, report)
+}
+
+// parseMonteCarloOptions 解析蒙地卡羅評估的查詢參數，格式錯誤時直接回應 400
+func parseMonteCarloOptions(c *gin.Context) (engine.MonteCarloOptions, bool) {
+	var opts engine.MonteCarloOptions
+	if v := c.Query("runs"); v != "" {
+		runs, err := strconv.Atoi(v)
+		if err != nil || runs <= 0 || runs > engine.MaxMonteCarloRuns {
+			c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_runs")})
+			return opts, false
+		}
+		opts.Runs = runs
+	}
+	if v := c.Query("seed"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_seed")})
+			return opts, false
+		}
+		opts.Seed = seed
+	}
+	return opts, true
+}
+
 // Save 儲存設計圖 (Server 範例)
 func (h *DesignHandler) Save(c *gin.Context) {
 	var d design.Design
 	if err := c.ShouldBindJSON(&d); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的設計圖格式"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_design")})
 		return
 	}
 
 	if err := h.designUC.SaveDesign(&d); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.saveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": t(c, "http.design_saved"), "id": d.ID})
+}
+
+// Get 取得單一設計圖
+func (h *DesignHandler) Get(c *gin.Context) {
+	d, err := h.designUC.GetDesign(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// ListByPlayer 列出玩家的所有設計圖
+func (h *DesignHandler) ListByPlayer(c *gin.Context) {
+	designs, err := h.designUC.ListDesigns(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if designs == nil {
+		designs = []*design.Design{}
+	}
+	c.JSON(http.StatusOK, designs)
+}
+
+// Update 覆寫既有的設計圖 (以路徑上的 ID 為準)
+func (h *DesignHandler) Update(c *gin.Context) {
+	var d design.Design
+	if err := c.ShouldBindJSON(&d); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_design")})
+		return
+	}
+
+	if err := h.designUC.UpdateDesign(c.Param("id"), &d); err != nil {
+		h.saveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": t(c, "http.design_saved"), "id": d.ID})
+}
+
+// Delete 刪除設計圖
+func (h *DesignHandler) Delete(c *gin.Context) {
+	if err := h.designUC.DeleteDesign(c.Param("id")); err != nil {
+		respondError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": t(c, "http.design_deleted"), "id": c.Param("id")})
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "設計圖儲存成功 (Server)", "id": d.ID})
+// saveError 回應儲存設計圖失敗：屬性驗證錯誤為 400 並列出每一筆錯誤
+func (h *DesignHandler) saveError(c *gin.Context, err error) {
+	var ve *component.ValidationError
+	if errors.As(err, &ve) {
+		ve.Localize(Locale(c))
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_properties"), "errors": ve.Errors})
+		return
+	}
+	respondError(c, err)
+}
+
+// Schemas 列出各組件類型與設計圖全域屬性的定義
+func (h *DesignHandler) Schemas(c *gin.Context) {
+	l := Locale(c)
+	schemas := h.designUC.PropertySchemas()
+	for i, s := range schemas.Components {
+		schemas.Components[i] = s.Localized(l)
+	}
+	schemas.Design = schemas.Design.Localized(l)
+	c.JSON(http.StatusOK, schemas)
 }
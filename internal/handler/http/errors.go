@@ -0,0 +1,61 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"system-design-game/internal/domain/catalog"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/scenario"
+	"system-design-game/internal/domain/world"
+	"system-design-game/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError 將用例回傳的錯誤對應到 HTTP 狀態碼：
+// 設計圖、關卡或遊戲狀態不存在為 404、SKU 錯誤與經濟模式資金不足為 400，其餘為 500
+// (內部錯誤的訊息只記錄在伺服器日誌，不回傳給客戶端)
+func respondError(c *gin.Context, err error) {
+	var skuErr *catalog.SKUError
+	switch {
+	case errors.Is(err, design.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": t(c, "error.design_not_found")})
+	case errors.Is(err, scenario.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": t(c, "error.scenario_not_found")})
+	case errors.Is(err, world.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": t(c, "error.game_state_not_found")})
+	case errors.Is(err, engine.ErrInsufficientFunds):
+		c.JSON(http.StatusBadRequest, errorBody(c, "error.insufficient_funds", err))
+	case errors.As(err, &skuErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(Locale(c), skuErr.MessageID, skuErr.Params())})
+	default:
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": t(c, "error.internal")})
+	}
+}
+
+// errorBody 產生錯誤回應：error 為 messageID 的訊息，錯誤帶有原因 (*engine.ReasonError) 時以 reason 附上依語系產生的原因
+func errorBody(c *gin.Context, messageID string, err error) gin.H {
+	body := gin.H{"error": t(c, messageID)}
+	var re *engine.ReasonError
+	if errors.As(err, &re) {
+		body["reason"] = re.Message(Locale(c))
+	}
+	return body
+}
+
+// parseElapsed 解析 ?elapsed= (模擬經過的秒數，預設 0)，格式錯誤時直接回應 400
+func parseElapsed(c *gin.Context) (int64, bool) {
+	v := c.Query("elapsed")
+	if v == "" {
+		return 0, true
+	}
+	elapsed, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || elapsed < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_elapsed")})
+		return 0, false
+	}
+	return elapsed, true
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"system-design-game/internal/application/usecase"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/domain/leaderboard"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeaderboardHandler 處理排行榜相關的 HTTP 請求
+type LeaderboardHandler struct {
+	leaderboardUC *usecase.LeaderboardUseCase
+}
+
+// NewLeaderboardHandler 建立新的 LeaderboardHandler
+func NewLeaderboardHandler(luc *usecase.LeaderboardUseCase) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardUC: luc,
+	}
+}
+
+// List 列出關卡的排行榜 (?sort=score|cost|latency|components&limit=20)
+func (h *LeaderboardHandler) List(c *gin.Context) {
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_limit")})
+			return
+		}
+		limit = n
+	}
+
+	standings, err := h.leaderboardUC.Leaderboard(c.Param("id"), leaderboard.SortKey(c.Query("sort")), limit)
+	if err != nil {
+		if errors.Is(err, leaderboard.ErrInvalidSort) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_leaderboard_sort")})
+			return
+		}
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, standings)
+}
+
+// Submit 提交一場遊戲 (格式與 POST /verify 相同，設計圖需有 player_id)，伺服器驗證通過後才記錄到排行榜
+// 客戶端回報的成績與伺服器不一致時回應 422，並附上驗證報告
+func (h *LeaderboardHandler) Submit(c *gin.Context) {
+	var req engine.VerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_verification")})
+		return
+	}
+	sub, err := h.leaderboardUC.Submit(c.Param("id"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrMissingPlayer):
+			c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.missing_player")})
+		case errors.Is(err, engine.ErrInvalidVerification):
+			c.JSON(http.StatusBadRequest, errorBody(c, "error.invalid_verification", err))
+		default:
+			respondError(c, err)
+		}
+		return
+	}
+	sub.Verification.Localize(Locale(c))
+	if !sub.Verification.Verified {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": t(c, "error.score_mismatch"), "verification": sub.Verification})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
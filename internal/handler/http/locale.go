@@ -0,0 +1,20 @@
+package http
+
+import (
+	"system-design-game/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale 取得請求的語系：?lang= 優先，其次為 Accept-Language 標頭
+func Locale(c *gin.Context) i18n.Locale {
+	if lang := c.Query("lang"); lang != "" {
+		return i18n.Parse(lang)
+	}
+	return i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// t 依請求的語系產生訊息
+func t(c *gin.Context, id string) string {
+	return i18n.T(Locale(c), id, nil)
+}
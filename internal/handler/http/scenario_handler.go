@@ -1,8 +1,10 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"system-design-game/internal/application/usecase"
+	"system-design-game/internal/domain/scenario"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,8 +25,66 @@ func NewScenarioHandler(suc *usecase.ScenarioUseCase) *ScenarioHandler {
 func (h *ScenarioHandler) List(c *gin.Context) {
 	scenarios, err := h.scenarioUC.ListScenarios()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
+	l := Locale(c)
+	for i, s := range scenarios {
+		scenarios[i] = s.Localized(l)
+	}
 	c.JSON(http.StatusOK, scenarios)
 }
+
+// Get 取得單一關卡
+func (h *ScenarioHandler) Get(c *gin.Context) {
+	s, err := h.scenarioUC.GetScenario(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s.Localized(Locale(c)))
+}
+
+// Create 新增關卡 (講師自訂挑戰)，ID 已存在時回應 409
+func (h *ScenarioHandler) Create(c *gin.Context) {
+	var s scenario.Scenario
+	if err := c.ShouldBindJSON(&s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_scenario")})
+		return
+	}
+	if err := h.scenarioUC.CreateScenario(&s); err != nil {
+		if errors.Is(err, scenario.ErrAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": t(c, "error.scenario_exists")})
+			return
+		}
+		h.saveError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": t(c, "http.scenario_saved"), "id": s.ID})
+}
+
+// Update 新增或覆寫指定 ID 的關卡 (以路徑上的 ID 為準)
+func (h *ScenarioHandler) Update(c *gin.Context) {
+	var s scenario.Scenario
+	if err := c.ShouldBindJSON(&s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_scenario")})
+		return
+	}
+	s.ID = c.Param("id")
+	if err := h.scenarioUC.SaveScenario(&s); err != nil {
+		h.saveError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": t(c, "http.scenario_saved"), "id": s.ID})
+}
+
+// saveError 回應儲存關卡失敗：驗證錯誤為 400 並列出每一筆錯誤
+func (h *ScenarioHandler) saveError(c *gin.Context, err error) {
+	var ve *scenario.ValidationError
+	if errors.As(err, &ve) {
+		ve.Localize(Locale(c))
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_scenario"), "errors": ve.Errors})
+		return
+	}
+	respondError(c, err)
+}
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"system-design-game/internal/application/usecase"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/engine"
+	"system-design-game/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// errForbiddenOrigin 是 WebSocket 連線的 Origin 不在允許範圍內的錯誤 (回應 403)
+var errForbiddenOrigin = errors.New("websocket origin not allowed")
+
+// SessionHandler 處理伺服器端即時模擬場次的 HTTP 與 WebSocket 請求
+type SessionHandler struct {
+	sessionUC      *usecase.SessionUseCase
+	evalUC         *usecase.EvaluationUseCase
+	allowedOrigins map[string]bool
+}
+
+// NewSessionHandler 建立新的 SessionHandler
+// allowedOrigins 是除了與伺服器同源之外，允許建立 WebSocket 連線的來源 (如 "https://game.example.com")
+func NewSessionHandler(suc *usecase.SessionUseCase, euc *usecase.EvaluationUseCase, allowedOrigins ...string) *SessionHandler {
+	origins := make(map[string]bool, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		origins[strings.TrimSuffix(strings.ToLower(o), "/")] = true
+	}
+	return &SessionHandler{
+		sessionUC:      suc,
+		evalUC:         euc,
+		allowedOrigins: origins,
+	}
+}
+
+// startSessionRequest 是開始場次的請求內容
+type startSessionRequest struct {
+	DesignID string  `json:"design_id" binding:"required"`
+	Speed    float64 `json:"speed"`
+	DT       float64 `json:"dt"`
+}
+
+// Start 以已儲存的設計圖開始一場模擬
+func (h *SessionHandler) Start(c *gin.Context) {
+	var req startSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_session")})
+		return
+	}
+	s, err := h.sessionUC.StartSession(req.DesignID, usecase.SessionOptions{Speed: req.Speed, TickSeconds: req.DT})
+	if err != nil {
+		h.controlError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, s.State())
+}
+
+// Get 取得場次目前的狀態
+func (h *SessionHandler) Get(c *gin.Context) {
+	s, err := h.sessionUC.GetSession(c.Param("id"))
+	if err != nil {
+		h.controlError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s.State())
+}
+
+// Stop 結束場次
+func (h *SessionHandler) Stop(c *gin.Context) {
+	if err := h.sessionUC.StopSession(c.Param("id")); err != nil {
+		h.controlError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": t(c, "http.session_stopped"), "id": c.Param("id")})
+}
+
+// Control 以 HTTP 送出控制指令 (與 WebSocket 上的控制訊息相同)
+func (h *SessionHandler) Control(c *gin.Context) {
+	s, err := h.sessionUC.GetSession(c.Param("id"))
+	if err != nil {
+		h.controlError(c, err)
+		return
+	}
+	var msg usecase.SessionControl
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_control")})
+		return
+	}
+	if err := s.Control(msg); err != nil {
+		h.controlError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s.State())
+}
+
+// GameState 取得玩家在無盡模式保存的遊戲狀態 (金幣、使用者、健康度與運行秒數)
+func (h *SessionHandler) GameState(c *gin.Context) {
+	state, err := h.sessionUC.GameState(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// RunLog 取得場次的執行紀錄 (進行中或最近結束的場次)，可附在問題回報中並以 Replay 重現
+func (h *SessionHandler) RunLog(c *gin.Context) {
+	log, err := h.sessionUC.RunLog(c.Param("id"))
+	if err != nil {
+		h.controlError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, log)
+}
+
+// Costs 依場次的執行紀錄推算每小時、每月與每年的成本、每百萬個成功請求的成本，並依組件類型拆分
+func (h *SessionHandler) Costs(c *gin.Context) {
+	projection, err := h.sessionUC.CostProjection(c.Param("id"))
+	if err != nil {
+		h.controlError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, projection)
+}
+
+// Replay 依上傳的執行紀錄重播模擬，回傳每個 tick 的評估結果與是否與紀錄一致
+func (h *SessionHandler) Replay(c *gin.Context) {
+	var log engine.RunLog
+	if err := c.ShouldBindJSON(&log); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": t(c, "error.invalid_run_log")})
+		return
+	}
+	report, err := h.evalUC.Replay(&log)
+	if err != nil {
+		if errors.Is(err, engine.ErrInvalidRunLog) {
+			c.JSON(http.StatusBadRequest, errorBody(c, "error.invalid_run_log", err))
+			return
+		}
+		respondError(c, err)
+		return
+	}
+	report.Localize(Locale(c))
+	c.JSON(http.StatusOK, report)
+}
+
+// Stream 以 WebSocket 推送每個 tick 的評估結果，同一條連線也接受控制指令：
+//
+//	{"type": "pause"} / {"type": "resume"} / {"type": "step"}
+//	{"type": "speed", "speed": 10} / {"type": "dt", "dt": 0.5}
+//	{"type": "restart", "component_id": "db-1"}
+//	{"type": "set_property", "component_id": "asg-1", "property": "max_replicas", "value": 8}
+//	{"type": "connect", "connection": {"from_id": "lb-1", "to_id": "web-2"}}
+//	{"type": "add_component", "component": {"id": "web-2", "sku": "server-standard"}}
+//
+// 控制指令失敗時只回傳給送出者：{"type": "error", "error": "..."}
+func (h *SessionHandler) Stream(c *gin.Context) {
+	s, err := h.sessionUC.GetSession(c.Param("id"))
+	if err != nil {
+		h.controlError(c, err)
+		return
+	}
+	l := Locale(c)
+
+	server := websocket.Server{Handshake: h.checkOrigin}
+	server.Handler = func(ws *websocket.Conn) {
+		defer ws.Close()
+		sub, err := s.Subscribe(l)
+		if err != nil {
+			websocket.Message.Send(ws, string(errorFrame(controlMessage(l, err))))
+			return
+		}
+		var writeMu sync.Mutex
+		write := func(frame []byte) error {
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			return websocket.Message.Send(ws, string(frame))
+		}
+
+		// 讀取控制指令，連線中斷時取消訂閱 (串流隨之關閉)
+		go func() {
+			defer sub.Cancel()
+			for {
+				var data []byte
+				if err := websocket.Message.Receive(ws, &data); err != nil {
+					return
+				}
+				var msg usecase.SessionControl
+				err := json.Unmarshal(data, &msg)
+				if err == nil {
+					err = s.Control(msg)
+				} else {
+					err = usecase.ErrInvalidControl
+				}
+				if err != nil {
+					write(errorFrame(controlMessage(l, err)))
+				}
+			}
+		}()
+
+		for frame := range sub.C {
+			if err := write(frame); err != nil {
+				sub.Cancel()
+				break
+			}
+		}
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin 拒絕來自其他網站的 WebSocket 連線 (瀏覽器會帶上玩家的 Cookie，任何網頁都能代替玩家控制場次)：
+// Origin 必須與請求的 Host 相同或在允許清單中。沒有 Origin 的請求來自非瀏覽器的客戶端 (CLI、腳本)，直接允許
+func (h *SessionHandler) checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		return nil
+	}
+	if strings.EqualFold(origin.Host, req.Host) || h.allowedOrigins[strings.ToLower(origin.Scheme+"://"+origin.Host)] {
+		config.Origin = origin
+		return nil
+	}
+	return errForbiddenOrigin
+}
+
+// controlMessage 依語系產生場次錯誤的訊息
+func controlMessage(l i18n.Locale, err error) string {
+	var ve *component.ValidationError
+	switch {
+	case errors.Is(err, usecase.ErrSessionNotFound):
+		return i18n.T(l, "error.session_not_found", nil)
+	case errors.Is(err, usecase.ErrPlayerInSession):
+		return i18n.T(l, "error.player_in_session", nil)
+	case errors.Is(err, usecase.ErrInvalidControl):
+		return i18n.T(l, "error.invalid_control", nil)
+	case errors.Is(err, engine.ErrComponentNotFound):
+		return i18n.T(l, "error.component_not_found", nil)
+	case errors.Is(err, engine.ErrComponentNotCrashed):
+		return i18n.T(l, "error.component_not_crashed", nil)
+	case errors.Is(err, engine.ErrInvalidConnection):
+		return i18n.T(l, "error.invalid_connection", nil)
+	case errors.Is(err, engine.ErrInvalidComponent):
+		return i18n.T(l, "error.invalid_component", nil)
+	case errors.Is(err, engine.ErrInsufficientFunds):
+		return i18n.T(l, "error.insufficient_funds", nil)
+	case errors.As(err, &ve):
+		ve.Localize(l)
+		msgs := make([]string, len(ve.Errors))
+		for i, pe := range ve.Errors {
+			msgs[i] = pe.Message
+		}
+		return strings.Join(msgs, "; ")
+	}
+	return i18n.T(l, "error.internal", nil)
+}
+
+func errorFrame(msg string) []byte {
+	data, _ := json.Marshal(gin.H{"type": "error", "error": msg})
+	return data
+}
+
+// controlError 回應場次相關的錯誤：場次不存在為 404，玩家已有進行中的場次為 409，
+// 控制指令、連線、組件、屬性錯誤、重啟未崩潰的組件或資金不足為 400
+func (h *SessionHandler) controlError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": controlMessage(Locale(c), err)})
+	case errors.Is(err, usecase.ErrPlayerInSession):
+		c.JSON(http.StatusConflict, gin.H{"error": controlMessage(Locale(c), err)})
+	case errors.Is(err, usecase.ErrInvalidControl), errors.Is(err, engine.ErrComponentNotFound), errors.Is(err, engine.ErrComponentNotCrashed),
+		errors.Is(err, engine.ErrInvalidConnection), errors.Is(err, engine.ErrInvalidComponent), errors.Is(err, engine.ErrInsufficientFunds):
+		c.JSON(http.StatusBadRequest, gin.H{"error": controlMessage(Locale(c), err)})
+	default:
+		var ve *component.ValidationError
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": controlMessage(Locale(c), err), "errors": ve.Errors})
+			return
+		}
+		respondError(c, err)
+	}
+}
@@ -0,0 +1,120 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Locale 代表一個語系
+type Locale string
+
+const (
+	ZhTW Locale = "zh-TW"
+	En   Locale = "en"
+
+	// DefaultLocale 為預設語系，找不到翻譯時也會退回此語系
+	DefaultLocale = ZhTW
+)
+
+//go:embed bundles/*.json
+var bundleFS embed.FS
+
+// bundles 以語系為 key 保存「訊息 ID -> 訊息模板」
+var bundles = map[Locale]map[string]string{}
+
+func init() {
+	for _, l := range []Locale{ZhTW, En} {
+		data, err := bundleFS.ReadFile("bundles/" + string(l) + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: 無法讀取語系檔 %s: %v", l, err))
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: 語系檔 %s 格式錯誤: %v", l, err))
+		}
+		bundles[l] = messages
+	}
+}
+
+// placeholder 匹配 {name} 或 {name:%.1f} 形式的參數
+var placeholder = regexp.MustCompile(`\{([a-z_]+)(?::(%[^}]+))?\}`)
+
+// T 依語系與訊息 ID 產生訊息，params 會填入模板中的 {name} 參數
+// 找不到該語系的翻譯時退回預設語系，兩者皆無則回傳訊息 ID 本身
+func T(l Locale, id string, params map[string]interface{}) string {
+	tmpl, ok := Lookup(l, id)
+	if !ok {
+		if tmpl, ok = Lookup(DefaultLocale, id); !ok {
+			return id
+		}
+	}
+	return Format(tmpl, params)
+}
+
+// Lookup 只在指定語系中尋找訊息模板
+func Lookup(l Locale, id string) (string, bool) {
+	tmpl, ok := bundles[l][id]
+	return tmpl, ok
+}
+
+// TOr 在指定語系中尋找並格式化訊息，找不到時回傳 fallback (用於玩家或講師自訂的內容)
+func TOr(l Locale, id string, fallback string, params map[string]interface{}) string {
+	if tmpl, ok := Lookup(l, id); ok {
+		return Format(tmpl, params)
+	}
+	return fallback
+}
+
+// Format 將參數填入訊息模板
+func Format(tmpl string, params map[string]interface{}) string {
+	return placeholder.ReplaceAllStringFunc(tmpl, func(m string) string {
+		parts := placeholder.FindStringSubmatch(m)
+		v, ok := params[parts[1]]
+		if !ok {
+			return m
+		}
+		if parts[2] != "" {
+			return fmt.Sprintf(parts[2], v)
+		}
+		return fmt.Sprint(v)
+	})
+}
+
+// Parse 將語系標籤 (如 "en-US"、"zh-Hant-TW") 對應到支援的語系，無法辨識時回傳預設語系
+func Parse(tag string) Locale {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case strings.HasPrefix(tag, "en"):
+		return En
+	case strings.HasPrefix(tag, "zh"):
+		return ZhTW
+	default:
+		return DefaultLocale
+	}
+}
+
+// ParseAcceptLanguage 解析 HTTP Accept-Language 標頭，依 q 值選出第一個支援的語系
+func ParseAcceptLanguage(header string) Locale {
+	best, bestQ := DefaultLocale, -1.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if !strings.HasPrefix(tag, "en") && !strings.HasPrefix(tag, "zh") {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				fmt.Sscanf(f[2:], "%g", &q)
+			}
+		}
+		if q > bestQ {
+			best, bestQ = Parse(tag), q
+		}
+	}
+	return best
+}
@@ -1,12 +1,53 @@
 package persistence
 
 import (
+	"system-design-game/internal/domain/catalog"
 	"system-design-game/internal/domain/component"
 )
 
-// ListAvailableComponents 回傳目前遊戲中可選用的組件列表及其基礎屬性
-func ListAvailableComponents() []component.Component {
-	return []component.Component{
+// 各 SKU 允許玩家調整的升級選項 (嚴格模式下的上限)
+var (
+	trafficOptions = []catalog.Option{
+		{Property: "start_qps", Max: component.Bound(100000)},
+		{Property: "read_ratio"},
+		{Property: "burst_traffic"},
+		{Property: "enable_attacks"},
+		{Property: "enable_failures"},
+	}
+	serverOptions = autoScalingOptions(10)
+	asgOptions    = autoScalingOptions(20)
+	dbOptions     = []catalog.Option{
+		{Property: "replication_mode"},
+		{Property: "slave_count", Max: component.Bound(5)},
+		reservedOption,
+	}
+	replicationOptions = []catalog.Option{{Property: "replication_mode"}, reservedOption}
+	queueOptions       = []catalog.Option{{Property: "delivery_mode"}, {Property: "backlog_alert"}}
+	// 資料節點只能預留容量，運算節點才能使用會被回收的 spot
+	reservedOption = catalog.Option{Property: "pricing", Enum: []string{"on_demand", "reserved"}}
+)
+
+func autoScalingOptions(maxReplicas float64) []catalog.Option {
+	return []catalog.Option{
+		{Property: "auto_scaling"},
+		{Property: "max_replicas", Max: component.Bound(maxReplicas)},
+		{Property: "scale_up_threshold"},
+		{Property: "warmup_seconds", Min: component.Bound(5)}, // 暖機時間不可低於 5 秒
+		{Property: "scale_metric"},
+		{Property: "pricing"},
+	}
+}
+
+// ListAvailableComponents 回傳目前遊戲中可選用的組件規格 (SKU) 及其成本與基礎屬性
+// 每種組件類型至少有一個 SKU，數值與前端元件庫一致
+func ListAvailableComponents() []catalog.SKU {
+	return []catalog.SKU{
+		{
+			ID:      "traffic-source",
+			Name:    "Traffic Source",
+			Type:    component.TrafficSource,
+			Options: trafficOptions,
+		},
 		{
 			ID:              "server-nano",
 			Name:            "Nano Server",
@@ -17,6 +58,7 @@ func ListAvailableComponents() []component.Component {
 				"max_qps":      200,
 				"base_latency": 100, // 性能較差，延遲較高
 			},
+			Options: serverOptions,
 		},
 		{
 			ID:              "server-standard",
@@ -28,6 +70,7 @@ func ListAvailableComponents() []component.Component {
 				"max_qps":      1000,
 				"base_latency": 50,
 			},
+			Options: serverOptions,
 		},
 		{
 			ID:              "server-high-perf",
@@ -39,6 +82,28 @@ func ListAvailableComponents() []component.Component {
 				"max_qps":      5000,
 				"base_latency": 20,
 			},
+			Options: serverOptions,
+		},
+		{
+			ID:              "asg-standard",
+			Name:            "Auto Scaling Group",
+			Type:            component.AutoScalingGroup,
+			OperationalCost: 0.30,
+			Properties: component.Metadata{
+				"max_qps": 1000, // 單一節點的容量，副本數由 Auto Scaling 設定決定
+			},
+			Options: asgOptions,
+		},
+		{
+			ID:              "worker-standard",
+			Name:            "Worker",
+			Type:            component.Worker,
+			SetupCost:       100,
+			OperationalCost: 0.10,
+			Properties: component.Metadata{
+				"max_qps":      500,
+				"base_latency": 50,
+			},
 		},
 		{
 			ID:              "lb-simple",
@@ -46,9 +111,121 @@ func ListAvailableComponents() []component.Component {
 			Type:            component.LoadBalancer,
 			SetupCost:       150,
 			OperationalCost: 0.10,
+			Properties: component.Metadata{
+				"max_qps":      20000,
+				"base_latency": 5,
+			},
+		},
+		{
+			ID:              "api-gateway",
+			Name:            "API Gateway",
+			Type:            component.APIGateway,
+			OperationalCost: 0.15,
+			Properties: component.Metadata{
+				"max_qps":      50000,
+				"base_latency": 2,
+			},
+		},
+		{
+			ID:   "cdn-global",
+			Name: "Global CDN",
+			Type: component.CDN,
+			Properties: component.Metadata{
+				"max_qps": 50000,
+			},
+		},
+		{
+			ID:   "waf-standard",
+			Name: "WAF",
+			Type: component.WAF,
 			Properties: component.Metadata{
 				"max_qps": 20000,
 			},
 		},
+		{
+			ID:              "db-postgres",
+			Name:            "SQL Database (PostgreSQL)",
+			Type:            component.Database,
+			SetupCost:       500,
+			OperationalCost: 0.50,
+			Properties: component.Metadata{
+				"max_qps":      2000,
+				"base_latency": 50,
+			},
+			Options: dbOptions,
+		},
+		{
+			ID:              "nosql-mongo",
+			Name:            "NoSQL (MongoDB)",
+			Type:            component.NoSQL,
+			SetupCost:       400,
+			OperationalCost: 0.40,
+			Properties: component.Metadata{
+				"max_qps":      10000,
+				"base_latency": 10,
+			},
+			Options: replicationOptions,
+		},
+		{
+			ID:   "object-storage-s3",
+			Name: "Object Storage (S3)",
+			Type: component.ObjectStorage,
+			Properties: component.Metadata{
+				"max_qps": 100000,
+			},
+			Options: replicationOptions,
+		},
+		{
+			ID:   "search-elasticsearch",
+			Name: "Search Engine (Elasticsearch)",
+			Type: component.SearchEngine,
+			Properties: component.Metadata{
+				"max_qps": 2000,
+			},
+			Options: replicationOptions,
+		},
+		{
+			ID:              "cache-redis",
+			Name:            "Redis Cache",
+			Type:            component.Cache,
+			OperationalCost: 0.30,
+			Properties: component.Metadata{
+				"max_qps":      20000,
+				"base_latency": 1,
+			},
+		},
+		{
+			ID:              "mq-kafka",
+			Name:            "Message Queue (Kafka)",
+			Type:            component.MessageQueue,
+			OperationalCost: 0.40,
+			Properties: component.Metadata{
+				"max_qps":      10000,
+				"base_latency": 200,
+			},
+			Options: queueOptions,
+		},
+		{
+			ID:              "video-transcoder",
+			Name:            "Video Transcoding Service",
+			Type:            component.VideoTranscoding,
+			SetupCost:       1000,
+			OperationalCost: 1.50,
+			Properties: component.Metadata{
+				"max_qps":      100,
+				"base_latency": 5000,
+			},
+		},
+		{
+			ID:              "external-api",
+			Name:            "External API (Third Party)",
+			Type:            component.ExternalAPI,
+			OperationalCost: 0.10,
+			Properties: component.Metadata{
+				"max_qps":      1000,
+				"base_latency": 200,
+				"sla":          99.9, // 第三方服務的成功率由供應商決定
+			},
+		},
 	}
 }
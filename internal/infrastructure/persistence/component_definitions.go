@@ -0,0 +1,16 @@
+package persistence
+
+import (
+	"fmt"
+	"system-design-game/internal/domain/component"
+)
+
+// LoadComponentDefinitions 從檔案或目錄讀取自訂組件定義 (.json、.yaml、.yml)
+// 每個檔案可以是單一定義或定義陣列；目錄依檔名排序讀取
+func LoadComponentDefinitions(path string) ([]component.Definition, error) {
+	defs, err := loadDataFiles[component.Definition](path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取自訂組件定義 %s 失敗: %w", path, err)
+	}
+	return defs, nil
+}
@@ -0,0 +1,56 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"system-design-game/internal/domain/component"
+	"testing"
+)
+
+// 目錄中的 YAML (單一定義) 與 JSON (定義陣列) 依檔名排序讀取
+func TestLoadComponentDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"01-gateway.yaml": "type: GraphQLGateway\nname: GraphQL Gateway\nmax_qps: 2000\nlatency_ms: 8\noperational_cost: 0.2\nread_absorption: 0.3\n",
+		"02-more.json":    `[{"type": "VectorDB", "max_qps": 500, "data_sink": true, "sensitive": true}]`,
+		"notes.txt":       "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defs, err := LoadComponentDefinitions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 2 {
+		t.Fatalf("loaded %d definitions, want 2", len(defs))
+	}
+	gw := defs[0]
+	if gw.Type != "GraphQLGateway" || gw.MaxQPS != 2000 || gw.LatencyMS != 8 || gw.ReadAbsorption != 0.3 {
+		t.Errorf("gateway = %+v", gw)
+	}
+	if vdb := defs[1]; vdb.Type != "VectorDB" || !vdb.DataSink || !vdb.Sensitive {
+		t.Errorf("vector db = %+v", vdb)
+	}
+	for _, d := range defs {
+		if err := d.Validate(); err != nil {
+			t.Errorf("%s: %v", d.Type, err)
+		}
+	}
+
+	// 載入不檢查內建類型，由註冊時的 Validate 拒絕
+	builtin := filepath.Join(dir, "builtin.yaml")
+	if err := os.WriteFile(builtin, []byte("type: DATABASE\nmax_qps: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defs, err = LoadComponentDefinitions(builtin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 1 || defs[0].Type != component.Database || defs[0].Validate() == nil {
+		t.Errorf("built-in definition = %+v, want it loaded and rejected by Validate", defs)
+	}
+}
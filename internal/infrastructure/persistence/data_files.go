@@ -0,0 +1,85 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// 自訂組件與關卡共用的資料檔讀取：支援 .json、.yaml、.yml，
+// 每個檔案可以是單一項目或項目陣列，目錄依檔名排序讀取
+
+// loadDataFiles 從檔案或目錄讀取所有項目
+func loadDataFiles[T any](path string) ([]T, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return readDataFile[T](os.DirFS(filepath.Dir(path)), filepath.Base(path))
+	}
+	return loadDataFS[T](os.DirFS(path), ".")
+}
+
+// loadDataFS 讀取檔案系統中某個目錄下的所有資料檔 (如 go:embed 內嵌的目錄)
+func loadDataFS[T any](fsys fs.FS, dir string) ([]T, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && isDataFile(e.Name()) {
+			files = append(files, path.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	var items []T
+	for _, f := range files {
+		fileItems, err := readDataFile[T](fsys, f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		items = append(items, fileItems...)
+	}
+	return items, nil
+}
+
+func isDataFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+func readDataFile[T any](fsys fs.FS, name string) ([]T, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarshal := yaml.Unmarshal
+	if strings.ToLower(filepath.Ext(name)) == ".json" {
+		unmarshal = json.Unmarshal
+	}
+
+	// 先嘗試陣列，失敗時再視為單一項目
+	var items []T
+	if err := unmarshal(data, &items); err == nil {
+		return items, nil
+	}
+	var item T
+	if err := unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	return []T{item}, nil
+}
@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"system-design-game/internal/domain/design"
+)
+
+// FileDesignRepository 以 JSON 檔保存設計圖，伺服器重啟後資料仍然存在
+type FileDesignRepository struct {
+	dir *jsonDir
+}
+
+func NewFileDesignRepository(path string) (*FileDesignRepository, error) {
+	dir, err := newJSONDir(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileDesignRepository{dir: dir}, nil
+}
+
+func (r *FileDesignRepository) Save(d *design.Design) error {
+	return r.dir.write(d.ID, d)
+}
+
+func (r *FileDesignRepository) GetByID(id string) (*design.Design, error) {
+	var d design.Design
+	found, err := r.dir.read(id, &d)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", design.ErrNotFound, id)
+	}
+	return &d, nil
+}
+
+func (r *FileDesignRepository) ListByPlayerID(playerID string) ([]*design.Design, error) {
+	var result []*design.Design
+	err := r.dir.each(func(data []byte) error {
+		var d design.Design
+		if err := json.Unmarshal(data, &d); err != nil {
+			return err
+		}
+		if d.PlayerID == playerID {
+			result = append(result, &d)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (r *FileDesignRepository) Delete(id string) error {
+	found, err := r.dir.remove(id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", design.ErrNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"system-design-game/internal/domain/leaderboard"
+)
+
+// FileLeaderboardRepository 以 JSON 檔保存排行榜，每位玩家在每個關卡一個檔案
+type FileLeaderboardRepository struct {
+	dir *jsonDir
+}
+
+func NewFileLeaderboardRepository(path string) (*FileLeaderboardRepository, error) {
+	dir, err := newJSONDir(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileLeaderboardRepository{dir: dir}, nil
+}
+
+// entryID 是成績的檔名 (經 jsonDir 跳脫，「/」不會產生子目錄)
+func entryID(scenarioID, playerID string) string {
+	return scenarioID + "/" + playerID
+}
+
+func (r *FileLeaderboardRepository) Save(entry *leaderboard.Entry) error {
+	return r.dir.write(entryID(entry.ScenarioID, entry.PlayerID), entry)
+}
+
+func (r *FileLeaderboardRepository) Get(scenarioID, playerID string) (*leaderboard.Entry, error) {
+	var e leaderboard.Entry
+	found, err := r.dir.read(entryID(scenarioID, playerID), &e)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s/%s", leaderboard.ErrNotFound, scenarioID, playerID)
+	}
+	return &e, nil
+}
+
+func (r *FileLeaderboardRepository) ListByScenario(scenarioID string) ([]*leaderboard.Entry, error) {
+	list := []*leaderboard.Entry{}
+	err := r.dir.each(func(data []byte) error {
+		var e leaderboard.Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return err
+		}
+		if e.ScenarioID == scenarioID {
+			list = append(list, &e)
+		}
+		return nil
+	})
+	return list, err
+}
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"system-design-game/internal/domain/scenario"
+)
+
+// FileScenarioRepository 以 JSON 檔保存關卡，講師可以直接在資料目錄中新增或修改關卡
+// 目錄為空時會寫入內建關卡
+type FileScenarioRepository struct {
+	dir *jsonDir
+}
+
+func NewFileScenarioRepository(path string) (*FileScenarioRepository, error) {
+	dir, err := newJSONDir(path)
+	if err != nil {
+		return nil, err
+	}
+	repo := &FileScenarioRepository{dir: dir}
+
+	isEmpty, err := dir.empty()
+	if err != nil {
+		return nil, err
+	}
+	if isEmpty {
+		for _, s := range BuiltinScenarios() {
+			if err := dir.write(s.ID, s); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return repo, nil
+}
+
+func (r *FileScenarioRepository) Save(s *scenario.Scenario) error {
+	return r.dir.write(s.ID, s)
+}
+
+func (r *FileScenarioRepository) GetByID(id string) (*scenario.Scenario, error) {
+	var s scenario.Scenario
+	found, err := r.dir.read(id, &s)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", scenario.ErrNotFound, id)
+	}
+	return &s, nil
+}
+
+func (r *FileScenarioRepository) ListAll() ([]*scenario.Scenario, error) {
+	var result []*scenario.Scenario
+	err := r.dir.each(func(data []byte) error {
+		var s scenario.Scenario
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		result = append(result, &s)
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,143 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// jsonDir 以「一筆資料一個 JSON 檔」的方式將資料保存在目錄中 (純 Go、不需外部資料庫)
+// 寫入時先寫到暫存檔再 rename，避免程序中斷時留下寫到一半的檔案
+type jsonDir struct {
+	mu   sync.RWMutex
+	path string
+}
+
+func newJSONDir(path string) (*jsonDir, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, err
+	}
+	return &jsonDir{path: path}, nil
+}
+
+// file 回傳 ID 對應的檔案路徑，ID 會經過跳脫，不會跳出資料目錄
+func (d *jsonDir) file(id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("ID 不可為空")
+	}
+	return filepath.Join(d.path, url.PathEscape(id)+".json"), nil
+}
+
+func (d *jsonDir) write(id string, v interface{}) error {
+	path, err := d.file(id)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tmp, err := os.CreateTemp(d.path, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// read 讀取 ID 對應的資料，檔案不存在時回傳 found = false
+func (d *jsonDir) read(id string, v interface{}) (found bool, err error) {
+	path, err := d.file(id)
+	if err != nil {
+		return false, err
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("解析 %s 失敗: %w", path, err)
+	}
+	return true, nil
+}
+
+// remove 刪除 ID 對應的資料，檔案不存在時回傳 found = false
+func (d *jsonDir) remove(id string) (found bool, err error) {
+	path, err := d.file(id)
+	if err != nil {
+		return false, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// each 依檔名順序讀取目錄中所有的資料
+func (d *jsonDir) each(fn func(data []byte) error) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(d.path, name))
+		if err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return fmt.Errorf("解析 %s 失敗: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// empty 判斷目錄中是否還沒有任何資料
+func (d *jsonDir) empty() (bool, error) {
+	isEmpty := true
+	err := d.each(func([]byte) error {
+		isEmpty = false
+		return nil
+	})
+	return isEmpty, err
+}
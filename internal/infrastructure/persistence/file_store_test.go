@@ -0,0 +1,241 @@
+package persistence
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"system-design-game/internal/domain/component"
+	"system-design-game/internal/domain/design"
+	"system-design-game/internal/domain/leaderboard"
+	"system-design-game/internal/domain/scenario"
+	"system-design-game/internal/domain/world"
+	"testing"
+)
+
+type record struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// dirNames 列出目錄中的所有檔名 (含暫存檔)
+func dirNames(t *testing.T, path string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+// 寫入經過暫存檔後 rename，完成後目錄中只剩正式的檔案，覆寫後讀到的是新內容
+func TestJSONDirWriteReplacesAtomically(t *testing.T) {
+	dir, err := newJSONDir(filepath.Join(t.TempDir(), "records"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dir.write("a", record{Name: "a", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dir.write("a", record{Name: "a", Value: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if names := dirNames(t, dir.path); !reflect.DeepEqual(names, []string{"a.json"}) {
+		t.Errorf("files = %v, want only a.json (no leftover temp files)", names)
+	}
+
+	var got record
+	if found, err := dir.read("a", &got); err != nil || !found || got.Value != 2 {
+		t.Errorf("read = %+v found=%v err=%v, want value 2", got, found, err)
+	}
+	info, err := os.Stat(filepath.Join(dir.path, "a.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o644 {
+		t.Errorf("permissions = %o, want 644", perm)
+	}
+
+	// 暫存檔 (非 .json) 不會被當成資料讀取
+	if err := os.WriteFile(filepath.Join(dir.path, ".tmp-123"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	if err := dir.each(func([]byte) error { count++; return nil }); err != nil || count != 1 {
+		t.Errorf("each visited %d records (err %v), want 1", count, err)
+	}
+}
+
+// ID 經過跳脫，含路徑分隔符或 .. 的 ID 不會跳出資料目錄
+func TestJSONDirEscapesIDs(t *testing.T) {
+	root := t.TempDir()
+	dir, err := newJSONDir(filepath.Join(root, "records"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"../x", "a/b", "..", "空 白"} {
+		if err := dir.write(id, record{Name: id}); err != nil {
+			t.Fatalf("write %q: %v", id, err)
+		}
+		var got record
+		if found, err := dir.read(id, &got); err != nil || !found || got.Name != id {
+			t.Errorf("read %q = %+v found=%v err=%v", id, got, found, err)
+		}
+	}
+	if names := dirNames(t, root); !reflect.DeepEqual(names, []string{"records"}) {
+		t.Errorf("files outside the data directory: %v", names)
+	}
+	for _, name := range dirNames(t, dir.path) {
+		if info, err := os.Stat(filepath.Join(dir.path, name)); err != nil || info.IsDir() {
+			t.Errorf("%s should be a file in the data directory", name)
+		}
+	}
+
+	if found, err := dir.remove("../x"); err != nil || !found {
+		t.Errorf("remove = %v, %v", found, err)
+	}
+	if found, err := dir.remove("../x"); err != nil || found {
+		t.Errorf("second remove = %v, %v; want not found", found, err)
+	}
+	if err := dir.write("", record{}); err == nil {
+		t.Error("an empty ID should be rejected")
+	}
+}
+
+func TestFileDesignRepository(t *testing.T) {
+	repo, err := NewFileDesignRepository(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &design.Design{
+		ID: "player/1", PlayerID: "alice", ScenarioID: "tinyurl",
+		Properties:  component.Metadata{"seed": float64(42)},
+		Components:  []component.Component{{ID: "web", Name: "Web", Type: component.WebServer, Properties: component.Metadata{"max_qps": float64(1000)}}},
+		Connections: []design.Connection{{FromID: "src", ToID: "web"}},
+	}
+	other := &design.Design{ID: "other", PlayerID: "bob"}
+	for _, x := range []*design.Design{d, other} {
+		if err := repo.Save(x); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := repo.GetByID("player/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("round trip = %+v, want %+v", got, d)
+	}
+	list, err := repo.ListByPlayerID("alice")
+	if err != nil || len(list) != 1 || list[0].ID != "player/1" {
+		t.Errorf("ListByPlayerID = %v, %v", list, err)
+	}
+
+	if err := repo.Delete("player/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID("player/1"); !errors.Is(err, design.ErrNotFound) {
+		t.Errorf("GetByID after delete = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete("player/1"); !errors.Is(err, design.ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileScenarioRepository(t *testing.T) {
+	path := t.TempDir()
+	repo, err := NewFileScenarioRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtin, err := repo.ListAll()
+	if err != nil || len(builtin) != len(BuiltinScenarios()) {
+		t.Fatalf("a new directory should be seeded with the built-in scenarios, got %d (%v)", len(builtin), err)
+	}
+
+	s := &scenario.Scenario{
+		ID: "custom", Title: "Custom",
+		Goal:   scenario.Goal{Duration: 60, MinQPS: 100},
+		Phases: []scenario.TrafficPhase{{Name: "steady", StartQPS: 100, EndQPS: 200, DurationSeconds: 60}},
+	}
+	if err := repo.Save(s); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重新開啟既有目錄時不會再寫入內建關卡，保存的關卡仍然存在
+	reopened, err := NewFileScenarioRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopened.GetByID("custom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("round trip = %+v, want %+v", got, s)
+	}
+	if all, _ := reopened.ListAll(); len(all) != len(builtin)+1 {
+		t.Errorf("ListAll = %d scenarios, want %d", len(all), len(builtin)+1)
+	}
+	if _, err := reopened.GetByID("missing"); !errors.Is(err, scenario.ErrNotFound) {
+		t.Errorf("GetByID(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileWorldRepository(t *testing.T) {
+	repo, err := NewFileWorldRepository(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &world.GameState{PlayerID: "../alice", Balance: 1234.5, TotalUsers: 42, SystemHealth: 87.5, Uptime: 90.5, LastTick: 1700000000}
+	if err := repo.Save(state); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.GetByPlayerID("../alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, state) {
+		t.Errorf("round trip = %+v, want %+v", got, state)
+	}
+	if _, err := repo.GetByPlayerID("bob"); !errors.Is(err, world.ErrNotFound) {
+		t.Errorf("GetByPlayerID(bob) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileLeaderboardRepository(t *testing.T) {
+	repo, err := NewFileLeaderboardRepository(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []*leaderboard.Entry{
+		{ScenarioID: "tinyurl", PlayerID: "a/b", DesignID: "d1", TotalScore: 97.5, Passed: true, CostPerSec: 1.25, P95LatencyMS: 80, ComponentCount: 3, Seed: 7, SubmittedAt: 100},
+		{ScenarioID: "tinyurl", PlayerID: "a", DesignID: "d2", TotalScore: 60},
+		{ScenarioID: "flash-sale", PlayerID: "a/b", DesignID: "d3", TotalScore: 80},
+	}
+	for _, e := range entries {
+		if err := repo.Save(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := repo.Get("tinyurl", "a/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, entries[0]) {
+		t.Errorf("round trip = %+v, want %+v", got, entries[0])
+	}
+	list, err := repo.ListByScenario("tinyurl")
+	if err != nil || len(list) != 2 {
+		t.Errorf("ListByScenario = %d entries (%v), want 2", len(list), err)
+	}
+	if _, err := repo.Get("tinyurl", "nobody"); !errors.Is(err, leaderboard.ErrNotFound) {
+		t.Errorf("Get(nobody) = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,35 @@
+package persistence
+
+import (
+	"fmt"
+	"system-design-game/internal/domain/world"
+)
+
+// FileWorldRepository 以 JSON 檔保存每位玩家的遊戲狀態
+type FileWorldRepository struct {
+	dir *jsonDir
+}
+
+func NewFileWorldRepository(path string) (*FileWorldRepository, error) {
+	dir, err := newJSONDir(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileWorldRepository{dir: dir}, nil
+}
+
+func (r *FileWorldRepository) Save(state *world.GameState) error {
+	return r.dir.write(state.PlayerID, state)
+}
+
+func (r *FileWorldRepository) GetByPlayerID(playerID string) (*world.GameState, error) {
+	var s world.GameState
+	found, err := r.dir.read(playerID, &s)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", world.ErrNotFound, playerID)
+	}
+	return &s, nil
+}
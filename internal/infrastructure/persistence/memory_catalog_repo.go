@@ -0,0 +1,41 @@
+package persistence
+
+import (
+	"fmt"
+	"system-design-game/internal/domain/catalog"
+)
+
+// InMemCatalogRepository 記憶體實作的組件目錄，內容來自 ListAvailableComponents
+type InMemCatalogRepository struct {
+	skus  map[string]*catalog.SKU
+	order []string
+}
+
+func NewInMemCatalogRepository() *InMemCatalogRepository {
+	repo := &InMemCatalogRepository{
+		skus: make(map[string]*catalog.SKU),
+	}
+	for _, sku := range ListAvailableComponents() {
+		sku := sku
+		repo.skus[sku.ID] = &sku
+		repo.order = append(repo.order, sku.ID)
+	}
+	return repo
+}
+
+func (r *InMemCatalogRepository) GetByID(id string) (*catalog.SKU, error) {
+	sku, ok := r.skus[id]
+	if !ok {
+		return nil, fmt.Errorf("sku not found: %s", id)
+	}
+	return sku, nil
+}
+
+// ListAll 依目錄定義的順序回傳所有 SKU
+func (r *InMemCatalogRepository) ListAll() ([]*catalog.SKU, error) {
+	out := make([]*catalog.SKU, 0, len(r.order))
+	for _, id := range r.order {
+		out = append(out, r.skus[id])
+	}
+	return out, nil
+}
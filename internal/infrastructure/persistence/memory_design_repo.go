@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"system-design-game/internal/domain/design"
 )
@@ -30,7 +31,7 @@ func (r *InMemDesignRepository) GetByID(id string) (*design.Design, error) {
 	defer r.mu.RUnlock()
 	d, ok := r.designs[id]
 	if !ok {
-		return nil, fmt.Errorf("design not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", design.ErrNotFound, id)
 	}
 	return d, nil
 }
@@ -44,5 +45,16 @@ func (r *InMemDesignRepository) ListByPlayerID(playerID string) ([]*design.Desig
 			result = append(result, d)
 		}
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
 	return result, nil
 }
+
+func (r *InMemDesignRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.designs[id]; !ok {
+		return fmt.Errorf("%w: %s", design.ErrNotFound, id)
+	}
+	delete(r.designs, id)
+	return nil
+}
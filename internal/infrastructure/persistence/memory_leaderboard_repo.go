@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"system-design-game/internal/domain/leaderboard"
+)
+
+// InMemLeaderboardRepository 記憶體實作的排行榜 Repository
+type InMemLeaderboardRepository struct {
+	mu      sync.RWMutex
+	entries map[string]map[string]*leaderboard.Entry // scenarioID -> playerID -> 成績
+}
+
+func NewInMemLeaderboardRepository() *InMemLeaderboardRepository {
+	return &InMemLeaderboardRepository{
+		entries: make(map[string]map[string]*leaderboard.Entry),
+	}
+}
+
+func (r *InMemLeaderboardRepository) Save(entry *leaderboard.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	players, ok := r.entries[entry.ScenarioID]
+	if !ok {
+		players = make(map[string]*leaderboard.Entry)
+		r.entries[entry.ScenarioID] = players
+	}
+	players[entry.PlayerID] = entry
+	return nil
+}
+
+func (r *InMemLeaderboardRepository) Get(scenarioID, playerID string) (*leaderboard.Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.entries[scenarioID][playerID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", leaderboard.ErrNotFound, scenarioID, playerID)
+	}
+	return e, nil
+}
+
+// ListByScenario 回傳關卡的所有成績 (依玩家 ID 排序，排名由 leaderboard.Rank 決定)
+func (r *InMemLeaderboardRepository) ListByScenario(scenarioID string) ([]*leaderboard.Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]*leaderboard.Entry, 0, len(r.entries[scenarioID]))
+	for _, e := range r.entries[scenarioID] {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].PlayerID < list[j].PlayerID })
+	return list, nil
+}
@@ -2,115 +2,53 @@ package persistence
 
 import (
 	"fmt"
+	"sync"
 	"system-design-game/internal/domain/scenario"
 )
 
-// InMemScenarioRepository 記憶體實作的 Scenario Repository
+// InMemScenarioRepository 記憶體實作的 Scenario Repository，啟動時載入內建關卡
 type InMemScenarioRepository struct {
+	mu        sync.RWMutex
 	scenarios map[string]*scenario.Scenario
+	order     []string // 依加入順序列出關卡
 }
 
 func NewInMemScenarioRepository() *InMemScenarioRepository {
 	repo := &InMemScenarioRepository{
 		scenarios: make(map[string]*scenario.Scenario),
 	}
-	repo.initMockData()
+	for _, s := range BuiltinScenarios() {
+		repo.Save(s)
+	}
 	return repo
 }
 
-func (r *InMemScenarioRepository) initMockData() {
-	s1 := &scenario.Scenario{
-		ID:          "tinyurl",
-		Title:       "短網址系統 (TinyURL)",
-		Description: "設計一個高讀取的短網址系統。挑戰：在極低預算下處理 100k 的跳轉請求，必須善用 Cache。",
-		Goal: scenario.Goal{
-			MinQPS:       100000,
-			MaxLatencyMS: 50,
-			Availability: 99.9,
-			Duration:     300,
-		},
-		Phases: []scenario.TrafficPhase{
-			{Name: "穩定讀取增長", StartQPS: 1000, EndQPS: 100000, DurationSeconds: 300},
-		},
-		Constraints: []scenario.Constraint{
-			{Type: "budget", Value: 10}, // 極低預算挑戰 ($10/sec)
-		},
-	}
-
-	s2 := &scenario.Scenario{
-		ID:          "flash-sale",
-		Title:       "快閃搶購 (Flash Sale)",
-		Description: "雙 11 搶購活動。挑戰：在 10 秒內應對從 0 到 500k 的突發流量，需使用 MQ 消峰填谷。",
-		Goal: scenario.Goal{
-			MinQPS:       500000,
-			MaxLatencyMS: 500,
-			Availability: 95.0,
-			Duration:     60,
-		},
-		Phases: []scenario.TrafficPhase{
-			{Name: "熱身", StartQPS: 100, EndQPS: 1000, DurationSeconds: 30},
-			{Name: "開賣瞬間", StartQPS: 1000, EndQPS: 500000, DurationSeconds: 10},
-			{Name: "餘溫", StartQPS: 500000, EndQPS: 10000, DurationSeconds: 20},
-		},
-		Constraints: []scenario.Constraint{
-			{Type: "budget", Value: 30}, // 中等預算
-		},
-	}
-
-	s3 := &scenario.Scenario{
-		ID:          "video-platform",
-		Title:       "影音串流 (Netflix/YouTube)",
-		Description: "全球化的影音平台。挑戰：降低跨國延遲，提升內容分發效率，需善用 CDN 與 Object Storage。",
-		Goal: scenario.Goal{
-			MinQPS:       50000,
-			MaxLatencyMS: 20,
-			Availability: 99.99,
-			Duration:     600,
-		},
-		Phases: []scenario.TrafficPhase{
-			{Name: "全球高峰", StartQPS: 5000, EndQPS: 50000, DurationSeconds: 600},
-		},
-		Constraints: []scenario.Constraint{
-			{Type: "budget", Value: 100}, // 較高預算，但 CDN 很貴
-		},
-	}
-
-	s4 := &scenario.Scenario{
-		ID:          "sandbox",
-		Title:       "自由沙盒 (Sandbox Mode)",
-		Description: "無目標限制的無盡模式。流量會隨時間持續緩慢增長，適合用來測試任何瘋狂的架構想法。",
-		Goal: scenario.Goal{
-			MinQPS:       1,
-			MaxLatencyMS: 5000,
-			Availability: 0.5,
-			Duration:     3600,
-		},
-		Phases: []scenario.TrafficPhase{
-			{Name: "無限增長", StartQPS: 100, EndQPS: 100000, DurationSeconds: 3600},
-		},
-		Constraints: []scenario.Constraint{
-			{Type: "budget", Value: 999999}, // 近乎無限的預算
-		},
+func (r *InMemScenarioRepository) Save(s *scenario.Scenario) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.scenarios[s.ID]; !ok {
+		r.order = append(r.order, s.ID)
 	}
-
-	r.scenarios[s1.ID] = s1
-	r.scenarios[s2.ID] = s2
-	r.scenarios[s3.ID] = s3
-	r.scenarios[s4.ID] = s4
+	r.scenarios[s.ID] = s
+	return nil
 }
 
 func (r *InMemScenarioRepository) GetByID(id string) (*scenario.Scenario, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	s, ok := r.scenarios[id]
 	if !ok {
-		return nil, fmt.Errorf("scenario not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", scenario.ErrNotFound, id)
 	}
 	return s, nil
 }
 
 func (r *InMemScenarioRepository) ListAll() ([]*scenario.Scenario, error) {
-	var result []*scenario.Scenario
-	for _, s := range r.scenarios {
-		result = append(result, s)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*scenario.Scenario, 0, len(r.order))
+	for _, id := range r.order {
+		result = append(result, r.scenarios[id])
 	}
 	return result, nil
 }
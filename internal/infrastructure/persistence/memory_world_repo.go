@@ -0,0 +1,36 @@
+package persistence
+
+import (
+	"fmt"
+	"sync"
+	"system-design-game/internal/domain/world"
+)
+
+// InMemWorldRepository 記憶體實作的遊戲狀態 Repository
+type InMemWorldRepository struct {
+	mu     sync.RWMutex
+	states map[string]*world.GameState
+}
+
+func NewInMemWorldRepository() *InMemWorldRepository {
+	return &InMemWorldRepository{
+		states: make(map[string]*world.GameState),
+	}
+}
+
+func (r *InMemWorldRepository) Save(state *world.GameState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.PlayerID] = state
+	return nil
+}
+
+func (r *InMemWorldRepository) GetByPlayerID(playerID string) (*world.GameState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.states[playerID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", world.ErrNotFound, playerID)
+	}
+	return s, nil
+}
@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"embed"
+	"fmt"
+	"system-design-game/internal/domain/scenario"
+)
+
+// 內建關卡以 YAML 檔內嵌於執行檔 (Server、CLI 與 WASM 共用)
+//
+//go:embed scenarios/*.yaml
+var builtinScenarioFiles embed.FS
+
+// BuiltinScenarios 回傳內建關卡 (依檔名排序)
+func BuiltinScenarios() []*scenario.Scenario {
+	items, err := loadDataFS[scenario.Scenario](builtinScenarioFiles, "scenarios")
+	if err != nil {
+		panic(fmt.Sprintf("內建關卡格式錯誤: %v", err))
+	}
+	scenarios, err := validateScenarios(items)
+	if err != nil {
+		panic(err)
+	}
+	return scenarios
+}
+
+// LoadScenarios 從檔案或目錄讀取關卡 (.json、.yaml、.yml) 並逐一驗證
+// 每個檔案可以是單一關卡或關卡陣列；目錄依檔名排序讀取
+func LoadScenarios(path string) ([]*scenario.Scenario, error) {
+	items, err := loadDataFiles[scenario.Scenario](path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取關卡 %s 失敗: %w", path, err)
+	}
+	scenarios, err := validateScenarios(items)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return scenarios, nil
+}
+
+func validateScenarios(items []scenario.Scenario) ([]*scenario.Scenario, error) {
+	scenarios := make([]*scenario.Scenario, len(items))
+	for i := range items {
+		if err := items[i].Validate(); err != nil {
+			return nil, err
+		}
+		scenarios[i] = &items[i]
+	}
+	return scenarios, nil
+}